@@ -1,5 +1,7 @@
 package gophercloud
 
+import "context"
+
 /*
 AuthOptions stores information needed to authenticate to an OpenStack Cloud.
 You can populate one manually, or use a provider's AuthOptionsFromEnv() function
@@ -48,6 +50,13 @@ type AuthOptions struct {
 	// Passcode is used in TOTP authentication method
 	Passcode string `json:"passcode,omitempty"`
 
+	// Receipt is the ID of the auth receipt issued by the Identity V3 API
+	// when the user must complete additional authentication methods, as
+	// configured by multi-factor authentication rules. Set it along with the
+	// credentials of the missing methods, typically Passcode, to continue the
+	// authentication.
+	Receipt string `json:"-"`
+
 	// At most one of DomainID and DomainName must be provided if using Username
 	// with Identity V3. Otherwise, either are optional.
 	DomainID   string `json:"-"`
@@ -93,6 +102,36 @@ type AuthOptions struct {
 	ApplicationCredentialID     string `json:"-"`
 	ApplicationCredentialName   string `json:"-"`
 	ApplicationCredentialSecret string `json:"-"`
+
+	// TokenCache, if set, is used to reuse a valid token obtained earlier
+	// with the same options, possibly by another process, instead of
+	// authenticating again. Newly issued tokens are stored in it. A loaded
+	// token is first validated with the Identity service, and deleted from
+	// the cache if it has been revoked; a token rejected while in use is
+	// deleted when the client reauthenticates. Only supported with the
+	// Identity V3 API, and not with Federated authentication, for which
+	// authentication fails.
+	TokenCache TokenCache `json:"-"`
+
+	// Federated, if set, authenticates through a federated identity provider
+	// instead of with the other credentials, for example with the options of
+	// the identity/v3/oidc package. The unscoped token it obtains is then
+	// rescoped according to TenantID, TenantName and Scope. Only supported
+	// with the Identity V3 API.
+	//
+	// If Federated also has a CanReauth() bool method, the client only
+	// reauthenticates if it returns true, since some federated credentials
+	// cannot be used twice.
+	Federated FederatedAuth `json:"-"`
+}
+
+// FederatedAuth obtains an unscoped token through a federated identity
+// provider of the Identity V3 API (OS-FEDERATION).
+type FederatedAuth interface {
+	// FederatedToken authenticates with the identity provider and returns
+	// the ID of the unscoped token issued by the Identity service which
+	// client, an Identity V3 service client, points to.
+	FederatedToken(ctx context.Context, client *ServiceClient) (string, error)
 }
 
 // AuthScope allows a created token to be limited to a specific domain or project.
@@ -507,11 +546,24 @@ func (opts AuthOptions) CanReauth() bool {
 		return false
 	}
 
+	if opts.Receipt != "" {
+		// cannot reauth using an auth receipt, which expires
+		return false
+	}
+
+	if f, ok := opts.Federated.(interface{ CanReauth() bool }); ok && !f.CanReauth() {
+		// cannot reauth using single-use federated credentials
+		return false
+	}
+
 	return opts.AllowReauth
 }
 
 // ToTokenV3HeadersMap allows AuthOptions to satisfy the AuthOptionsBuilder
 // interface in the v3 tokens package.
 func (opts *AuthOptions) ToTokenV3HeadersMap(map[string]any) (map[string]string, error) {
-	return nil, nil
+	if opts.Receipt == "" {
+		return nil, nil
+	}
+	return map[string]string{"Openstack-Auth-Receipt": opts.Receipt}, nil
 }
@@ -1,5 +1,7 @@
 package gophercloud
 
+import "time"
+
 /*
 AuthResult is the result from the request that was used to obtain a provider
 client's Keystone token. It is returned from ProviderClient.GetAuthResult().
@@ -50,3 +52,14 @@ type tokens3.User).
 type AuthResult interface {
 	ExtractTokenID() (string, error)
 }
+
+// ExpiringAuthResult is an AuthResult which also reports when the token it
+// carries expires. The CreateResult and GetResult types of both the v2 and v3
+// identity tokens packages satisfy this interface.
+//
+// The expiry is used by the ProviderClient to renew its token before it
+// expires, see ProviderClient.TokenRenewalWindow.
+type ExpiringAuthResult interface {
+	AuthResult
+	ExtractExpiresAt() (time.Time, error)
+}
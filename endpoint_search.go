@@ -94,6 +94,18 @@ type EndpointOpts struct {
 	// Availability is not required, and defaults to AvailabilityPublic. Not all
 	// providers or services offer all Availability options.
 	Availability Availability
+
+	// MaxMicroversion [optional] enables microversion negotiation for service
+	// clients that support microversions. When set, the service client is
+	// created with the highest microversion supported by the service that is
+	// not higher than MaxMicroversion, and it is an error if there is none.
+	// Use "latest" to pick the highest microversion supported by the service.
+	// The negotiated microversion is recorded in ServiceClient.Microversion.
+	// It is ignored by the clients of services without microversions, and,
+	// unless it is "latest", by the clients of services whose microversions
+	// have another major version: "2.90" applies to compute but not to
+	// block-storage, whose microversions are 3.x.
+	MaxMicroversion string
 }
 
 /*
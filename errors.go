@@ -86,6 +86,11 @@ type ErrUnexpectedResponseCode struct {
 	Actual         int
 	Body           []byte
 	ResponseHeader http.Header
+	// Fault is the error reported by the service in the response body, or
+	// nil if the body does not contain a recognized fault.
+	Fault *Fault
+	// RequestID is the ID assigned to the request by the service, if any.
+	RequestID string
 }
 
 func (e ErrUnexpectedResponseCode) Error() string {
@@ -96,6 +101,15 @@ func (e ErrUnexpectedResponseCode) Error() string {
 	return e.choseErrString()
 }
 
+// Unwrap returns the Fault reported by the service, if any, so that it can be
+// retrieved with errors.As.
+func (e ErrUnexpectedResponseCode) Unwrap() error {
+	if e.Fault == nil {
+		return nil
+	}
+	return e.Fault
+}
+
 // GetStatusCode returns the actual status code of the error.
 func (e ErrUnexpectedResponseCode) GetStatusCode() int {
 	return e.Actual
@@ -206,6 +220,41 @@ func (e ErrMultipleResourcesFound) Error() string {
 	return e.choseErrString()
 }
 
+// ErrMicroversionUnsupported is returned by a request function which requires
+// a higher microversion than the one the service client is set to.
+type ErrMicroversionUnsupported struct {
+	BaseError
+	ServiceType string
+	Required    string
+	Actual      string
+}
+
+func (e ErrMicroversionUnsupported) Error() string {
+	e.DefaultErrString = fmt.Sprintf(
+		"This request requires %s microversion %s or later, but the client is set to microversion %s",
+		e.ServiceType, e.Required, e.Actual,
+	)
+	return e.choseErrString()
+}
+
+// ErrResourceFailed is returned by WaitForStatus and the WaitFor helpers of
+// the resource packages when the polled resource reaches a status from which
+// it cannot reach the awaited one.
+type ErrResourceFailed struct {
+	BaseError
+	ID     string
+	Status string
+	Fault  string
+}
+
+func (e ErrResourceFailed) Error() string {
+	e.DefaultErrString = fmt.Sprintf("Resource %s reached failure status %s", e.ID, e.Status)
+	if e.Fault != "" {
+		e.DefaultErrString += ": " + e.Fault
+	}
+	return e.choseErrString()
+}
+
 // ErrUnexpectedType is the error when an unexpected type is encountered
 type ErrUnexpectedType struct {
 	BaseError
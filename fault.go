@@ -0,0 +1,217 @@
+package gophercloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Fault is the error reported by an OpenStack service in the body of an
+// unsuccessful response. Each service wraps its errors in its own envelope;
+// Fault is the common representation of all of them.
+//
+// A Fault is recorded on ErrUnexpectedResponseCode and can be retrieved with
+// errors.As:
+//
+//	var fault *gophercloud.Fault
+//	if errors.As(err, &fault) && fault.Type == "OverQuota" {
+//		handleQuotaExceeded()
+//	}
+type Fault struct {
+	// ServiceType is the type of the service which returned the fault, if
+	// known (e.g. compute, network).
+	ServiceType string
+
+	// Type is the kind of the fault as reported by the service. Depending on
+	// the service this is the name of the envelope (e.g. "itemNotFound",
+	// "badRequest" or "overLimit" for Nova, Cinder and Manila), the error
+	// type (e.g. "PortNotFound" or "OverQuota" for Neutron), the fault code
+	// (e.g. "Client" for Ironic and Octavia) or the error title (Keystone).
+	Type string
+
+	// Message is the human-readable description of the fault.
+	Message string
+
+	// Code is the numeric code reported in the body of the fault. It usually
+	// matches the HTTP status code, and is zero if the service did not
+	// report one.
+	Code int
+
+	// Details contains additional information about the fault, if provided
+	// by the service.
+	Details string
+}
+
+func (f *Fault) Error() string {
+	if f.Type != "" {
+		return fmt.Sprintf("%s: %s", f.Type, f.Message)
+	}
+	return f.Message
+}
+
+// ParseFault parses the body of an unsuccessful response returned by the
+// service of the given type. It returns nil if the body does not contain a
+// recognized fault.
+func ParseFault(serviceType string, body []byte) *Fault {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '{' {
+		return nil
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil
+	}
+
+	fault := parseFaultEnvelope(envelope, body)
+	if fault == nil || fault.Message == "" {
+		return nil
+	}
+	fault.ServiceType = serviceType
+	return fault
+}
+
+// faultBody contains the fields used by the various OpenStack services to
+// describe an error.
+type faultBody struct {
+	// Nova, Cinder, Manila, Keystone, Neutron, Heat, Designate
+	Message string `json:"message"`
+	// Neutron, Heat, Designate
+	Type string `json:"type"`
+	// Neutron
+	Detail string `json:"detail"`
+	// Keystone, Barbican, API-SIG compliant services
+	Title string `json:"title"`
+	// Barbican
+	Description string `json:"description"`
+	// Nova, Cinder, Manila, Keystone, Designate (integer), API-SIG (string)
+	Code json.RawMessage `json:"code"`
+	// API-SIG
+	Status int `json:"status"`
+	// Ironic, Octavia
+	FaultString string `json:"faultstring"`
+	FaultCode   string `json:"faultcode"`
+	DebugInfo   string `json:"debuginfo"`
+}
+
+func (b faultBody) toFault(typ string) *Fault {
+	f := &Fault{
+		Type:    typ,
+		Message: b.Message,
+		Details: b.Detail,
+		Code:    b.Status,
+	}
+
+	if f.Type == "" {
+		f.Type = b.Type
+	}
+	if f.Type == "" {
+		f.Type = b.Title
+	}
+	if f.Message == "" {
+		f.Message = b.Description
+	}
+	if f.Message == "" {
+		// API-SIG compliant services describe the error in "detail"
+		f.Message, f.Details = f.Details, ""
+	}
+
+	if len(b.Code) > 0 {
+		var code int
+		if err := json.Unmarshal(b.Code, &code); err == nil {
+			f.Code = code
+		} else {
+			var s string
+			if err := json.Unmarshal(b.Code, &s); err == nil {
+				if code, err := strconv.Atoi(s); err == nil {
+					f.Code = code
+				} else if f.Type == "" {
+					f.Type = s
+				} else if f.Details == "" {
+					f.Details = s
+				}
+			}
+		}
+	}
+
+	if b.FaultString != "" {
+		f.Message = b.FaultString
+		if f.Type == "" {
+			f.Type = b.FaultCode
+		}
+		if f.Details == "" {
+			f.Details = b.DebugInfo
+		}
+	}
+
+	return f
+}
+
+func parseFaultEnvelope(envelope map[string]json.RawMessage, body []byte) *Fault {
+	// Ironic: {"error_message": "{\"faultstring\": ..., \"faultcode\": ...}"}
+	if raw, ok := envelope["error_message"]; ok {
+		var encoded string
+		if err := json.Unmarshal(raw, &encoded); err == nil {
+			var b faultBody
+			if err := json.Unmarshal([]byte(encoded), &b); err == nil {
+				return b.toFault("")
+			}
+			return &Fault{Message: encoded}
+		}
+		var b faultBody
+		if err := json.Unmarshal(raw, &b); err == nil {
+			return b.toFault("")
+		}
+	}
+
+	// API-SIG compliant services (Placement, Magnum):
+	// {"errors": [{"status": 404, "title": ..., "detail": ..., "code": ...}]}
+	if raw, ok := envelope["errors"]; ok {
+		var errs []faultBody
+		if err := json.Unmarshal(raw, &errs); err == nil && len(errs) > 0 {
+			return errs[0].toFault("")
+		}
+	}
+
+	// Neutron: {"NeutronError": {"type": ..., "message": ..., "detail": ...}}
+	if raw, ok := envelope["NeutronError"]; ok {
+		var b faultBody
+		if err := json.Unmarshal(raw, &b); err == nil {
+			return b.toFault("")
+		}
+	}
+
+	// Keystone: {"error": {"code": 401, "message": ..., "title": ...}}
+	// Heat: {"error": {"message": ..., "type": ...}, "code": 400, "title": ...}
+	if raw, ok := envelope["error"]; ok {
+		var b faultBody
+		if err := json.Unmarshal(raw, &b); err == nil && b.Message != "" {
+			f := b.toFault("")
+			if f.Code == 0 {
+				if code, ok := envelope["code"]; ok {
+					_ = json.Unmarshal(code, &f.Code)
+				}
+			}
+			return f
+		}
+	}
+
+	// Octavia, Designate, Barbican: the fault fields are at the top level.
+	var top faultBody
+	if err := json.Unmarshal(body, &top); err == nil && (top.FaultString != "" || top.Message != "" || top.Description != "") {
+		return top.toFault("")
+	}
+
+	// Nova, Cinder, Manila: {"itemNotFound": {"message": ..., "code": 404}}
+	if len(envelope) == 1 {
+		for name, raw := range envelope {
+			var b faultBody
+			if err := json.Unmarshal(raw, &b); err == nil {
+				return b.toFault(name)
+			}
+		}
+	}
+
+	return nil
+}
@@ -140,7 +140,7 @@ func NewBlockStorageV1Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -161,7 +161,7 @@ func NewBlockStorageV2Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -182,7 +182,7 @@ func NewBlockStorageV3Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -241,7 +241,7 @@ func NewComputeV2Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -262,7 +262,7 @@ func NewBareMetalV1Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -303,7 +303,7 @@ func NewBareMetalIntrospectionV1Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -324,7 +324,7 @@ func NewDBV1Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -345,7 +345,7 @@ func NewDNSV2Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -366,7 +366,7 @@ func NewIdentityV2Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -387,7 +387,7 @@ func NewIdentityV2AdminClient() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -409,7 +409,7 @@ func NewIdentityV2UnauthenticatedClient() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.NewClient(ao.IdentityEndpoint)
+	client, err := newClient(ao.IdentityEndpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -428,7 +428,7 @@ func NewIdentityV3Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -449,7 +449,7 @@ func NewIdentityV3UnauthenticatedClient() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.NewClient(ao.IdentityEndpoint)
+	client, err := newClient(ao.IdentityEndpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -468,7 +468,7 @@ func NewImageV2Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -489,7 +489,7 @@ func NewNetworkV2Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -510,7 +510,7 @@ func NewObjectStorageV1Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -531,7 +531,7 @@ func NewSharedFileSystemV2Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -552,7 +552,7 @@ func NewLoadBalancerV2Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -573,7 +573,7 @@ func NewMessagingV2Client(clientID string) (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -594,7 +594,7 @@ func NewContainerV1Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -615,7 +615,7 @@ func NewKeyManagerV1Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -627,13 +627,51 @@ func NewKeyManagerV1Client() (*gophercloud.ServiceClient, error) {
 	})
 }
 
+// newClient returns an unauthenticated provider client which sends its
+// requests through the transport of the acceptance tests.
+func newClient(endpoint string) (*gophercloud.ProviderClient, error) {
+	client, err := openstack.NewClient(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := newTransport()
+	if err != nil {
+		return nil, err
+	}
+	client.HTTPClient = http.Client{Transport: transport}
+
+	return client, nil
+}
+
+// authenticatedClient returns a provider client authenticated with ao, which
+// sends its requests, including the authentication ones, through the
+// transport of the acceptance tests.
+func authenticatedClient(ao gophercloud.AuthOptions) (*gophercloud.ProviderClient, error) {
+	client, err := newClient(ao.IdentityEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	err = openstack.Authenticate(context.TODO(), client, ao)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
 // configureDebug will configure the provider client to print the API
 // requests and responses if OS_DEBUG is enabled.
 func configureDebug(client *gophercloud.ProviderClient) *gophercloud.ProviderClient {
 	if os.Getenv("OS_DEBUG") != "" {
+		rt := client.HTTPClient.Transport
+		if rt == nil {
+			rt = &http.Transport{}
+		}
 		client.HTTPClient = http.Client{
 			Transport: &LogRoundTripper{
-				Rt: &http.Transport{},
+				Rt: rt,
 			},
 		}
 	}
@@ -650,7 +688,7 @@ func NewContainerInfraV1Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -671,7 +709,7 @@ func NewWorkflowV2Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -692,7 +730,7 @@ func NewOrchestrationV1Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
@@ -713,7 +751,7 @@ func NewPlacementV1Client() (*gophercloud.ServiceClient, error) {
 		return nil, err
 	}
 
-	client, err := openstack.AuthenticatedClient(context.TODO(), ao)
+	client, err := authenticatedClient(ao)
 	if err != nil {
 		return nil, err
 	}
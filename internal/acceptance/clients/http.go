@@ -7,8 +7,12 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"os"
 	"sort"
 	"strings"
+	"sync"
+
+	"github.com/gophercloud/gophercloud/v2/testhelper/cassette"
 )
 
 // List of headers that need to be redacted
@@ -17,6 +21,41 @@ var REDACT_HEADERS = []string{"x-auth-token", "x-auth-key", "x-service-token",
 	"x-container-meta-temp-url-key", "x-container-meta-temp-url-key-2", "set-cookie",
 	"x-subject-token"}
 
+var (
+	transportOnce sync.Once
+	transport     http.RoundTripper
+	transportErr  error
+)
+
+// newTransport returns the transport of the clients. If OS_CASSETTE is set,
+// it is a cassette.Recorder shared by all the clients of the test binary,
+// which records the requests to the cassette at OS_CASSETTE if
+// OS_CASSETTE_MODE is "record", and replays them from it otherwise.
+func newTransport() (http.RoundTripper, error) {
+	transportOnce.Do(func() {
+		path := os.Getenv("OS_CASSETTE")
+		if path == "" {
+			transport = http.DefaultTransport
+			return
+		}
+
+		var mode cassette.Mode
+		switch m := os.Getenv("OS_CASSETTE_MODE"); m {
+		case "", "replay":
+			mode = cassette.ModeReplay
+		case "record":
+			mode = cassette.ModeRecord
+		default:
+			transportErr = fmt.Errorf("invalid OS_CASSETTE_MODE %q: expected record or replay", m)
+			return
+		}
+
+		transport, transportErr = cassette.New(path, cassette.Options{Mode: mode})
+	})
+
+	return transport, transportErr
+}
+
 // LogRoundTripper satisfies the http.RoundTripper interface and is used to
 // customize the default http client RoundTripper to allow logging.
 type LogRoundTripper struct {
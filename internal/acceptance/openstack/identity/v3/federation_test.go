@@ -120,3 +120,111 @@ func TestMappingsCRUD(t *testing.T) {
 	resp := federation.GetMapping(context.TODO(), client, mappingName)
 	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(resp.Err, http.StatusNotFound))
 }
+
+func TestIdentityProvidersCRUD(t *testing.T) {
+	clients.RequireAdmin(t)
+
+	idpName := tools.RandomString("TESTIDP-", 8)
+	mappingName := tools.RandomString("TESTMAPPING-", 8)
+
+	client, err := clients.NewIdentityV3Client()
+	th.AssertNoErr(t, err)
+
+	createOpts := federation.CreateIdentityProviderOpts{
+		Description: "Test identity provider",
+		Enabled:     gophercloud.Enabled,
+		RemoteIDs:   []string{"https://" + idpName + ".example.com"},
+	}
+
+	idp, err := federation.CreateIdentityProvider(context.TODO(), client, idpName, createOpts).Extract()
+	th.AssertNoErr(t, err)
+	defer func() {
+		err := federation.DeleteIdentityProvider(context.TODO(), client, idpName).ExtractErr()
+		th.AssertNoErr(t, err)
+
+		resp := federation.GetIdentityProvider(context.TODO(), client, idpName)
+		th.AssertEquals(t, true, gophercloud.ResponseCodeIs(resp.Err, http.StatusNotFound))
+	}()
+
+	tools.PrintResource(t, idp)
+	th.AssertEquals(t, idpName, idp.ID)
+	th.AssertDeepEquals(t, createOpts.RemoteIDs, idp.RemoteIDs)
+
+	description := ""
+	updateOpts := federation.UpdateIdentityProviderOpts{
+		Description: &description,
+		Enabled:     gophercloud.Disabled,
+	}
+
+	idp, err = federation.UpdateIdentityProvider(context.TODO(), client, idpName, updateOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "", idp.Description)
+	th.AssertEquals(t, false, idp.Enabled)
+
+	_, err = federation.CreateMapping(context.TODO(), client, mappingName, federation.CreateMappingOpts{
+		Rules: []federation.MappingRule{
+			{
+				Local:  []federation.RuleLocal{{User: &federation.RuleUser{Name: "{0}"}}},
+				Remote: []federation.RuleRemote{{Type: "UserName"}},
+			},
+		},
+	}).Extract()
+	th.AssertNoErr(t, err)
+	defer func() {
+		err := federation.DeleteMapping(context.TODO(), client, mappingName).ExtractErr()
+		th.AssertNoErr(t, err)
+	}()
+
+	protocol, err := federation.CreateProtocol(context.TODO(), client, idpName, "openid", federation.CreateProtocolOpts{
+		MappingID: mappingName,
+	}).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, mappingName, protocol.MappingID)
+
+	allPages, err := federation.ListProtocols(client, idpName).AllPages(context.TODO())
+	th.AssertNoErr(t, err)
+	protocols, err := federation.ExtractProtocols(allPages)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(protocols))
+
+	err = federation.DeleteProtocol(context.TODO(), client, idpName, "openid").ExtractErr()
+	th.AssertNoErr(t, err)
+}
+
+func TestServiceProvidersCRUD(t *testing.T) {
+	clients.RequireAdmin(t)
+
+	spName := tools.RandomString("TESTSP-", 8)
+
+	client, err := clients.NewIdentityV3Client()
+	th.AssertNoErr(t, err)
+
+	createOpts := federation.CreateServiceProviderOpts{
+		AuthURL: "https://" + spName + ".example.com/v3/OS-FEDERATION/identity_providers/acme/protocols/saml2/auth",
+		SPURL:   "https://" + spName + ".example.com/Shibboleth.sso/SAML2/ECP",
+	}
+
+	sp, err := federation.CreateServiceProvider(context.TODO(), client, spName, createOpts).Extract()
+	th.AssertNoErr(t, err)
+	defer func() {
+		err := federation.DeleteServiceProvider(context.TODO(), client, spName).ExtractErr()
+		th.AssertNoErr(t, err)
+	}()
+
+	tools.PrintResource(t, sp)
+	th.AssertEquals(t, createOpts.AuthURL, sp.AuthURL)
+	th.AssertEquals(t, createOpts.SPURL, sp.SPURL)
+
+	description := "Test service provider"
+	sp, err = federation.UpdateServiceProvider(context.TODO(), client, spName, federation.UpdateServiceProviderOpts{
+		Description: &description,
+	}).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, description, sp.Description)
+
+	allPages, err := federation.ListServiceProviders(client, federation.ListServiceProvidersOpts{ID: spName}).AllPages(context.TODO())
+	th.AssertNoErr(t, err)
+	sps, err := federation.ExtractServiceProviders(allPages)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(sps))
+}
@@ -575,40 +575,7 @@ func WaitForPortToCreate(client *gophercloud.ServiceClient, portID string) error
 	})
 }
 
-// This is duplicated from https://github.com/gophercloud/utils
-// so that Gophercloud "core" doesn't have a dependency on the
-// complementary utils repository.
+// IDFromName returns the ID of the network with the given name.
 func IDFromName(client *gophercloud.ServiceClient, name string) (string, error) {
-	count := 0
-	id := ""
-
-	listOpts := networks.ListOpts{
-		Name: name,
-	}
-
-	pages, err := networks.List(client, listOpts).AllPages(context.TODO())
-	if err != nil {
-		return "", err
-	}
-
-	all, err := networks.ExtractNetworks(pages)
-	if err != nil {
-		return "", err
-	}
-
-	for _, s := range all {
-		if s.Name == name {
-			count++
-			id = s.ID
-		}
-	}
-
-	switch count {
-	case 0:
-		return "", gophercloud.ErrResourceNotFound{Name: name, ResourceType: "network"}
-	case 1:
-		return id, nil
-	default:
-		return "", gophercloud.ErrMultipleResourcesFound{Name: name, Count: count, ResourceType: "network"}
-	}
+	return networks.IDFromName(context.TODO(), client, name)
 }
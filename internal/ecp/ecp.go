@@ -0,0 +1,96 @@
+// Package ecp implements the HTTP session shared by the federated
+// authentication plugins which establish a SAML2 session with a service
+// provider through the Enhanced Client or Proxy (ECP) profile.
+package ecp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// ContentType is the content type of the PAOS messages exchanged with the
+// service provider.
+const ContentType = "application/vnd.paos+xml"
+
+// Session sends the requests of an ECP exchange. They are not sent with
+// ProviderClient.Request, which would add the token of the Identity service
+// to them, but with a copy of its HTTP client which keeps the cookies of the
+// SAML2 session, so that they share its configuration.
+type Session struct {
+	httpClient http.Client
+	userAgent  string
+}
+
+// NewSession returns a session using the HTTP client of client.
+func NewSession(client *gophercloud.ProviderClient) (*Session, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := client.HTTPClient
+	httpClient.Jar = jar
+	// The service provider redirects to the federated auth endpoint once
+	// the SAML2 session is established. Like keystoneauth, the redirect is
+	// not followed and the token is requested instead.
+	httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &Session{
+		httpClient: httpClient,
+		userAgent:  client.UserAgent.Join(),
+	}, nil
+}
+
+// Do sends a request and returns the response and its body, or an
+// ErrUnexpectedResponseCode if the response code is not one of okCodes.
+func (s *Session) Do(req *http.Request, okCodes ...int) (*http.Response, []byte, error) {
+	req.Header.Set("User-Agent", s.userAgent)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, code := range okCodes {
+		if resp.StatusCode == code {
+			return resp, body, nil
+		}
+	}
+	return nil, nil, gophercloud.ErrUnexpectedResponseCode{
+		URL:            req.URL.String(),
+		Method:         req.Method,
+		Expected:       okCodes,
+		Actual:         resp.StatusCode,
+		Body:           body,
+		ResponseHeader: resp.Header,
+	}
+}
+
+// Token requests the unscoped token of the SAML2 session from the federated
+// auth endpoint of the Identity service.
+func (s *Session) Token(ctx context.Context, authURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, authURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, _, err := s.Do(req, http.StatusOK, http.StatusCreated)
+	if err != nil {
+		return "", err
+	}
+	tokenID := resp.Header.Get("X-Subject-Token")
+	if tokenID == "" {
+		return "", fmt.Errorf("no X-Subject-Token in the response of %s", authURL)
+	}
+	return tokenID, nil
+}
@@ -0,0 +1,205 @@
+// Package fakestore implements the in-memory storage shared by the fake
+// clients of the resource packages.
+package fakestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/internal/uuid"
+)
+
+// Store is a concurrency-safe collection of resources indexed by ID, which
+// keeps the order in which the resources were added.
+type Store[T any] struct {
+	mu           sync.Mutex
+	resourceType string
+	clone        func(T) T
+	items        map[string]T
+	order        []string
+}
+
+// New returns an empty Store of resources of the given type, which is used
+// in the errors returned by the Store. The resources are copied with clone
+// when they are stored and returned, so that neither the Store nor its
+// callers see the changes the other makes to their maps and slices.
+func New[T any](resourceType string, clone func(T) T) *Store[T] {
+	return &Store[T]{
+		resourceType: resourceType,
+		clone:        clone,
+		items:        make(map[string]T),
+	}
+}
+
+// Put adds the given resource to the store, or replaces the resource with
+// the same ID.
+func (s *Store[T]) Put(id string, v T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[id]; !ok {
+		s.order = append(s.order, id)
+	}
+	s.items[id] = s.clone(v)
+}
+
+// Get returns a copy of the resource with the given ID. It returns a 404
+// gophercloud.ErrUnexpectedResponseCode error if there is none.
+func (s *Store[T]) Get(id string) (*T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.items[id]
+	if !ok {
+		return nil, NotFound(http.MethodGet, s.resourceType, id)
+	}
+	v = s.clone(v)
+	return &v, nil
+}
+
+// List returns a copy of the resources for which match returns true, in the
+// order in which they were added. A nil match selects all the resources.
+func (s *Store[T]) List(match func(T) bool) []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := make([]T, 0, len(s.order))
+	for _, id := range s.order {
+		v := s.items[id]
+		if match == nil || match(v) {
+			items = append(items, s.clone(v))
+		}
+	}
+	return items
+}
+
+// Update applies update to the resource with the given ID and returns a copy
+// of the updated resource. It returns a 404
+// gophercloud.ErrUnexpectedResponseCode error if there is no such resource.
+func (s *Store[T]) Update(id string, update func(*T) error) (*T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.items[id]
+	if !ok {
+		return nil, NotFound(http.MethodPut, s.resourceType, id)
+	}
+	v = s.clone(v)
+	if err := update(&v); err != nil {
+		return nil, err
+	}
+	s.items[id] = v
+	v = s.clone(v)
+	return &v, nil
+}
+
+// Delete removes the resource with the given ID. It returns a 404
+// gophercloud.ErrUnexpectedResponseCode error if there is none.
+func (s *Store[T]) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[id]; !ok {
+		return NotFound(http.MethodDelete, s.resourceType, id)
+	}
+	delete(s.items, id)
+	for i, v := range s.order {
+		if v == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// NotFound returns the error a service reports for a missing resource, so
+// that gophercloud.ResponseCodeIs(err, http.StatusNotFound) holds for it.
+func NotFound(method, resourceType, id string) error {
+	return gophercloud.ErrUnexpectedResponseCode{
+		URL:      resourceType + "/" + id,
+		Method:   method,
+		Expected: []int{http.StatusOK},
+		Actual:   http.StatusNotFound,
+		Body:     fmt.Appendf(nil, "%s %s could not be found", resourceType, id),
+	}
+}
+
+// NewID returns a random UUID.
+func NewID() string {
+	return uuid.New()
+}
+
+// Decode decodes the object stored under key in a request body, as built by
+// the ToXxxCreateMap and ToXxxUpdateMap methods of the options, into v.
+func Decode(body map[string]any, key string, v any) error {
+	b, err := json.Marshal(body[key])
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// CloneValue returns a deep copy of v, a value decoded from JSON or built
+// like one: the maps and slices it holds, at any depth, are copied. Other
+// values are returned as is.
+func CloneValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		return CloneMap(v)
+	case []map[string]any:
+		return CloneMaps(v)
+	case []any:
+		if v == nil {
+			return v
+		}
+		c := make([]any, len(v))
+		for i, e := range v {
+			c[i] = CloneValue(e)
+		}
+		return c
+	case map[string]string:
+		return maps.Clone(v)
+	case []string:
+		return slices.Clone(v)
+	default:
+		return v
+	}
+}
+
+// CloneMap returns a deep copy of m, as CloneValue.
+func CloneMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]any, len(m))
+	for k, v := range m {
+		c[k] = CloneValue(v)
+	}
+	return c
+}
+
+// CloneMaps returns a deep copy of s, as CloneValue.
+func CloneMaps(s []map[string]any) []map[string]any {
+	if s == nil {
+		return nil
+	}
+	c := make([]map[string]any, len(s))
+	for i, m := range s {
+		c[i] = CloneMap(m)
+	}
+	return c
+}
+
+// ClonePtr returns a pointer to a copy of the value p points to, or nil.
+func ClonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
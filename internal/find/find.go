@@ -0,0 +1,60 @@
+// Package find implements the lookup of resources by name shared by the
+// IDFromName and Find functions of the resource packages.
+package find
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/pagination"
+)
+
+// ByName returns the only item of the pager whose name, as returned by
+// nameOf, is exactly name. The pager is expected to be filtered by name on
+// the server side when the API allows it, but its items are compared again
+// since some APIs do partial or case-insensitive matches.
+//
+// It returns a gophercloud.ErrResourceNotFound error if there is no such
+// item, and a gophercloud.ErrMultipleResourcesFound error if there are
+// several.
+func ByName[T any](ctx context.Context, pager pagination.Pager, extract func(pagination.Page) ([]T, error), nameOf func(T) string, resourceType, name string) (*T, error) {
+	var found *T
+	count := 0
+	for item, err := range pagination.Items(ctx, pager, extract) {
+		if err != nil {
+			return nil, err
+		}
+		if nameOf(item) != name {
+			continue
+		}
+		count++
+		if found == nil {
+			found = &item
+		}
+	}
+
+	switch count {
+	case 0:
+		return nil, gophercloud.ErrResourceNotFound{Name: name, ResourceType: resourceType}
+	case 1:
+		return found, nil
+	default:
+		return nil, gophercloud.ErrMultipleResourcesFound{Name: name, Count: count, ResourceType: resourceType}
+	}
+}
+
+// ByNameOrID returns the resource with the given ID using get, or, if there
+// is none, the resource with the given name using byName.
+func ByNameOrID[T any](ctx context.Context, nameOrID string, get func(context.Context, string) (*T, error), byName func(context.Context, string) (*T, error)) (*T, error) {
+	item, err := get(ctx, nameOrID)
+	if err == nil {
+		return item, nil
+	}
+	// Some services reject IDs which are not in their format with a 400
+	// response rather than a 404.
+	if !gophercloud.ResponseCodeIs(err, http.StatusNotFound) && !gophercloud.ResponseCodeIs(err, http.StatusBadRequest) {
+		return nil, err
+	}
+	return byName(ctx, nameOrID)
+}
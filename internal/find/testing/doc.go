@@ -0,0 +1,2 @@
+// find unit tests
+package testing
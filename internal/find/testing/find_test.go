@@ -0,0 +1,147 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/internal/find"
+	"github.com/gophercloud/gophercloud/v2/pagination"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/testhelper/client"
+)
+
+type item struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type itemPage struct {
+	pagination.SinglePageBase
+}
+
+func (r itemPage) IsEmpty() (bool, error) {
+	items, err := extractItems(r)
+	return len(items) == 0, err
+}
+
+func extractItems(r pagination.Page) ([]item, error) {
+	var s struct {
+		Items []item `json:"items"`
+	}
+	err := (r.(itemPage)).ExtractInto(&s)
+	return s.Items, err
+}
+
+// handleItems registers a list of items which, like some APIs, filters them
+// by name case-insensitively.
+func handleItems(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/items", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		switch strings.ToLower(r.URL.Query().Get("name")) {
+		case "web":
+			fmt.Fprint(w, `{"items": [{"id": "1", "name": "web"}, {"id": "2", "name": "WEB"}]}`)
+		case "db":
+			fmt.Fprint(w, `{"items": [{"id": "3", "name": "db"}, {"id": "4", "name": "db"}]}`)
+		case "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			fmt.Fprint(w, `{"items": []}`)
+		}
+	})
+}
+
+func byName(fakeServer th.FakeServer, name string) (*item, error) {
+	pager := pagination.NewPager(client.ServiceClient(fakeServer), fakeServer.Endpoint()+"items?name="+name, func(r pagination.PageResult) pagination.Page {
+		return itemPage{pagination.SinglePageBase(r)}
+	})
+	return find.ByName(context.TODO(), pager, extractItems, func(v item) string { return v.Name }, "item", name)
+}
+
+func TestByName(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	handleItems(t, fakeServer)
+
+	// the names returned by the server are compared exactly
+	actual, err := byName(fakeServer, "WEB")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "2", actual.ID)
+}
+
+func TestByNameNotFound(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	handleItems(t, fakeServer)
+
+	for _, name := range []string{"cache", "Web"} {
+		_, err := byName(fakeServer, name)
+		var notFound gophercloud.ErrResourceNotFound
+		th.AssertEquals(t, true, errors.As(err, &notFound))
+		th.AssertEquals(t, name, notFound.Name)
+		th.AssertEquals(t, "item", notFound.ResourceType)
+	}
+}
+
+func TestByNameMultipleResourcesFound(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	handleItems(t, fakeServer)
+
+	_, err := byName(fakeServer, "db")
+	var multiple gophercloud.ErrMultipleResourcesFound
+	th.AssertEquals(t, true, errors.As(err, &multiple))
+	th.AssertEquals(t, "db", multiple.Name)
+	th.AssertEquals(t, 2, multiple.Count)
+	th.AssertEquals(t, "item", multiple.ResourceType)
+}
+
+func TestByNameListError(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	handleItems(t, fakeServer)
+
+	_, err := byName(fakeServer, "broken")
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusInternalServerError))
+}
+
+func TestByNameOrID(t *testing.T) {
+	byNameResult := &item{ID: "1", Name: "web"}
+	for _, tt := range []struct {
+		name     string
+		getErr   error
+		expected *item
+		err      bool
+	}{
+		{"found by ID", nil, &item{ID: "web"}, false},
+		{"not found by ID", gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusNotFound}, byNameResult, false},
+		{"invalid ID", gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusBadRequest}, byNameResult, false},
+		{"get error", gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusInternalServerError}, nil, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var byNameCalls int
+			actual, err := find.ByNameOrID(context.TODO(), "web", func(_ context.Context, id string) (*item, error) {
+				if tt.getErr != nil {
+					return nil, tt.getErr
+				}
+				return &item{ID: id}, nil
+			}, func(_ context.Context, name string) (*item, error) {
+				byNameCalls++
+				th.AssertEquals(t, "web", name)
+				return byNameResult, nil
+			})
+			if tt.err {
+				th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusInternalServerError))
+				th.AssertEquals(t, 0, byNameCalls)
+				return
+			}
+			th.AssertNoErr(t, err)
+			th.CheckDeepEquals(t, tt.expected, actual)
+		})
+	}
+}
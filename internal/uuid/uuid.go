@@ -0,0 +1,18 @@
+// Package uuid implements the generation of the random UUIDs used as request
+// and resource IDs.
+package uuid
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// New returns a random (version 4) UUID.
+func New() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	// set the version (4) and variant (RFC 4122) bits of the UUID
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,187 @@
+package gophercloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RedactedValue replaces the value of sensitive headers and body fields in
+// logged requests and responses.
+const RedactedValue = "***"
+
+// sensitiveHeaders lists the HTTP headers whose value is never logged.
+var sensitiveHeaders = []string{
+	"X-Auth-Token",
+	"X-Subject-Token",
+	"X-Service-Token",
+	"X-Storage-Token",
+	"X-Auth-Key",
+	"Authorization",
+	"Openstack-Auth-Receipt",
+	"Set-Cookie",
+	"X-Account-Meta-Temp-Url-Key",
+	"X-Account-Meta-Temp-Url-Key-2",
+	"X-Container-Meta-Temp-Url-Key",
+	"X-Container-Meta-Temp-Url-Key-2",
+}
+
+// sensitiveFields lists the JSON body fields whose value is never logged.
+// Field names are compared case-insensitively.
+var sensitiveFields = map[string]bool{
+	"password":          true,
+	"original_password": true,
+	"adminpass":         true,
+	"admin_pass":        true,
+	"secret":            true,
+	"client_secret":     true,
+	"passcode":          true,
+	"access_token":      true,
+	"refresh_token":     true,
+	"id_token":          true,
+	"device_code":       true,
+	"private_key":       true,
+	"payload":           true,
+	"blob":              true,
+	"signature":         true,
+}
+
+// IsSensitiveField reports whether the JSON body fields with the given name
+// hold credentials, which RedactJSONBody redacts. Field names are compared
+// case-insensitively.
+func IsSensitiveField(name string) bool {
+	return sensitiveFields[strings.ToLower(name)]
+}
+
+// RedactHeaders returns a copy of the given headers in which the values of
+// authentication tokens and other credentials are replaced by RedactedValue.
+func RedactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if len(redacted.Values(name)) > 0 {
+			redacted.Set(name, RedactedValue)
+		}
+	}
+	return redacted
+}
+
+// RedactJSONBody returns a copy of the given JSON document in which the values
+// of passwords, application credential secrets, TOTP passcodes, token IDs and
+// other credentials are replaced by RedactedValue. A body which is not valid
+// JSON cannot be safely redacted, and is replaced by a placeholder mentioning
+// its length.
+func RedactJSONBody(body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Appendf(nil, "<non-JSON body of %d bytes>", len(body))
+	}
+
+	b, err := json.Marshal(redactValue(v, ""))
+	if err != nil {
+		return fmt.Appendf(nil, "<unredactable body of %d bytes>", len(body))
+	}
+	return b
+}
+
+// redactValue walks a decoded JSON document and redacts sensitive fields.
+// parent is the name of the field containing v.
+func redactValue(v any, parent string) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, fv := range t {
+			if IsSensitiveField(k) {
+				t[k] = RedactedValue
+				continue
+			}
+			// The ID of a token used for the "token" authentication method
+			// is the token itself.
+			if parent == "token" && k == "id" {
+				t[k] = RedactedValue
+				continue
+			}
+			t[k] = redactValue(fv, k)
+		}
+	case []any:
+		for i, e := range t {
+			t[i] = redactValue(e, parent)
+		}
+	}
+	return v
+}
+
+// logEnabled reports whether the client logs messages at the given level.
+func (client *ProviderClient) logEnabled(ctx context.Context, level slog.Level) bool {
+	return client.Logger != nil && client.Logger.Enabled(ctx, level)
+}
+
+// logRequest logs an HTTP request which is about to be sent.
+func (client *ProviderClient) logRequest(ctx context.Context, req *http.Request, body []byte) {
+	if !client.logEnabled(ctx, slog.LevelDebug) {
+		return
+	}
+	client.Logger.LogAttrs(ctx, slog.LevelDebug, "sending request",
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Any("headers", RedactHeaders(req.Header)),
+		slog.String("body", string(RedactJSONBody(body))),
+	)
+}
+
+// logResponse logs an HTTP response. The response body is only logged when
+// it has been read by the client, e.g. for JSON responses and errors.
+func (client *ProviderClient) logResponse(ctx context.Context, req *http.Request, resp *http.Response, start time.Time, state *requestState, body []byte) {
+	if !client.logEnabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Int("status", resp.StatusCode),
+		slog.String("request_id", RequestIDFromHeader(resp.Header)),
+		slog.Duration("latency", time.Since(start)),
+		slog.Uint64("retries", uint64(state.retries)),
+		slog.Any("headers", RedactHeaders(resp.Header)),
+	}
+	if body != nil {
+		attrs = append(attrs, slog.String("body", string(RedactJSONBody(body))))
+	}
+	client.Logger.LogAttrs(ctx, slog.LevelDebug, "received response", attrs...)
+}
+
+// logRequestError logs a request which failed without a response.
+func (client *ProviderClient) logRequestError(ctx context.Context, req *http.Request, start time.Time, state *requestState, err error) {
+	if !client.logEnabled(ctx, slog.LevelDebug) {
+		return
+	}
+	client.Logger.LogAttrs(ctx, slog.LevelDebug, "request failed",
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Duration("latency", time.Since(start)),
+		slog.Uint64("retries", uint64(state.retries)),
+		slog.String("error", err.Error()),
+	)
+}
+
+// logEvent logs a retry or reauthentication event. method and url may be
+// empty for events which are not tied to a request.
+func (client *ProviderClient) logEvent(ctx context.Context, msg, method, url string, attrs ...slog.Attr) {
+	if !client.logEnabled(ctx, slog.LevelInfo) {
+		return
+	}
+	if method != "" {
+		attrs = append([]slog.Attr{
+			slog.String("method", method),
+			slog.String("url", url),
+		}, attrs...)
+	}
+	client.Logger.LogAttrs(ctx, slog.LevelInfo, msg, attrs...)
+}
@@ -0,0 +1,128 @@
+package gophercloud
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type microversionKey struct{}
+
+// WithMicroversion returns a copy of ctx which carries the given microversion
+// for the given service type. Requests sent with the returned context by a
+// ServiceClient of that service type use this microversion instead of
+// ServiceClient.Microversion; the clients of other services are unaffected.
+// This allows a single ServiceClient to be shared by goroutines which need
+// different microversions, without modifying it. Service type aliases, such
+// as "volume" for "block-storage", are accepted.
+//
+// Example:
+//
+//	ctx := gophercloud.WithMicroversion(context.TODO(), "compute", "2.79")
+//	server, err := servers.Get(ctx, computeClient, id).Extract()
+func WithMicroversion(ctx context.Context, serviceType, microversion string) context.Context {
+	microversions := make(map[string]string)
+	if parent, ok := ctx.Value(microversionKey{}).(map[string]string); ok {
+		for k, v := range parent {
+			microversions[k] = v
+		}
+	}
+	microversions[OfficialServiceType(serviceType)] = microversion
+	return context.WithValue(ctx, microversionKey{}, microversions)
+}
+
+// MicroversionFromContext returns the microversion set in ctx with
+// WithMicroversion for the given service type, or an empty string if there is
+// none.
+func MicroversionFromContext(ctx context.Context, serviceType string) string {
+	microversions, _ := ctx.Value(microversionKey{}).(map[string]string)
+	return microversions[OfficialServiceType(serviceType)]
+}
+
+// OfficialServiceType returns the official service type of which t is an
+// alias in ServiceTypeAliases, or t itself.
+func OfficialServiceType(t string) string {
+	for official, aliases := range ServiceTypeAliases {
+		if slices.Contains(aliases, t) {
+			return official
+		}
+	}
+	return t
+}
+
+// microversion returns the microversion to use for a request: the one set in
+// the RequestOpts, then the one set in the context, then the one of the
+// service client.
+func (client *ServiceClient) microversion(ctx context.Context, opts *RequestOpts) string {
+	if opts != nil && opts.Microversion != "" {
+		return opts.Microversion
+	}
+	if microversion := MicroversionFromContext(ctx, client.Type); microversion != "" {
+		return microversion
+	}
+	return client.Microversion
+}
+
+// CheckMicroversion returns an ErrMicroversionUnsupported error if the
+// microversion used for requests sent with ctx, either set with
+// WithMicroversion or in the service client, is lower than the given minimum.
+// Request functions which need a minimum microversion call it to fail early
+// with a clear error, instead of sending a request that the service would
+// reject or misinterpret.
+//
+// If no microversion is set, the check is skipped: the service then uses its
+// default microversion, which is unknown to the client.
+func (client *ServiceClient) CheckMicroversion(ctx context.Context, minimum string) error {
+	microversion := client.microversion(ctx, nil)
+	if microversion == "" {
+		return nil
+	}
+	supported, err := microversionAtLeast(microversion, minimum)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return ErrMicroversionUnsupported{
+			ServiceType: client.Type,
+			Required:    minimum,
+			Actual:      microversion,
+		}
+	}
+	return nil
+}
+
+// microversionAtLeast reports whether the microversion v is greater than or
+// equal to minimum. The "latest" microversion is greater than all others.
+func microversionAtLeast(v, minimum string) (bool, error) {
+	if v == "latest" {
+		return true, nil
+	}
+	vMajor, vMinor, err := parseMicroversion(v)
+	if err != nil {
+		return false, err
+	}
+	minMajor, minMinor, err := parseMicroversion(minimum)
+	if err != nil {
+		return false, err
+	}
+	return vMajor > minMajor || (vMajor == minMajor && vMinor >= minMinor), nil
+}
+
+// parseMicroversion parses a microversion of the form major.minor.
+func parseMicroversion(v string) (major, minor int, err error) {
+	majorStr, minorStr, ok := strings.Cut(v, ".")
+	if !ok {
+		return 0, 0, ErrInvalidInput{
+			ErrMissingInput: ErrMissingInput{Argument: "microversion"},
+			Value:           v,
+		}
+	}
+	if major, err = strconv.Atoi(majorStr); err != nil {
+		return 0, 0, err
+	}
+	if minor, err = strconv.Atoi(minorStr); err != nil {
+		return 0, 0, err
+	}
+	return major, minor, nil
+}
@@ -0,0 +1,93 @@
+package gophercloud
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestHandler performs a single logical request on behalf of a
+// ProviderClient. Its signature matches ProviderClient.Request so that any
+// request function can be wrapped by Middleware.
+type RequestHandler func(ctx context.Context, method, url string, options *RequestOpts) (*http.Response, error)
+
+// Middleware wraps a RequestHandler. A Middleware can inspect or modify the
+// RequestOpts before calling next, and inspect the response or error that
+// next returns. It may also decide not to call next at all, for example to
+// inject a fault.
+//
+// Example of a middleware that adds a header to every request:
+//
+//	provider.Middleware = append(provider.Middleware, func(next gophercloud.RequestHandler) gophercloud.RequestHandler {
+//		return func(ctx context.Context, method, url string, opts *gophercloud.RequestOpts) (*http.Response, error) {
+//			if opts.MoreHeaders == nil {
+//				opts.MoreHeaders = make(map[string]string)
+//			}
+//			opts.MoreHeaders["X-Custom"] = "value"
+//			return next(ctx, method, url, opts)
+//		}
+//	})
+type Middleware func(next RequestHandler) RequestHandler
+
+// RequestInfo describes the request that is currently passing through the
+// middleware chain. It can be retrieved from the context passed to a
+// RequestHandler with RequestInfoFromContext.
+//
+// Retries and Reauthenticated are updated while the request is being
+// performed, so they should be read after the next RequestHandler returns.
+type RequestInfo struct {
+	// ServiceType is the type of the ServiceClient that issued the request
+	// (e.g. compute, network). It is empty for requests issued directly
+	// through a ProviderClient.
+	ServiceType string
+
+	// Microversion is the microversion requested for the call, if any.
+	Microversion string
+
+	// StartTime is the time at which the request entered the ProviderClient.
+	StartTime time.Time
+
+	// Retries is the number of times the request has been retried, either
+	// because of RetryFunc or RetryBackoffFunc.
+	Retries uint
+
+	// Reauthenticated reports whether the client had to reauthenticate
+	// because the request failed with a 401 response.
+	Reauthenticated bool
+}
+
+type requestInfoKey struct{}
+
+// RequestInfoFromContext returns the RequestInfo of the request being
+// performed with ctx, or nil if ctx was not passed through
+// ProviderClient.Request.
+func RequestInfoFromContext(ctx context.Context) *RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(*RequestInfo)
+	return info
+}
+
+// chainMiddleware wraps handler with the given middleware. The first
+// middleware in the slice is the outermost one, i.e. it sees the request
+// first and the response last.
+func chainMiddleware(handler RequestHandler, middleware []Middleware) RequestHandler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// requestWithMiddleware performs a request through the given middleware
+// chain, recording info in the context for the middleware to consume.
+func (client *ProviderClient) requestWithMiddleware(ctx context.Context, method, url string, options *RequestOpts, info *RequestInfo, middleware []Middleware) (*http.Response, error) {
+	info.StartTime = time.Now()
+	ctx = context.WithValue(ctx, requestInfoKey{}, info)
+
+	handler := chainMiddleware(func(ctx context.Context, method, url string, options *RequestOpts) (*http.Response, error) {
+		return client.doRequest(ctx, method, url, options, &requestState{
+			hasReauthenticated: false,
+			info:               info,
+		})
+	}, middleware)
+
+	return handler(ctx, method, url, options)
+}
@@ -6,20 +6,37 @@ import (
 	"github.com/gophercloud/gophercloud/v2"
 )
 
+// FailureProvisionStates are the provision states which a node reaches when a
+// provisioning operation failed.
+var FailureProvisionStates = []ProvisionState{
+	DeployFail,
+	CleanFail,
+	InspectFail,
+	AdoptFail,
+	RescueFail,
+	UnrescueFail,
+	ServiceFail,
+}
+
 // WaitForProvisionState will continually poll a node until it successfully
-// transitions to a specified state. It will do this for at most the number
-// of seconds specified.
+// transitions to a specified state. It returns a gophercloud.ErrResourceFailed
+// error carrying the last error of the node as soon as the node reaches one
+// of the FailureProvisionStates, unless it is the awaited state. The polling
+// can be configured by setting a gophercloud.Poller in the context.
 func WaitForProvisionState(ctx context.Context, c *gophercloud.ServiceClient, id string, state ProvisionState) error {
-	return gophercloud.WaitFor(ctx, func(ctx context.Context) (bool, error) {
+	failureStates := make([]string, len(FailureProvisionStates))
+	for i, s := range FailureProvisionStates {
+		failureStates[i] = string(s)
+	}
+
+	return gophercloud.WaitForStatus(ctx, id, string(state), failureStates, func(ctx context.Context) (gophercloud.ResourceStatus, error) {
 		current, err := Get(ctx, c, id).Extract()
 		if err != nil {
-			return false, err
+			return gophercloud.ResourceStatus{}, err
 		}
-
-		if current.ProvisionState == string(state) {
-			return true, nil
-		}
-
-		return false, nil
+		return gophercloud.ResourceStatus{
+			Status: current.ProvisionState,
+			Fault:  current.LastError,
+		}, nil
 	})
 }
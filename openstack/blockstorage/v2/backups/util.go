@@ -0,0 +1,27 @@
+package backups
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// FailureStatuses are the statuses of a backup which Cinder failed to create,
+// to restore or to delete.
+var FailureStatuses = []string{"error", "error_deleting"}
+
+// WaitForStatus polls a backup until it reaches the given status, such as
+// available. The fail_reason of a failed backup is the Fault of the returned
+// gophercloud.ErrResourceFailed error.
+func WaitForStatus(ctx context.Context, c *gophercloud.ServiceClient, id, status string) error {
+	return gophercloud.WaitForStatus(ctx, id, status, FailureStatuses, func(ctx context.Context) (gophercloud.ResourceStatus, error) {
+		current, err := Get(ctx, c, id).Extract()
+		if err != nil {
+			return gophercloud.ResourceStatus{}, err
+		}
+		return gophercloud.ResourceStatus{
+			Status: current.Status,
+			Fault:  current.FailReason,
+		}, nil
+	})
+}
@@ -7,6 +7,10 @@ import (
 	"github.com/gophercloud/gophercloud/v2/pagination"
 )
 
+// minMicroversion is the first block-storage microversion which supports
+// attachments.
+const minMicroversion = "3.27"
+
 // CreateOptsBuilder allows extensions to add additional parameters to the
 // Create request.
 type CreateOptsBuilder interface {
@@ -28,7 +32,9 @@ type CreateOpts struct {
 	// information for exmaple initiator IQN, etc.
 	Connector map[string]any `json:"connector,omitempty"`
 	// Mode is an attachment mode. Acceptable values are read-only ('ro')
-	// and read-and-write ('rw'). Available only since 3.54 microversion.
+	// and read-and-write ('rw'). Available only since 3.54 microversion;
+	// Create returns a gophercloud.ErrMicroversionUnsupported error if the
+	// client is set to a lower one.
 	// For APIs from 3.27 till 3.53 use Connector["mode"] = "rw|ro".
 	Mode string `json:"mode,omitempty"`
 }
@@ -43,11 +49,23 @@ func (opts CreateOpts) ToAttachmentCreateMap() (map[string]any, error) {
 // extract the Attachment object from the response, call the Extract method on
 // the CreateResult.
 func Create(ctx context.Context, client *gophercloud.ServiceClient, opts CreateOptsBuilder) (r CreateResult) {
+	if err := client.CheckMicroversion(ctx, minMicroversion); err != nil {
+		r.Err = err
+		return
+	}
 	b, err := opts.ToAttachmentCreateMap()
 	if err != nil {
 		r.Err = err
 		return
 	}
+	if attachment, ok := b["attachment"].(map[string]any); ok {
+		if _, ok := attachment["mode"]; ok {
+			if err := client.CheckMicroversion(ctx, "3.54"); err != nil {
+				r.Err = err
+				return
+			}
+		}
+	}
 	resp, err := client.Post(ctx, createURL(client), b, &r.Body, &gophercloud.RequestOpts{
 		OkCodes: []int{200, 202},
 	})
@@ -57,6 +75,10 @@ func Create(ctx context.Context, client *gophercloud.ServiceClient, opts CreateO
 
 // Delete will delete the existing Attachment with the provided ID.
 func Delete(ctx context.Context, client *gophercloud.ServiceClient, id string) (r DeleteResult) {
+	if err := client.CheckMicroversion(ctx, minMicroversion); err != nil {
+		r.Err = err
+		return
+	}
 	resp, err := client.Delete(ctx, deleteURL(client, id), &gophercloud.RequestOpts{
 		OkCodes: []int{200},
 	})
@@ -67,6 +89,10 @@ func Delete(ctx context.Context, client *gophercloud.ServiceClient, id string) (
 // Get retrieves the Attachment with the provided ID. To extract the Attachment
 // object from the response, call the Extract method on the GetResult.
 func Get(ctx context.Context, client *gophercloud.ServiceClient, id string) (r GetResult) {
+	if err := client.CheckMicroversion(ctx, minMicroversion); err != nil {
+		r.Err = err
+		return
+	}
 	resp, err := client.Get(ctx, getURL(client, id), &r.Body, nil)
 	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
 	return
@@ -156,6 +182,10 @@ func (opts UpdateOpts) ToAttachmentUpdateMap() (map[string]any, error) {
 // updated Attachment from the response, call the Extract method on the
 // UpdateResult.
 func Update(ctx context.Context, client *gophercloud.ServiceClient, id string, opts UpdateOptsBuilder) (r UpdateResult) {
+	if err := client.CheckMicroversion(ctx, minMicroversion); err != nil {
+		r.Err = err
+		return
+	}
 	b, err := opts.ToAttachmentUpdateMap()
 	if err != nil {
 		r.Err = err
@@ -171,6 +201,10 @@ func Update(ctx context.Context, client *gophercloud.ServiceClient, id string, o
 // Complete will complete an attachment for a cinder volume.
 // Available starting in the 3.44 microversion.
 func Complete(ctx context.Context, client *gophercloud.ServiceClient, id string) (r CompleteResult) {
+	if err := client.CheckMicroversion(ctx, "3.44"); err != nil {
+		r.Err = err
+		return
+	}
 	b := map[string]any{
 		"os-complete": nil,
 	}
@@ -2,8 +2,10 @@ package testing
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/gophercloud/gophercloud/v2"
 	"github.com/gophercloud/gophercloud/v2/openstack/blockstorage/v3/attachments"
 	th "github.com/gophercloud/gophercloud/v2/testhelper"
 	"github.com/gophercloud/gophercloud/v2/testhelper/client"
@@ -118,3 +120,31 @@ func TestComplete(t *testing.T) {
 	err := attachments.Complete(context.TODO(), client.ServiceClient(fakeServer), "05551600-a936-4d4a-ba42-79a037c1-c91a").ExtractErr()
 	th.AssertNoErr(t, err)
 }
+
+func TestMicroversionUnsupported(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	c := client.ServiceClient(fakeServer)
+	c.Microversion = "3.26"
+
+	var unsupported gophercloud.ErrMicroversionUnsupported
+	_, err := attachments.Get(context.TODO(), c, "05551600-a936-4d4a-ba42-79a037c1-c91a").Extract()
+	th.AssertEquals(t, true, errors.As(err, &unsupported))
+	th.AssertEquals(t, "3.27", unsupported.Required)
+
+	c.Microversion = "3.43"
+	err = attachments.Complete(context.TODO(), c, "05551600-a936-4d4a-ba42-79a037c1-c91a").ExtractErr()
+	th.AssertEquals(t, true, errors.As(err, &unsupported))
+	th.AssertEquals(t, "3.44", unsupported.Required)
+
+	c.Microversion = "3.53"
+	options := &attachments.CreateOpts{
+		InstanceUUID: "83ec2e3b-4321-422b-8706-a84185f52a0a",
+		VolumeUUID:   "289da7f8-6440-407c-9fb4-7db01ec49164",
+		Mode:         "ro",
+	}
+	_, err = attachments.Create(context.TODO(), c, options).Extract()
+	th.AssertEquals(t, true, errors.As(err, &unsupported))
+	th.AssertEquals(t, "3.54", unsupported.Required)
+}
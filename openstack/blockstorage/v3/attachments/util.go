@@ -6,18 +6,20 @@ import (
 	"github.com/gophercloud/gophercloud/v2"
 )
 
+// FailureStatuses are the statuses from which an attachment cannot reach another status
+// without an intervention.
+var FailureStatuses = []string{"error_attaching", "error_detaching"}
+
 // WaitForStatus will continually poll the resource, checking for a particular status.
+// It returns a gophercloud.ErrResourceFailed error as soon as the attachment reaches
+// one of the FailureStatuses, unless it is the awaited status. The polling can be
+// configured by setting a gophercloud.Poller in the context.
 func WaitForStatus(ctx context.Context, c *gophercloud.ServiceClient, id, status string) error {
-	return gophercloud.WaitFor(ctx, func(ctx context.Context) (bool, error) {
+	return gophercloud.WaitForStatus(ctx, id, status, FailureStatuses, func(ctx context.Context) (gophercloud.ResourceStatus, error) {
 		current, err := Get(ctx, c, id).Extract()
 		if err != nil {
-			return false, err
-		}
-
-		if current.Status == status {
-			return true, nil
+			return gophercloud.ResourceStatus{}, err
 		}
-
-		return false, nil
+		return gophercloud.ResourceStatus{Status: current.Status}, nil
 	})
 }
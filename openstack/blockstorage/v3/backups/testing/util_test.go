@@ -0,0 +1,55 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/blockstorage/v3/backups"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/testhelper/client"
+)
+
+func TestWaitForStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		statuses []string
+		status   string
+		failed   string
+	}{
+		{"available", []string{"creating", "available"}, "available", ""},
+		{"error", []string{"creating", "error"}, "available", "error"},
+		{"restore error", []string{"restoring", "error"}, "available", "error"},
+		{"error deleting", []string{"deleting", "error_deleting"}, "deleted", "error_deleting"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeServer := th.SetupHTTP()
+			defer fakeServer.Teardown()
+
+			var polls int
+			fakeServer.Mux.HandleFunc("/backups/d32019d3-bc6e-4319-9c1d-6722fc136a22", func(w http.ResponseWriter, r *http.Request) {
+				th.TestMethod(t, r, "GET")
+				th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+				w.Header().Add("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"backup": {"id": "d32019d3-bc6e-4319-9c1d-6722fc136a22", "status": "%s", "fail_reason": "Backup driver failed"}}`, tt.statuses[polls])
+				polls++
+			})
+
+			ctx := gophercloud.WithPoller(context.TODO(), &gophercloud.Poller{InitialInterval: time.Millisecond})
+			err := backups.WaitForStatus(ctx, client.ServiceClient(fakeServer), "d32019d3-bc6e-4319-9c1d-6722fc136a22", tt.status)
+			th.AssertEquals(t, len(tt.statuses), polls)
+			if tt.failed == "" {
+				th.AssertNoErr(t, err)
+				return
+			}
+			var failed gophercloud.ErrResourceFailed
+			th.AssertEquals(t, true, errors.As(err, &failed))
+			th.AssertEquals(t, tt.failed, failed.Status)
+			th.AssertEquals(t, "Backup driver failed", failed.Fault)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package volumes
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// Client performs the basic volume operations. Application code can depend
+// on it rather than on a ServiceClient, so that it can be tested with the
+// in-memory implementation of the volumes/fake package instead of an HTTP
+// fixture.
+type Client interface {
+	// Get returns the volume with the given ID.
+	Get(ctx context.Context, id string) (*Volume, error)
+
+	// List returns all the volumes matching opts, following the pagination.
+	List(ctx context.Context, opts ListOptsBuilder) ([]Volume, error)
+
+	// Create creates a volume.
+	Create(ctx context.Context, opts CreateOptsBuilder, hintOpts SchedulerHintOptsBuilder) (*Volume, error)
+
+	// Update changes the attributes of the volume with the given ID.
+	Update(ctx context.Context, id string, opts UpdateOptsBuilder) (*Volume, error)
+
+	// Delete deletes the volume with the given ID.
+	Delete(ctx context.Context, id string, opts DeleteOptsBuilder) error
+}
+
+// NewClient returns a Client which performs the volume operations with the
+// functions of this package and the given block storage client.
+func NewClient(client *gophercloud.ServiceClient) Client {
+	return serviceClient{client: client}
+}
+
+type serviceClient struct {
+	client *gophercloud.ServiceClient
+}
+
+func (c serviceClient) Get(ctx context.Context, id string) (*Volume, error) {
+	return Get(ctx, c.client, id).Extract()
+}
+
+func (c serviceClient) List(ctx context.Context, opts ListOptsBuilder) ([]Volume, error) {
+	pages, err := List(c.client, opts).AllPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ExtractVolumes(pages)
+}
+
+func (c serviceClient) Create(ctx context.Context, opts CreateOptsBuilder, hintOpts SchedulerHintOptsBuilder) (*Volume, error) {
+	return Create(ctx, c.client, opts, hintOpts).Extract()
+}
+
+func (c serviceClient) Update(ctx context.Context, id string, opts UpdateOptsBuilder) (*Volume, error) {
+	return Update(ctx, c.client, id, opts).Extract()
+}
+
+func (c serviceClient) Delete(ctx context.Context, id string, opts DeleteOptsBuilder) error {
+	return Delete(ctx, c.client, id, opts).ExtractErr()
+}
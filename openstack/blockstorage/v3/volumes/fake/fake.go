@@ -0,0 +1,154 @@
+/*
+Package fake provides an in-memory implementation of volumes.Client, for the
+tests of code which depends on that interface.
+
+Volumes are created in the available status, and missing volumes are reported
+with the same 404 error as the Block Storage API:
+
+	client := fake.New(volumes.Volume{ID: "existing", Name: "data", Size: 10})
+	volume, err := client.Create(ctx, volumes.CreateOpts{Name: "logs", Size: 1}, nil)
+
+	_, err = client.Get(ctx, "missing")
+	gophercloud.ResponseCodeIs(err, http.StatusNotFound) // true
+*/
+package fake
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2/internal/fakestore"
+	"github.com/gophercloud/gophercloud/v2/openstack/blockstorage/v3/volumes"
+)
+
+// Client is an in-memory volumes.Client. It is safe for concurrent use.
+type Client struct {
+	store *fakestore.Store[volumes.Volume]
+}
+
+var _ volumes.Client = (*Client)(nil)
+
+// New returns a Client holding the given volumes.
+func New(initial ...volumes.Volume) *Client {
+	c := &Client{store: fakestore.New("volumes", clone)}
+	for _, v := range initial {
+		c.store.Put(v.ID, v)
+	}
+	return c
+}
+
+// clone copies the maps and slices of a volume.
+func clone(v volumes.Volume) volumes.Volume {
+	v.Attachments = slices.Clone(v.Attachments)
+	v.Metadata = maps.Clone(v.Metadata)
+	v.VolumeImageMetadata = maps.Clone(v.VolumeImageMetadata)
+	return v
+}
+
+// Get implements volumes.Client.
+func (c *Client) Get(_ context.Context, id string) (*volumes.Volume, error) {
+	return c.store.Get(id)
+}
+
+// List implements volumes.Client. When opts is a volumes.ListOpts, the
+// volumes are filtered by Name, Status, Metadata and Bootable.
+func (c *Client) List(_ context.Context, opts volumes.ListOptsBuilder) ([]volumes.Volume, error) {
+	listOpts, _ := opts.(volumes.ListOpts)
+	return c.store.List(func(v volumes.Volume) bool {
+		for k, value := range listOpts.Metadata {
+			if v.Metadata[k] != value {
+				return false
+			}
+		}
+		return (listOpts.Name == "" || listOpts.Name == v.Name) &&
+			(listOpts.Status == "" || listOpts.Status == v.Status) &&
+			(listOpts.Bootable == nil || *listOpts.Bootable == (v.Bootable == "true"))
+	}), nil
+}
+
+// Create implements volumes.Client. The scheduler hints are ignored.
+func (c *Client) Create(_ context.Context, opts volumes.CreateOptsBuilder, _ volumes.SchedulerHintOptsBuilder) (*volumes.Volume, error) {
+	b, err := opts.ToVolumeCreateMap()
+	if err != nil {
+		return nil, err
+	}
+	var v struct {
+		Size             int               `json:"size"`
+		AvailabilityZone string            `json:"availability_zone"`
+		Description      string            `json:"description"`
+		Metadata         map[string]string `json:"metadata"`
+		Name             string            `json:"name"`
+		SnapshotID       string            `json:"snapshot_id"`
+		SourceVolID      string            `json:"source_volid"`
+		ImageID          string            `json:"imageRef"`
+		BackupID         string            `json:"backup_id"`
+		VolumeType       string            `json:"volume_type"`
+	}
+	if err := fakestore.Decode(b, "volume", &v); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	volume := volumes.Volume{
+		ID:               fakestore.NewID(),
+		Status:           "available",
+		Size:             v.Size,
+		AvailabilityZone: v.AvailabilityZone,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		Attachments:      []volumes.Attachment{},
+		Name:             v.Name,
+		Description:      v.Description,
+		VolumeType:       v.VolumeType,
+		SnapshotID:       v.SnapshotID,
+		SourceVolID:      v.SourceVolID,
+		Metadata:         v.Metadata,
+		Bootable:         "false",
+	}
+	if v.BackupID != "" {
+		volume.BackupID = &v.BackupID
+	}
+	if v.ImageID != "" {
+		volume.Bootable = "true"
+	}
+	c.store.Put(volume.ID, volume)
+	return &volume, nil
+}
+
+// Update implements volumes.Client. The given metadata replaces the metadata
+// of the volume.
+func (c *Client) Update(_ context.Context, id string, opts volumes.UpdateOptsBuilder) (*volumes.Volume, error) {
+	b, err := opts.ToVolumeUpdateMap()
+	if err != nil {
+		return nil, err
+	}
+	var u struct {
+		Name        *string           `json:"name"`
+		Description *string           `json:"description"`
+		Metadata    map[string]string `json:"metadata"`
+	}
+	if err := fakestore.Decode(b, "volume", &u); err != nil {
+		return nil, err
+	}
+
+	return c.store.Update(id, func(v *volumes.Volume) error {
+		if u.Name != nil {
+			v.Name = *u.Name
+		}
+		if u.Description != nil {
+			v.Description = *u.Description
+		}
+		if u.Metadata != nil {
+			v.Metadata = maps.Clone(u.Metadata)
+		}
+		v.UpdatedAt = time.Now().UTC()
+		return nil
+	})
+}
+
+// Delete implements volumes.Client. The options are ignored.
+func (c *Client) Delete(_ context.Context, id string, _ volumes.DeleteOptsBuilder) error {
+	return c.store.Delete(id)
+}
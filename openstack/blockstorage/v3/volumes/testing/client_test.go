@@ -0,0 +1,43 @@
+package testing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/v2/openstack/blockstorage/v3/volumes/fake"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+func TestFakeClient(t *testing.T) {
+	var c volumes.Client = fake.New()
+
+	created, err := c.Create(context.TODO(), volumes.CreateOpts{
+		Name:     "data",
+		Size:     10,
+		Metadata: map[string]string{"tier": "gold"},
+	}, nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "available", created.Status)
+	th.AssertEquals(t, 10, created.Size)
+
+	_, err = c.Create(context.TODO(), volumes.CreateOpts{Name: "logs", Size: 1}, nil)
+	th.AssertNoErr(t, err)
+
+	filtered, err := c.List(context.TODO(), volumes.ListOpts{Metadata: map[string]string{"tier": "gold"}})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(filtered))
+	th.AssertEquals(t, created.ID, filtered[0].ID)
+
+	description := "application data"
+	updated, err := c.Update(context.TODO(), created.ID, volumes.UpdateOpts{Description: &description})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "application data", updated.Description)
+	th.AssertEquals(t, "gold", updated.Metadata["tier"])
+
+	th.AssertNoErr(t, c.Delete(context.TODO(), created.ID, nil))
+	_, err = c.Get(context.TODO(), created.ID)
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusNotFound))
+}
@@ -4,20 +4,52 @@ import (
 	"context"
 
 	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/internal/find"
 )
 
+// FailureStatuses are the statuses from which a volume cannot reach another status
+// without an intervention.
+var FailureStatuses = []string{"error", "error_restoring", "error_extending"}
+
 // WaitForStatus will continually poll the resource, checking for a particular status.
+// It returns a gophercloud.ErrResourceFailed error as soon as the volume reaches
+// one of the FailureStatuses, unless it is the awaited status. The polling can be
+// configured by setting a gophercloud.Poller in the context.
 func WaitForStatus(ctx context.Context, c *gophercloud.ServiceClient, id, status string) error {
-	return gophercloud.WaitFor(ctx, func(ctx context.Context) (bool, error) {
+	return gophercloud.WaitForStatus(ctx, id, status, FailureStatuses, func(ctx context.Context) (gophercloud.ResourceStatus, error) {
 		current, err := Get(ctx, c, id).Extract()
 		if err != nil {
-			return false, err
+			return gophercloud.ResourceStatus{}, err
 		}
+		return gophercloud.ResourceStatus{Status: current.Status}, nil
+	})
+}
 
-		if current.Status == status {
-			return true, nil
-		}
+// IDFromName returns the ID of the volume with the given name. It returns a
+// gophercloud.ErrResourceNotFound error if there is none, and a
+// gophercloud.ErrMultipleResourcesFound error if there are several.
+func IDFromName(ctx context.Context, client *gophercloud.ServiceClient, name string) (string, error) {
+	found, err := findByName(ctx, client, name)
+	if err != nil {
+		return "", err
+	}
+	return found.ID, nil
+}
 
-		return false, nil
+// Find returns the volume with the given ID or, if there is none, the volume
+// with the given name. It returns a gophercloud.ErrResourceNotFound error if
+// there is none, and a gophercloud.ErrMultipleResourcesFound error if several
+// volumes have the given name.
+func Find(ctx context.Context, client *gophercloud.ServiceClient, nameOrID string) (*Volume, error) {
+	return find.ByNameOrID(ctx, nameOrID, func(ctx context.Context, id string) (*Volume, error) {
+		return Get(ctx, client, id).Extract()
+	}, func(ctx context.Context, name string) (*Volume, error) {
+		return findByName(ctx, client, name)
 	})
 }
+
+// findByName returns the volume with the given name.
+func findByName(ctx context.Context, client *gophercloud.ServiceClient, name string) (*Volume, error) {
+	pager := List(client, ListOpts{Name: name})
+	return find.ByName(ctx, pager, ExtractVolumes, func(v Volume) string { return v.Name }, "volume", name)
+}
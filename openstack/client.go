@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/gophercloud/gophercloud/v2"
 	tokens2 "github.com/gophercloud/gophercloud/v2/openstack/identity/v2/tokens"
@@ -185,12 +186,29 @@ func v3auth(ctx context.Context, client *gophercloud.ProviderClient, endpoint st
 		v3Client.Endpoint = endpoint
 	}
 
+	// authOpts are the options the token is requested with. A federated
+	// authentication provides an unscoped token, which is then rescoped like
+	// any other token, while opts are kept to reauthenticate.
+	authOpts := opts
+	if ao, ok := opts.(*gophercloud.AuthOptions); ok && ao.Federated != nil {
+		// the federated credentials are not part of the key of the cache,
+		// and the federated token is obtained before the cache could be
+		// checked
+		if ao.TokenCache != nil {
+			return errors.New("a TokenCache cannot be used with a federated authentication")
+		}
+		authOpts, err = federatedAuthOptions(ctx, v3Client, ao)
+		if err != nil {
+			return err
+		}
+	}
+
 	var catalog *tokens3.ServiceCatalog
 
 	var tokenID string
 	// passthroughToken allows to passthrough the token without a scope
 	var passthroughToken bool
-	switch v := opts.(type) {
+	switch v := authOpts.(type) {
 	case *gophercloud.AuthOptions:
 		tokenID = v.TokenID
 		passthroughToken = (v.Scope == nil || *v.Scope == gophercloud.AuthScope{})
@@ -201,7 +219,7 @@ func v3auth(ctx context.Context, client *gophercloud.ProviderClient, endpoint st
 
 	if tokenID != "" && passthroughToken {
 		// passing through the token ID without requesting a new scope
-		if opts.CanReauth() {
+		if authOpts.CanReauth() {
 			return fmt.Errorf("cannot use AllowReauth, when the token ID is defined and auth scope is not set")
 		}
 
@@ -222,13 +240,36 @@ func v3auth(ctx context.Context, client *gophercloud.ProviderClient, endpoint st
 		}
 	} else {
 		var result tokens3.CreateResult
-		switch opts.(type) {
-		case *ec2tokens.AuthOptions:
-			result = ec2tokens.Create(ctx, v3Client, opts)
-		case *oauth1.AuthOptions:
-			result = oauth1.Create(ctx, v3Client, opts)
-		default:
-			result = tokens3.Create(ctx, v3Client, opts)
+		cache, cacheKey := tokenCacheFor(authOpts)
+		var cached *gophercloud.CachedToken
+		// A throwaway client is used to reauthenticate, which means that
+		// the cached token, if any, has been rejected.
+		if cache != nil && !client.IsThrowaway() {
+			// a failing cache must not prevent authentication
+			cached, _ = cache.Load(ctx, cacheKey)
+			if cached.Valid(time.Minute) && !validCachedToken(ctx, client, v3Client, cached) {
+				_ = cache.Delete(ctx, cacheKey)
+				cached = nil
+			}
+		}
+
+		if cached.Valid(time.Minute) {
+			result = tokens3.CreateResultFromCachedToken(cached)
+		} else {
+			switch authOpts.(type) {
+			case *ec2tokens.AuthOptions:
+				result = ec2tokens.Create(ctx, v3Client, authOpts)
+			case *oauth1.AuthOptions:
+				result = oauth1.Create(ctx, v3Client, authOpts)
+			default:
+				result = tokens3.Create(ctx, v3Client, authOpts)
+			}
+
+			if cache != nil && result.Err == nil {
+				if t, err := result.ExtractCachedToken(); err == nil {
+					_ = cache.Store(ctx, cacheKey, t)
+				}
+			}
 		}
 
 		err = client.SetTokenAndAuthResult(result)
@@ -274,7 +315,12 @@ func v3auth(ctx context.Context, client *gophercloud.ProviderClient, endpoint st
 		default:
 			tao = opts
 		}
+		cache, cacheKey := tokenCacheFor(opts)
 		client.ReauthFunc = func(ctx context.Context) error {
+			// the cached token, if any, has been rejected
+			if cache != nil {
+				_ = cache.Delete(ctx, cacheKey)
+			}
 			err := v3auth(ctx, &tac, endpoint, tao, eo)
 			if err != nil {
 				return err
@@ -290,6 +336,66 @@ func v3auth(ctx context.Context, client *gophercloud.ProviderClient, endpoint st
 	return nil
 }
 
+// federatedAuthOptions obtains an unscoped token with the federated
+// authentication of opts, and returns the options to rescope it according to
+// the scope of opts.
+func federatedAuthOptions(ctx context.Context, client *gophercloud.ServiceClient, opts *gophercloud.AuthOptions) (*gophercloud.AuthOptions, error) {
+	tokenID, err := opts.Federated.FederatedToken(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// the scope is computed on a copy, since it depends on options, such as
+	// the domain, which cannot be used along with a token. The scope itself
+	// is copied, so that neither opts nor the returned options share it.
+	scoped := *opts
+	if opts.Scope != nil {
+		scope := *opts.Scope
+		scoped.Scope = &scope
+	}
+	if _, err := scoped.ToTokenV3ScopeMap(); err != nil {
+		return nil, err
+	}
+
+	return &gophercloud.AuthOptions{
+		TokenID: tokenID,
+		Scope:   scoped.Scope,
+	}, nil
+}
+
+// validCachedToken reports whether the Identity service still accepts a
+// token loaded from a cache, which may have been revoked since it was stored.
+func validCachedToken(ctx context.Context, client *gophercloud.ProviderClient, v3Client *gophercloud.ServiceClient, cached *gophercloud.CachedToken) bool {
+	// the token authenticates its own validation, on a throwaway client
+	// which neither sends the token of client nor reauthenticates if the
+	// cached token is rejected
+	vc := *client
+	vc.SetThrowaway(true)
+	vc.ReauthFunc = nil
+	sc := *v3Client
+	sc.ProviderClient = &vc
+
+	ok, err := tokens3.Validate(ctx, &sc, cached.TokenID, selfValidateOpts(cached.TokenID))
+	return err == nil && ok
+}
+
+// selfValidateOpts authenticates the validation of a token with the token
+// itself.
+type selfValidateOpts string
+
+func (opts selfValidateOpts) ToTokenValidateParams() (map[string]string, error) {
+	return map[string]string{"X-Auth-Token": string(opts)}, nil
+}
+
+// tokenCacheFor returns the token cache configured in the options, if any,
+// and the key of the tokens obtained with these options.
+func tokenCacheFor(opts tokens3.AuthOptionsBuilder) (gophercloud.TokenCache, string) {
+	if ao, ok := opts.(*gophercloud.AuthOptions); ok && ao.TokenCache != nil {
+		return ao.TokenCache, ao.TokenCacheKey()
+	}
+	return nil, ""
+}
+
 // NewIdentityV2 creates a ServiceClient that may be used to interact with the
 // v2 identity service.
 func NewIdentityV2(ctx context.Context, client *gophercloud.ProviderClient, eo gophercloud.EndpointOpts) (*gophercloud.ServiceClient, error) {
@@ -345,6 +451,41 @@ func NewIdentityV3(ctx context.Context, client *gophercloud.ProviderClient, eo g
 	}, nil
 }
 
+// microversionedServices maps the types of the services which support
+// microversions to the major version of their API, which is also the major
+// version of their microversions. The clients of other services and of other
+// major versions, such as the block-storage v2 client, ignore
+// EndpointOpts.MaxMicroversion, which allows sharing the same EndpointOpts
+// between all services.
+var microversionedServices = map[string]int{
+	"application-container":               1,
+	"baremetal":                           1,
+	"baremetal-introspection":             1,
+	"block-storage":                       3,
+	"compute":                             2,
+	"container-infrastructure-management": 1,
+	"key-manager":                         1,
+	"placement":                           1,
+	"shared-file-system":                  2,
+}
+
+// negotiatesMicroversion reports whether the client of the given service type
+// and major version negotiates a microversion up to maxMicroversion. A
+// ceiling other than "latest" only applies to the services whose
+// microversions have the same major version, so that a compute ceiling such
+// as 2.90 is not applied to the 3.x microversions of block-storage.
+func negotiatesMicroversion(clientType string, version int, maxMicroversion string) bool {
+	if maxMicroversion == "" || microversionedServices[clientType] != version {
+		return false
+	}
+	if maxMicroversion == "latest" {
+		return true
+	}
+	major, _, err := utils.ParseMicroversion(maxMicroversion)
+	// an invalid ceiling is reported by the negotiation
+	return err != nil || major == version
+}
+
 // TODO(stephenfin): Allow passing aliases to all New${SERVICE}V${VERSION} methods in v3
 func initClientOpts(ctx context.Context, client *gophercloud.ProviderClient, eo gophercloud.EndpointOpts, clientType string, version int) (*gophercloud.ServiceClient, error) {
 	sc := new(gophercloud.ServiceClient)
@@ -363,6 +504,13 @@ func initClientOpts(ctx context.Context, client *gophercloud.ProviderClient, eo
 	sc.ProviderClient = client
 	sc.Endpoint = url
 	sc.Type = clientType
+
+	if negotiatesMicroversion(clientType, version, eo.MaxMicroversion) {
+		sc.Microversion, err = utils.NegotiateMicroversion(ctx, sc, eo.MaxMicroversion)
+		if err != nil {
+			return sc, err
+		}
+	}
 	return sc, nil
 }
 
@@ -0,0 +1,87 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/flavors"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/testhelper/client"
+)
+
+// handleFlavorsByName serves the list of the public and private flavors, and
+// answers 404 to a Get of any flavor but 1.
+func handleFlavorsByName(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/flavors/detail", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		th.TestFormValues(t, r, map[string]string{"is_public": "None"})
+
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, `
+			{
+				"flavors": [
+					{"id": "1", "name": "m1.tiny", "os-flavor-access:is_public": true},
+					{"id": "2", "name": "m1.private", "os-flavor-access:is_public": false},
+					{"id": "3", "name": "m1.large", "os-flavor-access:is_public": true},
+					{"id": "4", "name": "m1.large", "os-flavor-access:is_public": false}
+				]
+			}
+		`)
+	})
+	fakeServer.Mux.HandleFunc("/flavors/", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		if r.URL.Path != "/flavors/1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, `{"flavor": {"id": "1", "name": "m1.tiny"}}`)
+	})
+}
+
+func TestIDFromName(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	handleFlavorsByName(t, fakeServer)
+
+	id, err := flavors.IDFromName(context.TODO(), client.ServiceClient(fakeServer), "m1.tiny")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "1", id)
+
+	// private flavors are found too
+	id, err = flavors.IDFromName(context.TODO(), client.ServiceClient(fakeServer), "m1.private")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "2", id)
+
+	_, err = flavors.IDFromName(context.TODO(), client.ServiceClient(fakeServer), "m1.small")
+	var notFound gophercloud.ErrResourceNotFound
+	th.AssertEquals(t, true, errors.As(err, &notFound))
+	th.AssertEquals(t, "flavor", notFound.ResourceType)
+
+	_, err = flavors.IDFromName(context.TODO(), client.ServiceClient(fakeServer), "m1.large")
+	var multiple gophercloud.ErrMultipleResourcesFound
+	th.AssertEquals(t, true, errors.As(err, &multiple))
+	th.AssertEquals(t, 2, multiple.Count)
+}
+
+func TestFind(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	handleFlavorsByName(t, fakeServer)
+
+	f, err := flavors.Find(context.TODO(), client.ServiceClient(fakeServer), "1")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "m1.tiny", f.Name)
+
+	f, err = flavors.Find(context.TODO(), client.ServiceClient(fakeServer), "m1.private")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "2", f.ID)
+
+	_, err = flavors.Find(context.TODO(), client.ServiceClient(fakeServer), "m1.large")
+	th.AssertEquals(t, true, errors.As(err, &gophercloud.ErrMultipleResourcesFound{}))
+}
@@ -0,0 +1,38 @@
+package flavors
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/internal/find"
+)
+
+// IDFromName returns the ID of the flavor with the given name. It returns a
+// gophercloud.ErrResourceNotFound error if there is none, and a
+// gophercloud.ErrMultipleResourcesFound error if there are several.
+func IDFromName(ctx context.Context, client *gophercloud.ServiceClient, name string) (string, error) {
+	found, err := findByName(ctx, client, name)
+	if err != nil {
+		return "", err
+	}
+	return found.ID, nil
+}
+
+// Find returns the flavor with the given ID or, if there is none, the flavor
+// with the given name. It returns a gophercloud.ErrResourceNotFound error if
+// there is none, and a gophercloud.ErrMultipleResourcesFound error if several
+// flavors have the given name.
+func Find(ctx context.Context, client *gophercloud.ServiceClient, nameOrID string) (*Flavor, error) {
+	return find.ByNameOrID(ctx, nameOrID, func(ctx context.Context, id string) (*Flavor, error) {
+		return Get(ctx, client, id).Extract()
+	}, func(ctx context.Context, name string) (*Flavor, error) {
+		return findByName(ctx, client, name)
+	})
+}
+
+// findByName returns the flavor with the given name, among the public and
+// private flavors.
+func findByName(ctx context.Context, client *gophercloud.ServiceClient, name string) (*Flavor, error) {
+	pager := ListDetail(client, ListOpts{AccessType: AllAccess})
+	return find.ByName(ctx, pager, ExtractFlavors, func(v Flavor) string { return v.Name }, "flavor", name)
+}
@@ -72,6 +72,11 @@ func (opts CreateOpts) ToRemoteConsoleCreateMap() (map[string]any, error) {
 
 // Create requests the creation of a new remote console on the specified server.
 func Create(ctx context.Context, client *gophercloud.ServiceClient, serverID string, opts CreateOptsBuilder) (r CreateResult) {
+	if err := client.CheckMicroversion(ctx, "2.6"); err != nil {
+		r.Err = err
+		return
+	}
+
 	reqBody, err := opts.ToRemoteConsoleCreateMap()
 	if err != nil {
 		r.Err = err
@@ -0,0 +1,62 @@
+package servers
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// Client performs the basic server operations. Application code can depend
+// on it rather than on a ServiceClient, so that it can be tested with the
+// in-memory implementation of the servers/fake package instead of an HTTP
+// fixture.
+type Client interface {
+	// Get returns the server with the given ID.
+	Get(ctx context.Context, id string) (*Server, error)
+
+	// List returns all the servers matching opts, following the pagination.
+	List(ctx context.Context, opts ListOptsBuilder) ([]Server, error)
+
+	// Create requests a server to be provisioned.
+	Create(ctx context.Context, opts CreateOptsBuilder, hintOpts SchedulerHintOptsBuilder) (*Server, error)
+
+	// Update changes the attributes of the server with the given ID.
+	Update(ctx context.Context, id string, opts UpdateOptsBuilder) (*Server, error)
+
+	// Delete requests the removal of the server with the given ID.
+	Delete(ctx context.Context, id string) error
+}
+
+// NewClient returns a Client which performs the server operations with the
+// functions of this package and the given compute client.
+func NewClient(client *gophercloud.ServiceClient) Client {
+	return serviceClient{client: client}
+}
+
+type serviceClient struct {
+	client *gophercloud.ServiceClient
+}
+
+func (c serviceClient) Get(ctx context.Context, id string) (*Server, error) {
+	return Get(ctx, c.client, id).Extract()
+}
+
+func (c serviceClient) List(ctx context.Context, opts ListOptsBuilder) ([]Server, error) {
+	pages, err := List(c.client, opts).AllPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ExtractServers(pages)
+}
+
+func (c serviceClient) Create(ctx context.Context, opts CreateOptsBuilder, hintOpts SchedulerHintOptsBuilder) (*Server, error) {
+	return Create(ctx, c.client, opts, hintOpts).Extract()
+}
+
+func (c serviceClient) Update(ctx context.Context, id string, opts UpdateOptsBuilder) (*Server, error) {
+	return Update(ctx, c.client, id, opts).Extract()
+}
+
+func (c serviceClient) Delete(ctx context.Context, id string) error {
+	return Delete(ctx, c.client, id).ExtractErr()
+}
@@ -0,0 +1,175 @@
+/*
+Package fake provides an in-memory implementation of servers.Client, for the
+tests of code which depends on that interface.
+
+Servers are created in the ACTIVE status, and missing servers are reported
+with the same 404 error as the Compute API:
+
+	client := fake.New(servers.Server{ID: "existing", Name: "web"})
+	server, err := client.Create(ctx, servers.CreateOpts{Name: "db"}, nil)
+
+	_, err = client.Get(ctx, "missing")
+	gophercloud.ResponseCodeIs(err, http.StatusNotFound) // true
+*/
+package fake
+
+import (
+	"context"
+	"maps"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2/internal/fakestore"
+	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/servers"
+)
+
+// Client is an in-memory servers.Client. It is safe for concurrent use.
+type Client struct {
+	store *fakestore.Store[servers.Server]
+}
+
+var _ servers.Client = (*Client)(nil)
+
+// New returns a Client holding the given servers.
+func New(initial ...servers.Server) *Client {
+	c := &Client{store: fakestore.New("servers", clone)}
+	for _, s := range initial {
+		c.store.Put(s.ID, s)
+	}
+	return c
+}
+
+// clone deeply copies the maps, slices and pointers of a server.
+func clone(s servers.Server) servers.Server {
+	s.Image = fakestore.CloneMap(s.Image)
+	s.Flavor = fakestore.CloneMap(s.Flavor)
+	s.Addresses = fakestore.CloneMap(s.Addresses)
+	s.Metadata = maps.Clone(s.Metadata)
+	s.Links, _ = fakestore.CloneValue(s.Links).([]any)
+	s.SecurityGroups = fakestore.CloneMaps(s.SecurityGroups)
+	s.AttachedVolumes = slices.Clone(s.AttachedVolumes)
+	if s.Tags != nil {
+		tags := slices.Clone(*s.Tags)
+		s.Tags = &tags
+	}
+	if s.ServerGroups != nil {
+		groups := slices.Clone(*s.ServerGroups)
+		s.ServerGroups = &groups
+	}
+	s.ReservationID = fakestore.ClonePtr(s.ReservationID)
+	s.LaunchIndex = fakestore.ClonePtr(s.LaunchIndex)
+	s.RAMDiskID = fakestore.ClonePtr(s.RAMDiskID)
+	s.KernelID = fakestore.ClonePtr(s.KernelID)
+	s.Hostname = fakestore.ClonePtr(s.Hostname)
+	s.RootDeviceName = fakestore.ClonePtr(s.RootDeviceName)
+	s.Userdata = fakestore.ClonePtr(s.Userdata)
+	s.Locked = fakestore.ClonePtr(s.Locked)
+	return s
+}
+
+// Get implements servers.Client.
+func (c *Client) Get(_ context.Context, id string) (*servers.Server, error) {
+	return c.store.Get(id)
+}
+
+// List implements servers.Client. When opts is a servers.ListOpts, the
+// servers are filtered by Name, as a regular expression, and by Status.
+func (c *Client) List(_ context.Context, opts servers.ListOptsBuilder) ([]servers.Server, error) {
+	listOpts, _ := opts.(servers.ListOpts)
+	var name *regexp.Regexp
+	if listOpts.Name != "" {
+		var err error
+		if name, err = regexp.Compile(listOpts.Name); err != nil {
+			return nil, err
+		}
+	}
+	return c.store.List(func(s servers.Server) bool {
+		return (name == nil || name.MatchString(s.Name)) &&
+			(listOpts.Status == "" || listOpts.Status == s.Status)
+	}), nil
+}
+
+// Create implements servers.Client. The scheduler hints are ignored.
+func (c *Client) Create(_ context.Context, opts servers.CreateOptsBuilder, _ servers.SchedulerHintOptsBuilder) (*servers.Server, error) {
+	b, err := opts.ToServerCreateMap()
+	if err != nil {
+		return nil, err
+	}
+	var s struct {
+		Name           string            `json:"name"`
+		ImageRef       string            `json:"imageRef"`
+		FlavorRef      string            `json:"flavorRef"`
+		Metadata       map[string]string `json:"metadata"`
+		KeyName        string            `json:"key_name"`
+		AccessIPv4     string            `json:"accessIPv4"`
+		AccessIPv6     string            `json:"accessIPv6"`
+		Tags           []string          `json:"tags"`
+		SecurityGroups []map[string]any  `json:"security_groups"`
+	}
+	if err := fakestore.Decode(b, "server", &s); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	server := servers.Server{
+		ID:             fakestore.NewID(),
+		Name:           s.Name,
+		Status:         "ACTIVE",
+		Created:        now,
+		Updated:        now,
+		Flavor:         map[string]any{"id": s.FlavorRef},
+		Metadata:       s.Metadata,
+		KeyName:        s.KeyName,
+		AccessIPv4:     s.AccessIPv4,
+		AccessIPv6:     s.AccessIPv6,
+		SecurityGroups: s.SecurityGroups,
+	}
+	if s.ImageRef != "" {
+		server.Image = map[string]any{"id": s.ImageRef}
+	}
+	if s.Tags != nil {
+		server.Tags = &s.Tags
+	}
+	c.store.Put(server.ID, server)
+	return &server, nil
+}
+
+// Update implements servers.Client.
+func (c *Client) Update(_ context.Context, id string, opts servers.UpdateOptsBuilder) (*servers.Server, error) {
+	b, err := opts.ToServerUpdateMap()
+	if err != nil {
+		return nil, err
+	}
+	var u struct {
+		Name       *string `json:"name"`
+		AccessIPv4 *string `json:"accessIPv4"`
+		AccessIPv6 *string `json:"accessIPv6"`
+		Hostname   *string `json:"hostname"`
+	}
+	if err := fakestore.Decode(b, "server", &u); err != nil {
+		return nil, err
+	}
+
+	return c.store.Update(id, func(s *servers.Server) error {
+		if u.Name != nil {
+			s.Name = *u.Name
+		}
+		if u.AccessIPv4 != nil {
+			s.AccessIPv4 = *u.AccessIPv4
+		}
+		if u.AccessIPv6 != nil {
+			s.AccessIPv6 = *u.AccessIPv6
+		}
+		if u.Hostname != nil {
+			s.Hostname = u.Hostname
+		}
+		s.Updated = time.Now().UTC()
+		return nil
+	})
+}
+
+// Delete implements servers.Client.
+func (c *Client) Delete(_ context.Context, id string) error {
+	return c.store.Delete(id)
+}
@@ -10,6 +10,7 @@ import (
 	"maps"
 	"net"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/gophercloud/gophercloud/v2"
@@ -496,13 +497,17 @@ type CreateOpts struct {
 	Max int `json:"max_count,omitempty"`
 
 	// Tags allows a server to be tagged with single-word metadata.
-	// Requires microversion 2.52 or later.
+	// Requires microversion 2.52 or later; Create returns a
+	// gophercloud.ErrMicroversionUnsupported error if the client is set to
+	// a lower one.
 	Tags []string `json:"tags,omitempty"`
 
 	// (Available from 2.90) Hostname specifies the hostname to configure for the
 	// instance in the metadata service. Starting with microversion 2.94, this can
 	// be a Fully Qualified Domain Name (FQDN) of up to 255 characters in length.
 	// If not set, OpenStack will derive the server's hostname from the Name field.
+	// Create returns a gophercloud.ErrMicroversionUnsupported error if it is
+	// set while the client is set to a lower microversion.
 	Hostname string `json:"hostname,omitempty"`
 
 	// BlockDevice describes the mapping of various block devices.
@@ -606,6 +611,14 @@ func Create(ctx context.Context, client *gophercloud.ServiceClient, opts CreateO
 		maps.Copy(b, sh)
 	}
 
+	if err := checkFieldMicroversions(ctx, client, b, map[string]string{
+		"tags":     "2.52",
+		"hostname": "2.90",
+	}); err != nil {
+		r.Err = err
+		return
+	}
+
 	resp, err := client.Post(ctx, createURL(client), b, &r.Body, &gophercloud.RequestOpts{
 		OkCodes: []int{200, 202},
 	})
@@ -613,6 +626,23 @@ func Create(ctx context.Context, client *gophercloud.ServiceClient, opts CreateO
 	return
 }
 
+// checkFieldMicroversions returns a gophercloud.ErrMicroversionUnsupported
+// error if the server object of a request body sets one of the given fields,
+// mapped to the first microversion which accepts it, while the client is set
+// to a lower microversion.
+func checkFieldMicroversions(ctx context.Context, client *gophercloud.ServiceClient, body map[string]any, fields map[string]string) error {
+	server, _ := body["server"].(map[string]any)
+	for _, field := range slices.Sorted(maps.Keys(fields)) {
+		if _, ok := server[field]; !ok {
+			continue
+		}
+		if err := client.CheckMicroversion(ctx, fields[field]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Delete requests that a server previously provisioned be removed from your
 // account.
 func Delete(ctx context.Context, client *gophercloud.ServiceClient, id string) (r DeleteResult) {
@@ -658,7 +688,9 @@ type UpdateOpts struct {
 	AccessIPv6 *string `json:"accessIPv6,omitempty"`
 
 	// Hostname changes the hostname of the server.
-	// Requires microversion 2.90 or later.
+	// Requires microversion 2.90 or later; Update returns a
+	// gophercloud.ErrMicroversionUnsupported error if the client is set to
+	// a lower one.
 	// Note: This information is published via the metadata service and requires
 	// application such as cloud-init to propagate it through to the instance.
 	Hostname *string `json:"hostname,omitempty"`
@@ -676,6 +708,12 @@ func Update(ctx context.Context, client *gophercloud.ServiceClient, id string, o
 		r.Err = err
 		return
 	}
+	if err := checkFieldMicroversions(ctx, client, b, map[string]string{
+		"hostname": "2.90",
+	}); err != nil {
+		r.Err = err
+		return
+	}
 	resp, err := client.Put(ctx, updateURL(client, id), b, &r.Body, &gophercloud.RequestOpts{
 		OkCodes: []int{200},
 	})
@@ -0,0 +1,109 @@
+package testing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/servers/fake"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/testhelper/client"
+)
+
+func TestClientGet(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleServerGetSuccessfully(t, fakeServer)
+
+	c := servers.NewClient(client.ServiceClient(fakeServer))
+	actual, err := c.Get(context.TODO(), "1234asdf")
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ServerDerp, *actual)
+}
+
+func TestClientList(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleServerListSuccessfully(t, fakeServer)
+
+	c := servers.NewClient(client.ServiceClient(fakeServer))
+	actual, err := c.List(context.TODO(), servers.ListOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, len(actual))
+	th.CheckDeepEquals(t, ServerHerp, actual[0])
+}
+
+func TestFakeClient(t *testing.T) {
+	var c servers.Client = fake.New(ServerDerp)
+
+	created, err := c.Create(context.TODO(), servers.CreateOpts{
+		Name:      "web-1",
+		ImageRef:  "f90f6034-2570-4974-8351-6b49732ef2eb",
+		FlavorRef: "1",
+		Metadata:  map[string]string{"role": "web"},
+		Tags:      []string{"web"},
+	}, nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "web-1", created.Name)
+	th.AssertEquals(t, "ACTIVE", created.Status)
+	th.AssertEquals(t, "f90f6034-2570-4974-8351-6b49732ef2eb", created.Image["id"])
+	th.AssertEquals(t, "web", created.Metadata["role"])
+
+	actual, err := c.Get(context.TODO(), created.ID)
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, created, actual)
+
+	// the servers returned do not share their metadata and tags with the
+	// stored ones
+	created.Metadata["role"] = "db"
+	(*actual.Tags)[0] = "db"
+	actual, err = c.Get(context.TODO(), created.ID)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "web", actual.Metadata["role"])
+	th.CheckDeepEquals(t, []string{"web"}, *actual.Tags)
+
+	all, err := c.List(context.TODO(), nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(all))
+
+	filtered, err := c.List(context.TODO(), servers.ListOpts{Name: "^web"})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(filtered))
+	th.AssertEquals(t, created.ID, filtered[0].ID)
+
+	name := "web-2"
+	updated, err := c.Update(context.TODO(), created.ID, servers.UpdateOpts{Name: &name})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "web-2", updated.Name)
+
+	th.AssertNoErr(t, c.Delete(context.TODO(), created.ID))
+	_, err = c.Get(context.TODO(), created.ID)
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusNotFound))
+	err = c.Delete(context.TODO(), created.ID)
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusNotFound))
+}
+
+func TestFakeClientDeepCopies(t *testing.T) {
+	c := fake.New(ServerDerp)
+
+	// the nested maps and slices of a returned server are not shared with
+	// the stored one
+	returned, err := c.Get(context.TODO(), ServerDerp.ID)
+	th.AssertNoErr(t, err)
+	imageLink := returned.Image["links"].([]any)[0].(map[string]any)
+	flavorLink := returned.Flavor["links"].([]any)[0].(map[string]any)
+	address := returned.Addresses["private"].([]any)[0].(map[string]any)
+	expectedImageLink, expectedFlavorLink, expectedAddress := imageLink["href"], flavorLink["href"], address["addr"]
+	imageLink["href"] = "mutated"
+	flavorLink["href"] = "mutated"
+	address["addr"] = "mutated"
+
+	actual, err := c.Get(context.TODO(), ServerDerp.ID)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, expectedImageLink, actual.Image["links"].([]any)[0].(map[string]any)["href"])
+	th.AssertEquals(t, expectedFlavorLink, actual.Flavor["links"].([]any)[0].(map[string]any)["href"])
+	th.AssertEquals(t, expectedAddress, actual.Addresses["private"].([]any)[0].(map[string]any)["addr"])
+	th.AssertEquals(t, "10.0.0.31", expectedAddress)
+}
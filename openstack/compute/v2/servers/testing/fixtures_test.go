@@ -3,6 +3,7 @@ package testing
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -1055,6 +1056,40 @@ func HandleServerGetFaultSuccessfully(t *testing.T, fakeServer th.FakeServer) {
 	})
 }
 
+// HandleServerGetErrorSuccessfully sets up the test server to respond to a server Get
+// request with a server in the ERROR status.
+func HandleServerGetErrorSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/servers/1234asdf", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		th.TestHeader(t, r, "Accept", "application/json")
+
+		fmt.Fprint(w, strings.Replace(FaultyServerBody, `"status": "ACTIVE"`, `"status": "ERROR"`, 1))
+	})
+}
+
+// HandleServerListByNameSuccessfully sets up the test server to respond to a
+// server List request filtered by name. It returns servers whose name
+// partially matches the requested one, like the compute API does.
+func HandleServerListByNameSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/servers/detail", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Add("Content-Type", "application/json")
+		switch r.URL.Query().Get("name") {
+		case `^web\.1$`:
+			fmt.Fprint(w, `{"servers": [{"id": "ef079b0c-e610-4dfb-b1aa-b49f07ac48e5", "name": "web.1"}]}`)
+		case `^web$`:
+			fmt.Fprint(w, `{"servers": [{"id": "ef079b0c-e610-4dfb-b1aa-b49f07ac48e5", "name": "web.1"}]}`)
+		case `^db$`:
+			fmt.Fprint(w, `{"servers": [{"id": "9e5476bd-a4ec-4653-93d6-72c93aa682ba", "name": "db"}, {"id": "e6b8d8e5-9b6f-4e2c-8d5a-1f0c2a3b4c5d", "name": "db"}]}`)
+		default:
+			t.Errorf("Unexpected name filter: %s", r.URL.Query().Get("name"))
+		}
+	})
+}
+
 // HandleServerUpdateSuccessfully sets up the test server to respond to a server Update request.
 func HandleServerUpdateSuccessfully(t *testing.T, fakeServer th.FakeServer) {
 	fakeServer.Mux.HandleFunc("/servers/1234asdf", func(w http.ResponseWriter, r *http.Request) {
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"testing"
 
+	"github.com/gophercloud/gophercloud/v2"
 	"github.com/gophercloud/gophercloud/v2/internal/ptr"
 	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/v2/pagination"
@@ -779,6 +781,24 @@ func TestGetFaultyServer(t *testing.T) {
 	th.CheckDeepEquals(t, FaultyServer, *actual)
 }
 
+func TestWaitForStatusFault(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleServerGetErrorSuccessfully(t, fakeServer)
+
+	client := client.ServiceClient(fakeServer)
+	err := servers.WaitForStatus(context.TODO(), client, "1234asdf", "ACTIVE")
+
+	var failed gophercloud.ErrResourceFailed
+	th.AssertEquals(t, true, errors.As(err, &failed))
+	th.AssertEquals(t, "1234asdf", failed.ID)
+	th.AssertEquals(t, "ERROR", failed.Status)
+	th.AssertEquals(t, DerpFault.Message, failed.Fault)
+
+	err = servers.WaitForStatus(context.TODO(), client, "1234asdf", "ERROR")
+	th.AssertNoErr(t, err)
+}
+
 func TestGetServerWithExtensions(t *testing.T) {
 	fakeServer := th.SetupHTTP()
 	defer fakeServer.Teardown()
@@ -1173,6 +1193,32 @@ func TestCreateServerWithTags(t *testing.T) {
 	th.CheckDeepEquals(t, ServerDerpTags, *actualServer)
 }
 
+func TestCreateServerMicroversionUnsupported(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	c := client.ServiceClient(fakeServer)
+	c.Microversion = "2.51"
+
+	createOpts := servers.CreateOpts{
+		Name:      "derp",
+		ImageRef:  "f90f6034-2570-4974-8351-6b49732ef2eb",
+		FlavorRef: "1",
+		Tags:      []string{"foo", "bar"},
+	}
+	err := servers.Create(context.TODO(), c, createOpts, nil).Err
+	var unsupported gophercloud.ErrMicroversionUnsupported
+	th.AssertEquals(t, true, errors.As(err, &unsupported))
+	th.AssertEquals(t, "2.52", unsupported.Required)
+
+	c.Microversion = "2.89"
+	createOpts.Tags = nil
+	createOpts.Hostname = "derp"
+	err = servers.Create(context.TODO(), c, createOpts, nil).Err
+	th.AssertEquals(t, true, errors.As(err, &unsupported))
+	th.AssertEquals(t, "2.90", unsupported.Required)
+}
+
 func TestCreateServerWithHypervisorHostname(t *testing.T) {
 	opts := servers.CreateOpts{
 		Name:               "createdserver",
@@ -1208,3 +1254,36 @@ func TestUpdateServerHostname(t *testing.T) {
 
 	th.CheckDeepEquals(t, ServerDerp, *actual)
 }
+
+func TestUpdateServerHostnameMicroversionUnsupported(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	c := client.ServiceClient(fakeServer)
+	c.Microversion = "2.89"
+
+	err := servers.Update(context.TODO(), c, "1234asdf", servers.UpdateOpts{Hostname: ptr.To("new-hostname")}).Err
+	var unsupported gophercloud.ErrMicroversionUnsupported
+	th.AssertEquals(t, true, errors.As(err, &unsupported))
+	th.AssertEquals(t, "2.90", unsupported.Required)
+}
+
+func TestIDFromName(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleServerListByNameSuccessfully(t, fakeServer)
+
+	id, err := servers.IDFromName(context.TODO(), client.ServiceClient(fakeServer), "web.1")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "ef079b0c-e610-4dfb-b1aa-b49f07ac48e5", id)
+
+	// the name filter of the compute API is a regular expression, so
+	// partial matches must be discarded
+	_, err = servers.IDFromName(context.TODO(), client.ServiceClient(fakeServer), "web")
+	th.AssertEquals(t, true, errors.As(err, &gophercloud.ErrResourceNotFound{}))
+
+	_, err = servers.IDFromName(context.TODO(), client.ServiceClient(fakeServer), "db")
+	var multiple gophercloud.ErrMultipleResourcesFound
+	th.AssertEquals(t, true, errors.As(err, &multiple))
+	th.AssertEquals(t, 2, multiple.Count)
+}
@@ -2,23 +2,59 @@ package servers
 
 import (
 	"context"
+	"regexp"
 
 	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/internal/find"
 )
 
+// FailureStatuses are the statuses from which a server cannot reach another
+// status without an intervention.
+var FailureStatuses = []string{"ERROR"}
+
 // WaitForStatus will continually poll a server until it successfully
-// transitions to a specified status.
+// transitions to a specified status. It returns a gophercloud.ErrResourceFailed
+// error carrying the fault of the server as soon as the server reaches one of
+// the FailureStatuses, unless it is the awaited status. The polling can be
+// configured by setting a gophercloud.Poller in the context.
 func WaitForStatus(ctx context.Context, c *gophercloud.ServiceClient, id, status string) error {
-	return gophercloud.WaitFor(ctx, func(ctx context.Context) (bool, error) {
+	return gophercloud.WaitForStatus(ctx, id, status, FailureStatuses, func(ctx context.Context) (gophercloud.ResourceStatus, error) {
 		current, err := Get(ctx, c, id).Extract()
 		if err != nil {
-			return false, err
+			return gophercloud.ResourceStatus{}, err
 		}
+		return gophercloud.ResourceStatus{
+			Status: current.Status,
+			Fault:  current.Fault.Message,
+		}, nil
+	})
+}
 
-		if current.Status == status {
-			return true, nil
-		}
+// IDFromName returns the ID of the server with the given name. It returns a
+// gophercloud.ErrResourceNotFound error if there is none, and a
+// gophercloud.ErrMultipleResourcesFound error if there are several.
+func IDFromName(ctx context.Context, client *gophercloud.ServiceClient, name string) (string, error) {
+	found, err := findByName(ctx, client, name)
+	if err != nil {
+		return "", err
+	}
+	return found.ID, nil
+}
 
-		return false, nil
+// Find returns the server with the given ID or, if there is none, the server
+// with the given name. It returns a gophercloud.ErrResourceNotFound error if
+// there is none, and a gophercloud.ErrMultipleResourcesFound error if several
+// servers have the given name.
+func Find(ctx context.Context, client *gophercloud.ServiceClient, nameOrID string) (*Server, error) {
+	return find.ByNameOrID(ctx, nameOrID, func(ctx context.Context, id string) (*Server, error) {
+		return Get(ctx, client, id).Extract()
+	}, func(ctx context.Context, name string) (*Server, error) {
+		return findByName(ctx, client, name)
 	})
 }
+
+// findByName returns the server with the given name.
+func findByName(ctx context.Context, client *gophercloud.ServiceClient, name string) (*Server, error) {
+	pager := List(client, ListOpts{Name: "^" + regexp.QuoteMeta(name) + "$"})
+	return find.ByName(ctx, pager, ExtractServers, func(v Server) string { return v.Name }, "server", name)
+}
@@ -6,8 +6,16 @@ import (
 	"github.com/gophercloud/gophercloud/v2"
 )
 
+// minMicroversion is the first compute microversion which supports server
+// tags.
+const minMicroversion = "2.26"
+
 // List all tags on a server.
 func List(ctx context.Context, client *gophercloud.ServiceClient, serverID string) (r ListResult) {
+	if err := client.CheckMicroversion(ctx, minMicroversion); err != nil {
+		r.Err = err
+		return
+	}
 	url := listURL(client, serverID)
 	resp, err := client.Get(ctx, url, &r.Body, &gophercloud.RequestOpts{
 		OkCodes: []int{200},
@@ -18,6 +26,10 @@ func List(ctx context.Context, client *gophercloud.ServiceClient, serverID strin
 
 // Check if a tag exists on a server.
 func Check(ctx context.Context, client *gophercloud.ServiceClient, serverID, tag string) (r CheckResult) {
+	if err := client.CheckMicroversion(ctx, minMicroversion); err != nil {
+		r.Err = err
+		return
+	}
 	url := checkURL(client, serverID, tag)
 	resp, err := client.Get(ctx, url, nil, &gophercloud.RequestOpts{
 		OkCodes: []int{204},
@@ -43,6 +55,10 @@ func (opts ReplaceAllOpts) ToTagsReplaceAllMap() (map[string]any, error) {
 
 // ReplaceAll replaces all Tags on a server.
 func ReplaceAll(ctx context.Context, client *gophercloud.ServiceClient, serverID string, opts ReplaceAllOptsBuilder) (r ReplaceAllResult) {
+	if err := client.CheckMicroversion(ctx, minMicroversion); err != nil {
+		r.Err = err
+		return
+	}
 	b, err := opts.ToTagsReplaceAllMap()
 	url := replaceAllURL(client, serverID)
 	if err != nil {
@@ -58,6 +74,10 @@ func ReplaceAll(ctx context.Context, client *gophercloud.ServiceClient, serverID
 
 // Add adds a new Tag on a server.
 func Add(ctx context.Context, client *gophercloud.ServiceClient, serverID, tag string) (r AddResult) {
+	if err := client.CheckMicroversion(ctx, minMicroversion); err != nil {
+		r.Err = err
+		return
+	}
 	url := addURL(client, serverID, tag)
 	resp, err := client.Put(ctx, url, nil, nil, &gophercloud.RequestOpts{
 		OkCodes: []int{201, 204},
@@ -68,6 +88,10 @@ func Add(ctx context.Context, client *gophercloud.ServiceClient, serverID, tag s
 
 // Delete removes a tag from a server.
 func Delete(ctx context.Context, client *gophercloud.ServiceClient, serverID, tag string) (r DeleteResult) {
+	if err := client.CheckMicroversion(ctx, minMicroversion); err != nil {
+		r.Err = err
+		return
+	}
 	url := deleteURL(client, serverID, tag)
 	resp, err := client.Delete(ctx, url, &gophercloud.RequestOpts{
 		OkCodes: []int{204},
@@ -78,6 +102,10 @@ func Delete(ctx context.Context, client *gophercloud.ServiceClient, serverID, ta
 
 // DeleteAll removes all tag from a server.
 func DeleteAll(ctx context.Context, client *gophercloud.ServiceClient, serverID string) (r DeleteResult) {
+	if err := client.CheckMicroversion(ctx, minMicroversion); err != nil {
+		r.Err = err
+		return
+	}
 	url := deleteAllURL(client, serverID)
 	resp, err := client.Delete(ctx, url, &gophercloud.RequestOpts{
 		OkCodes: []int{204},
@@ -30,11 +30,13 @@ type CreateOpts struct {
 	VolumeID string `json:"volumeId" required:"true"`
 
 	// Tag is a device role tag that can be applied to a volume when attaching
-	// it to the VM. Requires 2.49 microversion
+	// it to the VM. Requires 2.49 microversion; Create returns a
+	// gophercloud.ErrMicroversionUnsupported error if the client is set to
+	// a lower one.
 	Tag string `json:"tag,omitempty"`
 
 	// DeleteOnTermination specifies whether or not to delete the volume when the server
-	// is destroyed. Requires 2.79 microversion
+	// is destroyed. Requires 2.79 microversion, checked like Tag.
 	DeleteOnTermination bool `json:"delete_on_termination,omitempty"`
 }
 
@@ -50,6 +52,20 @@ func Create(ctx context.Context, client *gophercloud.ServiceClient, serverID str
 		r.Err = err
 		return
 	}
+	if attachment, ok := b["volumeAttachment"].(map[string]any); ok {
+		if _, ok := attachment["tag"]; ok {
+			if err := client.CheckMicroversion(ctx, "2.49"); err != nil {
+				r.Err = err
+				return
+			}
+		}
+		if _, ok := attachment["delete_on_termination"]; ok {
+			if err := client.CheckMicroversion(ctx, "2.79"); err != nil {
+				r.Err = err
+				return
+			}
+		}
+	}
 	resp, err := client.Post(ctx, createURL(client, serverID), b, &r.Body, &gophercloud.RequestOpts{
 		OkCodes: []int{200},
 	})
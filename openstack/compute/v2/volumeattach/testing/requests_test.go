@@ -2,8 +2,10 @@ package testing
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/gophercloud/gophercloud/v2"
 	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/volumeattach"
 	"github.com/gophercloud/gophercloud/v2/pagination"
 	th "github.com/gophercloud/gophercloud/v2/testhelper"
@@ -82,6 +84,30 @@ func TestCreate(t *testing.T) {
 	th.CheckDeepEquals(t, &CreatedVolumeAttachment, actual)
 }
 
+func TestCreateMicroversionUnsupported(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	c := client.ServiceClient(fakeServer)
+	c.Microversion = "2.48"
+
+	serverID := "4d8c3732-a248-40ed-bebc-539a6ffd25c0"
+	createOpts := volumeattach.CreateOpts{
+		VolumeID: "a26887c6-c47b-4654-abb5-dfadf7d3f804",
+		Tag:      iTag,
+	}
+	err := volumeattach.Create(context.TODO(), c, serverID, createOpts).Err
+	var unsupported gophercloud.ErrMicroversionUnsupported
+	th.AssertEquals(t, true, errors.As(err, &unsupported))
+	th.AssertEquals(t, "2.49", unsupported.Required)
+
+	c.Microversion = "2.78"
+	createOpts.DeleteOnTermination = iTrue
+	err = volumeattach.Create(context.TODO(), c, serverID, createOpts).Err
+	th.AssertEquals(t, true, errors.As(err, &unsupported))
+	th.AssertEquals(t, "2.79", unsupported.Required)
+}
+
 func TestGet(t *testing.T) {
 	fakeServer := th.SetupHTTP()
 	defer fakeServer.Teardown()
@@ -28,6 +28,8 @@ import (
 	"reflect"
 
 	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/oidc"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/saml2"
 	"go.yaml.in/yaml/v3"
 )
 
@@ -161,21 +163,30 @@ func Parse(opts ...ParseOption) (gophercloud.AuthOptions, gophercloud.EndpointOp
 		}
 	}
 
-	return gophercloud.AuthOptions{
-			IdentityEndpoint:            coalesce(options.authURL, cloud.AuthInfo.AuthURL),
-			Username:                    coalesce(options.username, cloud.AuthInfo.Username),
-			UserID:                      coalesce(options.userID, cloud.AuthInfo.UserID),
-			Password:                    coalesce(options.password, cloud.AuthInfo.Password),
-			DomainID:                    coalesce(options.domainID, cloud.AuthInfo.UserDomainID, cloud.AuthInfo.ProjectDomainID, cloud.AuthInfo.DomainID),
-			DomainName:                  coalesce(options.domainName, cloud.AuthInfo.UserDomainName, cloud.AuthInfo.ProjectDomainName, cloud.AuthInfo.DomainName),
-			TenantID:                    coalesce(options.projectID, cloud.AuthInfo.ProjectID),
-			TenantName:                  coalesce(options.projectName, cloud.AuthInfo.ProjectName),
-			TokenID:                     coalesce(options.token, cloud.AuthInfo.Token),
-			Scope:                       coalesce(options.scope, scope),
-			ApplicationCredentialID:     coalesce(options.applicationCredentialID, cloud.AuthInfo.ApplicationCredentialID),
-			ApplicationCredentialName:   coalesce(options.applicationCredentialName, cloud.AuthInfo.ApplicationCredentialName),
-			ApplicationCredentialSecret: coalesce(options.applicationCredentialSecret, cloud.AuthInfo.ApplicationCredentialSecret),
-		}, gophercloud.EndpointOpts{
+	authOptions := gophercloud.AuthOptions{
+		IdentityEndpoint:            coalesce(options.authURL, cloud.AuthInfo.AuthURL),
+		Username:                    coalesce(options.username, cloud.AuthInfo.Username),
+		UserID:                      coalesce(options.userID, cloud.AuthInfo.UserID),
+		Password:                    coalesce(options.password, cloud.AuthInfo.Password),
+		DomainID:                    coalesce(options.domainID, cloud.AuthInfo.UserDomainID, cloud.AuthInfo.ProjectDomainID, cloud.AuthInfo.DomainID),
+		DomainName:                  coalesce(options.domainName, cloud.AuthInfo.UserDomainName, cloud.AuthInfo.ProjectDomainName, cloud.AuthInfo.DomainName),
+		TenantID:                    coalesce(options.projectID, cloud.AuthInfo.ProjectID),
+		TenantName:                  coalesce(options.projectName, cloud.AuthInfo.ProjectName),
+		TokenID:                     coalesce(options.token, cloud.AuthInfo.Token),
+		Scope:                       coalesce(options.scope, scope),
+		ApplicationCredentialID:     coalesce(options.applicationCredentialID, cloud.AuthInfo.ApplicationCredentialID),
+		ApplicationCredentialName:   coalesce(options.applicationCredentialName, cloud.AuthInfo.ApplicationCredentialName),
+		ApplicationCredentialSecret: coalesce(options.applicationCredentialSecret, cloud.AuthInfo.ApplicationCredentialSecret),
+	}
+
+	// The user credentials of the federated authentication types are those
+	// of the identity provider, not of the Identity service.
+	if federated := computeFederatedAuth(cloud, authOptions); federated != nil {
+		authOptions.Federated = federated
+		authOptions.Username, authOptions.UserID, authOptions.Password = "", "", ""
+	}
+
+	return authOptions, gophercloud.EndpointOpts{
 			Region:       coalesce(options.region, cloud.RegionName),
 			Availability: computeAvailability(endpointType),
 		},
@@ -183,6 +194,53 @@ func Parse(opts ...ParseOption) (gophercloud.AuthOptions, gophercloud.EndpointOp
 		nil
 }
 
+// computeFederatedAuth returns the federated authentication of the auth
+// type of the cloud, or nil if it is not a federated one. The username and
+// password are taken from authOptions, which accounts for the parse options.
+func computeFederatedAuth(cloud Cloud, authOptions gophercloud.AuthOptions) gophercloud.FederatedAuth {
+	var grantType oidc.GrantType
+	switch cloud.AuthType {
+	case AuthV3OIDCPassword:
+		grantType = oidc.GrantPassword
+	case AuthV3OIDCClientCredentials:
+		grantType = oidc.GrantClientCredentials
+	case AuthV3OIDCAccessToken:
+	case AuthV3OIDCAuthCode:
+		grantType = oidc.GrantAuthorizationCode
+	case AuthV3OIDCDeviceAuthz:
+		grantType = oidc.GrantDeviceCode
+	case AuthV3SAMLPassword:
+		return &saml2.AuthOptions{
+			IdentityProvider:    cloud.AuthInfo.IdentityProvider,
+			Protocol:            cloud.AuthInfo.Protocol,
+			IdentityProviderURL: cloud.AuthInfo.IdentityProviderURL,
+			Username:            authOptions.Username,
+			Password:            authOptions.Password,
+		}
+	default:
+		return nil
+	}
+
+	return &oidc.AuthOptions{
+		IdentityProvider:            cloud.AuthInfo.IdentityProvider,
+		Protocol:                    cloud.AuthInfo.Protocol,
+		AccessToken:                 cloud.AuthInfo.AccessToken,
+		GrantType:                   grantType,
+		ClientID:                    cloud.AuthInfo.ClientID,
+		ClientSecret:                cloud.AuthInfo.ClientSecret,
+		DiscoveryEndpoint:           cloud.AuthInfo.DiscoveryEndpoint,
+		AccessTokenEndpoint:         cloud.AuthInfo.AccessTokenEndpoint,
+		DeviceAuthorizationEndpoint: cloud.AuthInfo.DeviceAuthorizationEndpoint,
+		OpenIDScope:                 cloud.AuthInfo.OpenIDScope,
+		AccessTokenType:             cloud.AuthInfo.AccessTokenType,
+		Username:                    authOptions.Username,
+		Password:                    authOptions.Password,
+		Code:                        cloud.AuthInfo.Code,
+		RedirectURI:                 cloud.AuthInfo.RedirectURI,
+		CodeChallengeMethod:         cloud.AuthInfo.CodeChallengeMethod,
+	}
+}
+
 // computeAvailability is a helper method to determine the endpoint type
 // requested by the user.
 func computeAvailability(endpointType string) gophercloud.Availability {
@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/gophercloud/gophercloud/v2/openstack/config/clouds"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/oidc"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/saml2"
 )
 
 func ExampleWithCloudName() {
@@ -336,3 +339,98 @@ func TestParse(t *testing.T) {
 		}
 	})
 }
+
+func TestParseOIDC(t *testing.T) {
+	const cloudsYAML = `clouds:
+  gophercloud-test:
+    auth_type: v3oidcpassword
+    auth:
+      auth_url: https://keystone.example.com:5000/v3
+      identity_provider: sso
+      protocol: openid
+      client_id: openstack
+      client_secret: client-secret
+      discovery_endpoint: https://sso.example.com/.well-known/openid-configuration
+      openid_scope: openid email
+      username: jdoe
+      password: secret
+      project_name: demo
+      project_domain_name: Default`
+
+	ao, _, _, err := clouds.Parse(
+		clouds.WithCloudsYAML(strings.NewReader(cloudsYAML)),
+		clouds.WithCloudName("gophercloud-test"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	federated, ok := ao.Federated.(*oidc.AuthOptions)
+	if !ok {
+		t.Fatalf("unexpected federated authentication: %#v", ao.Federated)
+	}
+	expected := oidc.AuthOptions{
+		IdentityProvider:  "sso",
+		Protocol:          "openid",
+		GrantType:         oidc.GrantPassword,
+		ClientID:          "openstack",
+		ClientSecret:      "client-secret",
+		DiscoveryEndpoint: "https://sso.example.com/.well-known/openid-configuration",
+		OpenIDScope:       "openid email",
+		Username:          "jdoe",
+		Password:          "secret",
+	}
+	if !reflect.DeepEqual(expected, *federated) {
+		t.Errorf("unexpected OIDC options: %#v", *federated)
+	}
+
+	// the user credentials are those of the identity provider
+	if ao.Username != "" || ao.Password != "" {
+		t.Errorf("unexpected credentials: %q, %q", ao.Username, ao.Password)
+	}
+	if ao.TenantName != "demo" || ao.DomainName != "Default" {
+		t.Errorf("unexpected scope: %q, %q", ao.TenantName, ao.DomainName)
+	}
+}
+
+func TestParseSAMLPassword(t *testing.T) {
+	const cloudsYAML = `clouds:
+  gophercloud-test:
+    auth_type: v3samlpassword
+    auth:
+      auth_url: https://keystone.example.com:5000/v3
+      identity_provider: acme
+      protocol: saml2
+      identity_provider_url: https://idp.acme.example.com/idp/profile/SAML2/SOAP/ECP
+      username: jdoe
+      password: secret
+      project_id: 3c44b3d1bdb24e1cbe9d6d77ba8d6cbc`
+
+	ao, _, _, err := clouds.Parse(
+		clouds.WithCloudsYAML(strings.NewReader(cloudsYAML)),
+		clouds.WithCloudName("gophercloud-test"),
+		clouds.WithPassword("override"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	federated, ok := ao.Federated.(*saml2.AuthOptions)
+	if !ok {
+		t.Fatalf("unexpected federated authentication: %#v", ao.Federated)
+	}
+	expected := saml2.AuthOptions{
+		IdentityProvider:    "acme",
+		Protocol:            "saml2",
+		IdentityProviderURL: "https://idp.acme.example.com/idp/profile/SAML2/SOAP/ECP",
+		Username:            "jdoe",
+		Password:            "override",
+	}
+	if !reflect.DeepEqual(expected, *federated) {
+		t.Errorf("unexpected SAML2 options: %#v", *federated)
+	}
+
+	if ao.Username != "" || ao.Password != "" {
+		t.Errorf("unexpected credentials: %q, %q", ao.Username, ao.Password)
+	}
+}
@@ -132,6 +132,60 @@ type AuthInfo struct {
 	// false, it will not cache these settings, but re-authentication will not be
 	// possible.  This setting defaults to false.
 	AllowReauth bool `yaml:"allow_reauth,omitempty" json:"allow_reauth,omitempty"`
+
+	// IdentityProvider is the ID of the identity provider to authenticate
+	// with, for the federated authentication types.
+	IdentityProvider string `yaml:"identity_provider,omitempty" json:"identity_provider,omitempty"`
+
+	// Protocol is the ID of the federation protocol of the identity
+	// provider, for the federated authentication types.
+	Protocol string `yaml:"protocol,omitempty" json:"protocol,omitempty"`
+
+	// ClientID is the ID of the OpenID Connect client.
+	ClientID string `yaml:"client_id,omitempty" json:"client_id,omitempty"`
+
+	// ClientSecret is the secret of the OpenID Connect client.
+	ClientSecret string `yaml:"client_secret,omitempty" json:"client_secret,omitempty"`
+
+	// DiscoveryEndpoint is the URL of the OpenID Connect discovery document
+	// of the identity provider.
+	DiscoveryEndpoint string `yaml:"discovery_endpoint,omitempty" json:"discovery_endpoint,omitempty"`
+
+	// AccessTokenEndpoint is the URL of the token endpoint of the OpenID
+	// Connect identity provider.
+	AccessTokenEndpoint string `yaml:"access_token_endpoint,omitempty" json:"access_token_endpoint,omitempty"`
+
+	// DeviceAuthorizationEndpoint is the URL of the device authorization
+	// endpoint of the OpenID Connect identity provider.
+	DeviceAuthorizationEndpoint string `yaml:"device_authorization_endpoint,omitempty" json:"device_authorization_endpoint,omitempty"`
+
+	// AccessTokenType is the token of the OpenID Connect identity provider
+	// sent to the Identity service: access_token or id_token.
+	AccessTokenType string `yaml:"access_token_type,omitempty" json:"access_token_type,omitempty"`
+
+	// OpenIDScope is the space-separated list of OpenID Connect scopes to
+	// request.
+	OpenIDScope string `yaml:"openid_scope,omitempty" json:"openid_scope,omitempty"`
+
+	// AccessToken is an access token of the OpenID Connect identity
+	// provider, for the v3oidcaccesstoken authentication type.
+	AccessToken string `yaml:"access_token,omitempty" json:"access_token,omitempty"`
+
+	// Code is an authorization code of the OpenID Connect identity provider,
+	// for the v3oidcauthcode authentication type.
+	Code string `yaml:"code,omitempty" json:"code,omitempty"`
+
+	// RedirectURI is the redirection URI the authorization code was issued
+	// for.
+	RedirectURI string `yaml:"redirect_uri,omitempty" json:"redirect_uri,omitempty"`
+
+	// CodeChallengeMethod enables PKCE for the v3oidcdeviceauthz
+	// authentication type: S256 or plain.
+	CodeChallengeMethod string `yaml:"code_challenge_method,omitempty" json:"code_challenge_method,omitempty"`
+
+	// IdentityProviderURL is the URL of the SAML2 ECP endpoint of the
+	// identity provider, for the v3samlpassword authentication type.
+	IdentityProviderURL string `yaml:"identity_provider_url,omitempty" json:"identity_provider_url,omitempty"`
 }
 
 // Region represents a region included as part of cloud in clouds.yaml
@@ -203,4 +257,18 @@ const (
 
 	// AuthV3ApplicationCredential defines version 3 of the application credential
 	AuthV3ApplicationCredential AuthType = "v3applicationcredential"
+
+	// AuthV3OIDCPassword defines the OpenID Connect password grant
+	AuthV3OIDCPassword AuthType = "v3oidcpassword"
+	// AuthV3OIDCClientCredentials defines the OpenID Connect client credentials grant
+	AuthV3OIDCClientCredentials AuthType = "v3oidcclientcredentials"
+	// AuthV3OIDCAccessToken defines an OpenID Connect access token obtained beforehand
+	AuthV3OIDCAccessToken AuthType = "v3oidcaccesstoken"
+	// AuthV3OIDCAuthCode defines the OpenID Connect authorization code grant
+	AuthV3OIDCAuthCode AuthType = "v3oidcauthcode"
+	// AuthV3OIDCDeviceAuthz defines the OpenID Connect device authorization grant
+	AuthV3OIDCDeviceAuthz AuthType = "v3oidcdeviceauthz"
+
+	// AuthV3SAMLPassword defines the SAML2 ECP profile with a username and a password
+	AuthV3SAMLPassword AuthType = "v3samlpassword"
 )
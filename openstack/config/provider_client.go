@@ -12,6 +12,7 @@ import (
 type options struct {
 	httpClient http.Client
 	tlsConfig  *tls.Config
+	tokenCache gophercloud.TokenCache
 }
 
 // WithHTTPClient enables passing a custom http.Client to be used in the
@@ -32,6 +33,15 @@ func WithTLSConfig(tlsConfig *tls.Config) func(*options) {
 	}
 }
 
+// WithTokenCache enables reusing a valid token stored in the given cache
+// instead of authenticating again, and storing newly issued tokens in it. See
+// gophercloud.AuthOptions.TokenCache.
+func WithTokenCache(cache gophercloud.TokenCache) func(*options) {
+	return func(o *options) {
+		o.tokenCache = cache
+	}
+}
+
 // NewProviderClient logs in to an OpenStack cloud found at the identity
 // endpoint specified by the options, acquires a token, and returns a Provider
 // Client instance that's ready to operate.
@@ -62,6 +72,10 @@ func NewProviderClient(ctx context.Context, authOptions gophercloud.AuthOptions,
 	}
 	client.HTTPClient = options.httpClient
 
+	if options.tokenCache != nil {
+		authOptions.TokenCache = options.tokenCache
+	}
+
 	err = openstack.Authenticate(ctx, client, authOptions)
 	if err != nil {
 		return nil, err
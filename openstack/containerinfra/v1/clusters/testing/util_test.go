@@ -0,0 +1,62 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/containerinfra/v1/clusters"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/testhelper/client"
+)
+
+func TestWaitForStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		statuses []string
+		status   string
+		failed   string
+	}{
+		{"create complete", []string{"CREATE_IN_PROGRESS", "CREATE_COMPLETE"}, "CREATE_COMPLETE", ""},
+		{"create failed", []string{"CREATE_IN_PROGRESS", "CREATE_FAILED"}, "CREATE_COMPLETE", "CREATE_FAILED"},
+		{"rolled back", []string{"CREATE_IN_PROGRESS", "ROLLBACK_IN_PROGRESS", "ROLLBACK_COMPLETE"}, "CREATE_COMPLETE", "ROLLBACK_COMPLETE"},
+		{"rollback failed", []string{"ROLLBACK_IN_PROGRESS", "ROLLBACK_FAILED"}, "CREATE_COMPLETE", "ROLLBACK_FAILED"},
+		{"update failed", []string{"UPDATE_IN_PROGRESS", "UPDATE_FAILED"}, "UPDATE_COMPLETE", "UPDATE_FAILED"},
+		{"delete failed", []string{"DELETE_IN_PROGRESS", "DELETE_FAILED"}, "DELETE_COMPLETE", "DELETE_FAILED"},
+		{"resume failed", []string{"RESUME_IN_PROGRESS", "RESUME_FAILED"}, "RESUME_COMPLETE", "RESUME_FAILED"},
+		{"restore failed", []string{"RESTORE_IN_PROGRESS", "RESTORE_FAILED"}, "RESTORE_COMPLETE", "RESTORE_FAILED"},
+		{"snapshot failed", []string{"SNAPSHOT_IN_PROGRESS", "SNAPSHOT_FAILED"}, "SNAPSHOT_COMPLETE", "SNAPSHOT_FAILED"},
+		{"check failed", []string{"CHECK_IN_PROGRESS", "CHECK_FAILED"}, "CHECK_COMPLETE", "CHECK_FAILED"},
+		{"adopt failed", []string{"ADOPT_IN_PROGRESS", "ADOPT_FAILED"}, "ADOPT_COMPLETE", "ADOPT_FAILED"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeServer := th.SetupHTTP()
+			defer fakeServer.Teardown()
+
+			var polls int
+			fakeServer.Mux.HandleFunc("/clusters/746e779a-751a-456b-a3e9-c883d734946f", func(w http.ResponseWriter, r *http.Request) {
+				th.TestMethod(t, r, "GET")
+				th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+				w.Header().Add("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"uuid": "746e779a-751a-456b-a3e9-c883d734946f", "status": "%s", "status_reason": "Stack CREATE FAILED"}`, tt.statuses[polls])
+				polls++
+			})
+
+			ctx := gophercloud.WithPoller(context.TODO(), &gophercloud.Poller{InitialInterval: time.Millisecond})
+			err := clusters.WaitForStatus(ctx, client.ServiceClient(fakeServer), "746e779a-751a-456b-a3e9-c883d734946f", tt.status)
+			th.AssertEquals(t, len(tt.statuses), polls)
+			if tt.failed == "" {
+				th.AssertNoErr(t, err)
+				return
+			}
+			var failed gophercloud.ErrResourceFailed
+			th.AssertEquals(t, true, errors.As(err, &failed))
+			th.AssertEquals(t, tt.failed, failed.Status)
+			th.AssertEquals(t, "Stack CREATE FAILED", failed.Fault)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package clusters
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// FailureStatuses are the statuses in which Magnum leaves a cluster when an
+// operation on it or on its Heat stack failed. They include ROLLBACK_COMPLETE,
+// the status of a cluster whose failed create was rolled back.
+var FailureStatuses = []string{
+	"CREATE_FAILED",
+	"UPDATE_FAILED",
+	"DELETE_FAILED",
+	"ROLLBACK_FAILED",
+	"ROLLBACK_COMPLETE",
+	"RESUME_FAILED",
+	"RESTORE_FAILED",
+	"SNAPSHOT_FAILED",
+	"CHECK_FAILED",
+	"ADOPT_FAILED",
+}
+
+// WaitForStatus polls a cluster until it reaches the given status, such as
+// CREATE_COMPLETE. The status_reason of a failed cluster is the Fault of the
+// returned gophercloud.ErrResourceFailed error.
+func WaitForStatus(ctx context.Context, c *gophercloud.ServiceClient, id, status string) error {
+	return gophercloud.WaitForStatus(ctx, id, status, FailureStatuses, func(ctx context.Context) (gophercloud.ResourceStatus, error) {
+		current, err := Get(ctx, c, id).Extract()
+		if err != nil {
+			return gophercloud.ResourceStatus{}, err
+		}
+		return gophercloud.ResourceStatus{
+			Status: current.Status,
+			Fault:  current.StatusReason,
+		}, nil
+	})
+}
@@ -0,0 +1,54 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/db/v1/instances"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/testhelper/client"
+)
+
+func TestWaitForStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		statuses []string
+		status   string
+		failed   string
+	}{
+		{"active", []string{"BUILD", "ACTIVE"}, "ACTIVE", ""},
+		{"error", []string{"BUILD", "ERROR"}, "ACTIVE", "ERROR"},
+		{"failed", []string{"BUILD", "FAILED"}, "ACTIVE", "FAILED"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeServer := th.SetupHTTP()
+			defer fakeServer.Teardown()
+
+			var polls int
+			fakeServer.Mux.HandleFunc("/instances/d4603f69-ec7e-4e9b-803f-600b9205576f", func(w http.ResponseWriter, r *http.Request) {
+				th.TestMethod(t, r, "GET")
+				th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+				w.Header().Add("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"instance": {"id": "d4603f69-ec7e-4e9b-803f-600b9205576f", "status": "%s", "fault": {"message": "Volume quota exceeded", "created": "2015-01-01T00:00:00"}}}`, tt.statuses[polls])
+				polls++
+			})
+
+			ctx := gophercloud.WithPoller(context.TODO(), &gophercloud.Poller{InitialInterval: time.Millisecond})
+			err := instances.WaitForStatus(ctx, client.ServiceClient(fakeServer), "d4603f69-ec7e-4e9b-803f-600b9205576f", tt.status)
+			th.AssertEquals(t, len(tt.statuses), polls)
+			if tt.failed == "" {
+				th.AssertNoErr(t, err)
+				return
+			}
+			var failed gophercloud.ErrResourceFailed
+			th.AssertEquals(t, true, errors.As(err, &failed))
+			th.AssertEquals(t, tt.failed, failed.Status)
+			th.AssertEquals(t, "Volume quota exceeded", failed.Fault)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package instances
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// FailureStatuses are the statuses of a database instance which Trove failed
+// to build or to operate.
+var FailureStatuses = []string{"ERROR", "FAILED"}
+
+// WaitForStatus polls a database instance until it reaches the given status,
+// usually ACTIVE once it is built. The message of the fault of a failed
+// instance, if any, is the Fault of the returned gophercloud.ErrResourceFailed
+// error.
+func WaitForStatus(ctx context.Context, c *gophercloud.ServiceClient, id, status string) error {
+	return gophercloud.WaitForStatus(ctx, id, status, FailureStatuses, func(ctx context.Context) (gophercloud.ResourceStatus, error) {
+		current, err := Get(ctx, c, id).Extract()
+		if err != nil {
+			return gophercloud.ResourceStatus{}, err
+		}
+		state := gophercloud.ResourceStatus{Status: current.Status}
+		if current.Fault != nil {
+			state.Fault = current.Fault.Message
+		}
+		return state, nil
+	})
+}
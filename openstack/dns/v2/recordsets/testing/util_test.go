@@ -0,0 +1,52 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/dns/v2/recordsets"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/testhelper/client"
+)
+
+func TestWaitForStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		statuses []string
+		status   string
+		failed   string
+	}{
+		{"active", []string{"PENDING", "ACTIVE"}, "ACTIVE", ""},
+		{"error", []string{"PENDING", "ERROR"}, "ACTIVE", "ERROR"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeServer := th.SetupHTTP()
+			defer fakeServer.Teardown()
+
+			var polls int
+			fakeServer.Mux.HandleFunc("/zones/2150b1bf-dee2-4221-9d85-11f7886fb15f/recordsets/f7b10e9b-0cae-4a91-b162-562bc6096648", func(w http.ResponseWriter, r *http.Request) {
+				th.TestMethod(t, r, "GET")
+				th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+				w.Header().Add("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"id": "f7b10e9b-0cae-4a91-b162-562bc6096648", "zone_id": "2150b1bf-dee2-4221-9d85-11f7886fb15f", "status": "%s"}`, tt.statuses[polls])
+				polls++
+			})
+
+			ctx := gophercloud.WithPoller(context.TODO(), &gophercloud.Poller{InitialInterval: time.Millisecond})
+			err := recordsets.WaitForStatus(ctx, client.ServiceClient(fakeServer), "2150b1bf-dee2-4221-9d85-11f7886fb15f", "f7b10e9b-0cae-4a91-b162-562bc6096648", tt.status)
+			th.AssertEquals(t, len(tt.statuses), polls)
+			if tt.failed == "" {
+				th.AssertNoErr(t, err)
+				return
+			}
+			var failed gophercloud.ErrResourceFailed
+			th.AssertEquals(t, true, errors.As(err, &failed))
+			th.AssertEquals(t, tt.failed, failed.Status)
+		})
+	}
+}
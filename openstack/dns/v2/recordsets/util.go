@@ -0,0 +1,23 @@
+package recordsets
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// FailureStatuses are the statuses of a record set whose last change Designate
+// could not apply to the DNS backends.
+var FailureStatuses = []string{"ERROR"}
+
+// WaitForStatus polls a record set until it reaches the given status, usually
+// ACTIVE once its changes have been applied to the DNS backends.
+func WaitForStatus(ctx context.Context, c *gophercloud.ServiceClient, zoneID, id, status string) error {
+	return gophercloud.WaitForStatus(ctx, id, status, FailureStatuses, func(ctx context.Context) (gophercloud.ResourceStatus, error) {
+		current, err := Get(ctx, c, zoneID, id).Extract()
+		if err != nil {
+			return gophercloud.ResourceStatus{}, err
+		}
+		return gophercloud.ResourceStatus{Status: current.Status}, nil
+	})
+}
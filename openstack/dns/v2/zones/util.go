@@ -0,0 +1,54 @@
+package zones
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/internal/find"
+)
+
+// FailureStatuses are the statuses of a zone whose last change Designate could
+// not apply to the DNS backends.
+var FailureStatuses = []string{"ERROR"}
+
+// WaitForStatus polls a zone until it reaches the given status, usually ACTIVE
+// once its changes have been applied to the DNS backends.
+func WaitForStatus(ctx context.Context, c *gophercloud.ServiceClient, id, status string) error {
+	return gophercloud.WaitForStatus(ctx, id, status, FailureStatuses, func(ctx context.Context) (gophercloud.ResourceStatus, error) {
+		current, err := Get(ctx, c, id).Extract()
+		if err != nil {
+			return gophercloud.ResourceStatus{}, err
+		}
+		return gophercloud.ResourceStatus{Status: current.Status}, nil
+	})
+}
+
+// IDFromName returns the ID of the zone with the given name. It returns a
+// gophercloud.ErrResourceNotFound error if there is none, and a
+// gophercloud.ErrMultipleResourcesFound error if there are several.
+// Zone names are fully qualified, e.g. "example.com.".
+func IDFromName(ctx context.Context, client *gophercloud.ServiceClient, name string) (string, error) {
+	found, err := findByName(ctx, client, name)
+	if err != nil {
+		return "", err
+	}
+	return found.ID, nil
+}
+
+// Find returns the zone with the given ID or, if there is none, the zone
+// with the given name. It returns a gophercloud.ErrResourceNotFound error if
+// there is none, and a gophercloud.ErrMultipleResourcesFound error if several
+// zones have the given name.
+func Find(ctx context.Context, client *gophercloud.ServiceClient, nameOrID string) (*Zone, error) {
+	return find.ByNameOrID(ctx, nameOrID, func(ctx context.Context, id string) (*Zone, error) {
+		return Get(ctx, client, id).Extract()
+	}, func(ctx context.Context, name string) (*Zone, error) {
+		return findByName(ctx, client, name)
+	})
+}
+
+// findByName returns the zone with the given name.
+func findByName(ctx context.Context, client *gophercloud.ServiceClient, name string) (*Zone, error) {
+	pager := List(client, ListOpts{Name: name})
+	return find.ByName(ctx, pager, ExtractZones, func(v Zone) string { return v.Name }, "zone", name)
+}
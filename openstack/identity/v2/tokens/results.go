@@ -150,6 +150,17 @@ func (r CreateResult) ExtractTokenID() (string, error) {
 	return s.Access.Token.ID, err
 }
 
+// ExtractExpiresAt implements the gophercloud.ExpiringAuthResult interface.
+// The returned time is the same as the ExpiresAt field of the Token struct
+// returned from ExtractToken().
+func (r CreateResult) ExtractExpiresAt() (time.Time, error) {
+	t, err := r.ExtractToken()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.ExpiresAt, nil
+}
+
 // ExtractServiceCatalog returns the ServiceCatalog that was generated along
 // with the user's Token.
 func (r CreateResult) ExtractServiceCatalog() (*ServiceCatalog, error) {
@@ -101,5 +101,122 @@ Example to Delete a Mapping
 	if err != nil {
 		panic(err)
 	}
+
+Example to List Identity Providers
+
+	listOpts := federation.ListIdentityProvidersOpts{
+		Enabled: gophercloud.Enabled,
+	}
+	allPages, err := federation.ListIdentityProviders(identityClient, listOpts).AllPages(context.TODO())
+	if err != nil {
+		panic(err)
+	}
+	allIdentityProviders, err := federation.ExtractIdentityProviders(allPages)
+	if err != nil {
+		panic(err)
+	}
+
+Example to Create an Identity Provider
+
+	createOpts := federation.CreateIdentityProviderOpts{
+		DomainID:    "1789d1",
+		Description: "Stores ACME identities",
+		Enabled:     gophercloud.Enabled,
+		RemoteIDs:   []string{"https://acme.example.com/idp"},
+	}
+	idp, err := federation.CreateIdentityProvider(context.TODO(), identityClient, "ACME", createOpts).Extract()
+	if err != nil {
+		panic(err)
+	}
+
+Example to Update an Identity Provider
+
+	remoteIDs := []string{"https://acme.example.com/idp", "https://sso.acme.example.com"}
+	updateOpts := federation.UpdateIdentityProviderOpts{
+		RemoteIDs: &remoteIDs,
+	}
+	idp, err := federation.UpdateIdentityProvider(context.TODO(), identityClient, "ACME", updateOpts).Extract()
+	if err != nil {
+		panic(err)
+	}
+
+Example to Delete an Identity Provider
+
+	err := federation.DeleteIdentityProvider(context.TODO(), identityClient, "ACME").ExtractErr()
+	if err != nil {
+		panic(err)
+	}
+
+Example to Add a Protocol to an Identity Provider
+
+	createOpts := federation.CreateProtocolOpts{
+		MappingID: "ACME",
+	}
+	protocol, err := federation.CreateProtocol(context.TODO(), identityClient, "ACME", "saml2", createOpts).Extract()
+	if err != nil {
+		panic(err)
+	}
+
+Example to List the Protocols of an Identity Provider
+
+	allPages, err := federation.ListProtocols(identityClient, "ACME").AllPages(context.TODO())
+	if err != nil {
+		panic(err)
+	}
+	allProtocols, err := federation.ExtractProtocols(allPages)
+	if err != nil {
+		panic(err)
+	}
+
+Example to Create a Service Provider
+
+	createOpts := federation.CreateServiceProviderOpts{
+		AuthURL: "https://beta.example.com/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2/auth",
+		SPURL:   "https://beta.example.com/Shibboleth.sso/SAML2/ECP",
+		Enabled: gophercloud.Enabled,
+	}
+	sp, err := federation.CreateServiceProvider(context.TODO(), identityClient, "BETA", createOpts).Extract()
+	if err != nil {
+		panic(err)
+	}
+
+Example to Get the SAML2 Metadata
+
+	metadata, err := federation.GetSAML2Metadata(context.TODO(), identityClient).Extract()
+	if err != nil {
+		panic(err)
+	}
+
+Example to Create an ECP Wrapped SAML2 Assertion for a Service Provider
+
+	createOpts := federation.CreateAssertionOpts{
+		TokenID:           identityClient.Token(),
+		ServiceProviderID: "BETA",
+	}
+	assertion, err := federation.CreateECPAssertion(context.TODO(), identityClient, createOpts).Extract()
+	if err != nil {
+		panic(err)
+	}
+
+Example to List the Projects Available to a Federated Token
+
+	allPages, err := federation.ListProjects(identityClient).AllPages(context.TODO())
+	if err != nil {
+		panic(err)
+	}
+	allProjects, err := projects.ExtractProjects(allPages)
+	if err != nil {
+		panic(err)
+	}
+
+Example to Exchange an OpenID Connect Access Token for an Unscoped Token
+
+	authOpts := federation.AuthenticateOpts{
+		AccessToken: accessToken,
+	}
+	tokenID, err := federation.Authenticate(context.TODO(), identityClient, "ACME", "openid", authOpts).ExtractTokenID()
+	if err != nil {
+		panic(err)
+	}
 */
 package federation
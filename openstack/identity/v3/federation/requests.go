@@ -4,6 +4,9 @@ import (
 	"context"
 
 	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/domains"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/projects"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/tokens"
 	"github.com/gophercloud/gophercloud/v2/pagination"
 )
 
@@ -89,3 +92,534 @@ func DeleteMapping(ctx context.Context, client *gophercloud.ServiceClient, mappi
 	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
 	return
 }
+
+// ListIdentityProvidersOptsBuilder allows extensions to add additional
+// parameters to the ListIdentityProviders request.
+type ListIdentityProvidersOptsBuilder interface {
+	ToIdentityProviderListQuery() (string, error)
+}
+
+// ListIdentityProvidersOpts enables filtering of a ListIdentityProviders
+// request.
+type ListIdentityProvidersOpts struct {
+	// ID filters the response by identity provider ID.
+	ID string `q:"id"`
+
+	// Enabled filters the response by enabled identity providers.
+	Enabled *bool `q:"enabled"`
+}
+
+// ToIdentityProviderListQuery formats a ListIdentityProvidersOpts into a
+// query string.
+func (opts ListIdentityProvidersOpts) ToIdentityProviderListQuery() (string, error) {
+	q, err := gophercloud.BuildQueryString(opts)
+	if err != nil {
+		return "", err
+	}
+	return q.String(), nil
+}
+
+// ListIdentityProviders enumerates the identity providers.
+func ListIdentityProviders(client *gophercloud.ServiceClient, opts ListIdentityProvidersOptsBuilder) pagination.Pager {
+	url := identityProvidersRootURL(client)
+	if opts != nil {
+		query, err := opts.ToIdentityProviderListQuery()
+		if err != nil {
+			return pagination.Pager{Err: err}
+		}
+		url += query
+	}
+	return pagination.NewPager(client, url, func(r pagination.PageResult) pagination.Page {
+		return IdentityProvidersPage{pagination.LinkedPageBase{PageResult: r}}
+	})
+}
+
+// CreateIdentityProviderOptsBuilder allows extensions to add additional
+// parameters to the CreateIdentityProvider request.
+type CreateIdentityProviderOptsBuilder interface {
+	ToIdentityProviderCreateMap() (map[string]any, error)
+}
+
+// CreateIdentityProviderOpts provides options for creating an identity
+// provider.
+type CreateIdentityProviderOpts struct {
+	// DomainID is the ID of the domain the federated users are created in.
+	// If not set, a domain is created for the identity provider.
+	DomainID string `json:"domain_id,omitempty"`
+
+	// Description is the description of the identity provider.
+	Description string `json:"description,omitempty"`
+
+	// Enabled sets the identity provider status to enabled or disabled.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// RemoteIDs are the unique identifiers of the identity provider, such as
+	// the entity ID of a SAML2 identity provider or the issuer of an OpenID
+	// Connect provider.
+	RemoteIDs []string `json:"remote_ids,omitempty"`
+
+	// AuthorizationTTL is the number of minutes the group memberships of a
+	// federated user are valid, once the user authenticated.
+	AuthorizationTTL *int `json:"authorization_ttl,omitempty"`
+}
+
+// ToIdentityProviderCreateMap formats a CreateIdentityProviderOpts into a
+// create request.
+func (opts CreateIdentityProviderOpts) ToIdentityProviderCreateMap() (map[string]any, error) {
+	return gophercloud.BuildRequestBody(opts, "identity_provider")
+}
+
+// CreateIdentityProvider creates a new identity provider.
+func CreateIdentityProvider(ctx context.Context, client *gophercloud.ServiceClient, idpID string, opts CreateIdentityProviderOptsBuilder) (r CreateIdentityProviderResult) {
+	b, err := opts.ToIdentityProviderCreateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Put(ctx, identityProvidersResourceURL(client, idpID), &b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{201},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// GetIdentityProvider retrieves details on a single identity provider, by ID.
+func GetIdentityProvider(ctx context.Context, client *gophercloud.ServiceClient, idpID string) (r GetIdentityProviderResult) {
+	resp, err := client.Get(ctx, identityProvidersResourceURL(client, idpID), &r.Body, nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// UpdateIdentityProviderOptsBuilder allows extensions to add additional
+// parameters to the UpdateIdentityProvider request.
+type UpdateIdentityProviderOptsBuilder interface {
+	ToIdentityProviderUpdateMap() (map[string]any, error)
+}
+
+// UpdateIdentityProviderOpts provides options for updating an identity
+// provider. The domain of an identity provider cannot be changed.
+type UpdateIdentityProviderOpts struct {
+	// Description is the description of the identity provider.
+	Description *string `json:"description,omitempty"`
+
+	// Enabled sets the identity provider status to enabled or disabled.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// RemoteIDs are the unique identifiers of the identity provider.
+	RemoteIDs *[]string `json:"remote_ids,omitempty"`
+
+	// AuthorizationTTL is the number of minutes the group memberships of a
+	// federated user are valid, once the user authenticated.
+	AuthorizationTTL *int `json:"authorization_ttl,omitempty"`
+}
+
+// ToIdentityProviderUpdateMap formats an UpdateIdentityProviderOpts into an
+// update request.
+func (opts UpdateIdentityProviderOpts) ToIdentityProviderUpdateMap() (map[string]any, error) {
+	return gophercloud.BuildRequestBody(opts, "identity_provider")
+}
+
+// UpdateIdentityProvider updates an existing identity provider.
+func UpdateIdentityProvider(ctx context.Context, client *gophercloud.ServiceClient, idpID string, opts UpdateIdentityProviderOptsBuilder) (r UpdateIdentityProviderResult) {
+	b, err := opts.ToIdentityProviderUpdateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Patch(ctx, identityProvidersResourceURL(client, idpID), &b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{200},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// DeleteIdentityProvider deletes an identity provider, along with its
+// protocols.
+func DeleteIdentityProvider(ctx context.Context, client *gophercloud.ServiceClient, idpID string) (r DeleteIdentityProviderResult) {
+	resp, err := client.Delete(ctx, identityProvidersResourceURL(client, idpID), nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// ListProtocols enumerates the protocols of an identity provider.
+func ListProtocols(client *gophercloud.ServiceClient, idpID string) pagination.Pager {
+	return pagination.NewPager(client, protocolsRootURL(client, idpID), func(r pagination.PageResult) pagination.Page {
+		return ProtocolsPage{pagination.LinkedPageBase{PageResult: r}}
+	})
+}
+
+// CreateProtocolOptsBuilder allows extensions to add additional parameters to
+// the CreateProtocol request.
+type CreateProtocolOptsBuilder interface {
+	ToProtocolCreateMap() (map[string]any, error)
+}
+
+// CreateProtocolOpts provides options for creating a protocol.
+type CreateProtocolOpts struct {
+	// MappingID is the ID of the mapping applied to the users authenticating
+	// with the protocol.
+	MappingID string `json:"mapping_id" required:"true"`
+
+	// RemoteIDAttribute is the attribute of the assertion which holds the
+	// remote ID of the identity provider.
+	RemoteIDAttribute string `json:"remote_id_attribute,omitempty"`
+}
+
+// ToProtocolCreateMap formats a CreateProtocolOpts into a create request.
+func (opts CreateProtocolOpts) ToProtocolCreateMap() (map[string]any, error) {
+	return gophercloud.BuildRequestBody(opts, "protocol")
+}
+
+// CreateProtocol adds a protocol to an identity provider.
+func CreateProtocol(ctx context.Context, client *gophercloud.ServiceClient, idpID, protocolID string, opts CreateProtocolOptsBuilder) (r CreateProtocolResult) {
+	b, err := opts.ToProtocolCreateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Put(ctx, protocolsResourceURL(client, idpID, protocolID), &b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{201},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// GetProtocol retrieves details on a single protocol of an identity
+// provider, by ID.
+func GetProtocol(ctx context.Context, client *gophercloud.ServiceClient, idpID, protocolID string) (r GetProtocolResult) {
+	resp, err := client.Get(ctx, protocolsResourceURL(client, idpID, protocolID), &r.Body, nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// UpdateProtocolOptsBuilder allows extensions to add additional parameters to
+// the UpdateProtocol request.
+type UpdateProtocolOptsBuilder interface {
+	ToProtocolUpdateMap() (map[string]any, error)
+}
+
+// UpdateProtocolOpts provides options for updating a protocol.
+type UpdateProtocolOpts struct {
+	// MappingID is the ID of the mapping applied to the users authenticating
+	// with the protocol.
+	MappingID string `json:"mapping_id,omitempty"`
+
+	// RemoteIDAttribute is the attribute of the assertion which holds the
+	// remote ID of the identity provider.
+	RemoteIDAttribute *string `json:"remote_id_attribute,omitempty"`
+}
+
+// ToProtocolUpdateMap formats an UpdateProtocolOpts into an update request.
+func (opts UpdateProtocolOpts) ToProtocolUpdateMap() (map[string]any, error) {
+	return gophercloud.BuildRequestBody(opts, "protocol")
+}
+
+// UpdateProtocol updates an existing protocol of an identity provider.
+func UpdateProtocol(ctx context.Context, client *gophercloud.ServiceClient, idpID, protocolID string, opts UpdateProtocolOptsBuilder) (r UpdateProtocolResult) {
+	b, err := opts.ToProtocolUpdateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Patch(ctx, protocolsResourceURL(client, idpID, protocolID), &b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{200},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// DeleteProtocol deletes a protocol of an identity provider.
+func DeleteProtocol(ctx context.Context, client *gophercloud.ServiceClient, idpID, protocolID string) (r DeleteProtocolResult) {
+	resp, err := client.Delete(ctx, protocolsResourceURL(client, idpID, protocolID), nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// ListServiceProvidersOptsBuilder allows extensions to add additional
+// parameters to the ListServiceProviders request.
+type ListServiceProvidersOptsBuilder interface {
+	ToServiceProviderListQuery() (string, error)
+}
+
+// ListServiceProvidersOpts enables filtering of a ListServiceProviders
+// request.
+type ListServiceProvidersOpts struct {
+	// ID filters the response by service provider ID.
+	ID string `q:"id"`
+
+	// Enabled filters the response by enabled service providers.
+	Enabled *bool `q:"enabled"`
+}
+
+// ToServiceProviderListQuery formats a ListServiceProvidersOpts into a query
+// string.
+func (opts ListServiceProvidersOpts) ToServiceProviderListQuery() (string, error) {
+	q, err := gophercloud.BuildQueryString(opts)
+	if err != nil {
+		return "", err
+	}
+	return q.String(), nil
+}
+
+// ListServiceProviders enumerates the service providers.
+func ListServiceProviders(client *gophercloud.ServiceClient, opts ListServiceProvidersOptsBuilder) pagination.Pager {
+	url := serviceProvidersRootURL(client)
+	if opts != nil {
+		query, err := opts.ToServiceProviderListQuery()
+		if err != nil {
+			return pagination.Pager{Err: err}
+		}
+		url += query
+	}
+	return pagination.NewPager(client, url, func(r pagination.PageResult) pagination.Page {
+		return ServiceProvidersPage{pagination.LinkedPageBase{PageResult: r}}
+	})
+}
+
+// CreateServiceProviderOptsBuilder allows extensions to add additional
+// parameters to the CreateServiceProvider request.
+type CreateServiceProviderOptsBuilder interface {
+	ToServiceProviderCreateMap() (map[string]any, error)
+}
+
+// CreateServiceProviderOpts provides options for creating a service
+// provider.
+type CreateServiceProviderOpts struct {
+	// AuthURL is the URL of the federated auth endpoint of the service
+	// provider, at which the SAML2 assertions are exchanged for tokens.
+	AuthURL string `json:"auth_url" required:"true"`
+
+	// SPURL is the URL of the ECP endpoint of the service provider, to which
+	// the SAML2 assertions are posted.
+	SPURL string `json:"sp_url" required:"true"`
+
+	// Description is the description of the service provider.
+	Description string `json:"description,omitempty"`
+
+	// Enabled sets the service provider status to enabled or disabled.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// RelayStatePrefix is the prefix of the RelayState of the SAML2
+	// assertions sent to the service provider.
+	RelayStatePrefix string `json:"relay_state_prefix,omitempty"`
+}
+
+// ToServiceProviderCreateMap formats a CreateServiceProviderOpts into a
+// create request.
+func (opts CreateServiceProviderOpts) ToServiceProviderCreateMap() (map[string]any, error) {
+	return gophercloud.BuildRequestBody(opts, "service_provider")
+}
+
+// CreateServiceProvider creates a new service provider.
+func CreateServiceProvider(ctx context.Context, client *gophercloud.ServiceClient, spID string, opts CreateServiceProviderOptsBuilder) (r CreateServiceProviderResult) {
+	b, err := opts.ToServiceProviderCreateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Put(ctx, serviceProvidersResourceURL(client, spID), &b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{201},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// GetServiceProvider retrieves details on a single service provider, by ID.
+func GetServiceProvider(ctx context.Context, client *gophercloud.ServiceClient, spID string) (r GetServiceProviderResult) {
+	resp, err := client.Get(ctx, serviceProvidersResourceURL(client, spID), &r.Body, nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// UpdateServiceProviderOptsBuilder allows extensions to add additional
+// parameters to the UpdateServiceProvider request.
+type UpdateServiceProviderOptsBuilder interface {
+	ToServiceProviderUpdateMap() (map[string]any, error)
+}
+
+// UpdateServiceProviderOpts provides options for updating a service
+// provider.
+type UpdateServiceProviderOpts struct {
+	// AuthURL is the URL of the federated auth endpoint of the service
+	// provider.
+	AuthURL string `json:"auth_url,omitempty"`
+
+	// SPURL is the URL of the ECP endpoint of the service provider.
+	SPURL string `json:"sp_url,omitempty"`
+
+	// Description is the description of the service provider.
+	Description *string `json:"description,omitempty"`
+
+	// Enabled sets the service provider status to enabled or disabled.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// RelayStatePrefix is the prefix of the RelayState of the SAML2
+	// assertions sent to the service provider.
+	RelayStatePrefix *string `json:"relay_state_prefix,omitempty"`
+}
+
+// ToServiceProviderUpdateMap formats an UpdateServiceProviderOpts into an
+// update request.
+func (opts UpdateServiceProviderOpts) ToServiceProviderUpdateMap() (map[string]any, error) {
+	return gophercloud.BuildRequestBody(opts, "service_provider")
+}
+
+// UpdateServiceProvider updates an existing service provider.
+func UpdateServiceProvider(ctx context.Context, client *gophercloud.ServiceClient, spID string, opts UpdateServiceProviderOptsBuilder) (r UpdateServiceProviderResult) {
+	b, err := opts.ToServiceProviderUpdateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Patch(ctx, serviceProvidersResourceURL(client, spID), &b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{200},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// DeleteServiceProvider deletes a service provider.
+func DeleteServiceProvider(ctx context.Context, client *gophercloud.ServiceClient, spID string) (r DeleteServiceProviderResult) {
+	resp, err := client.Delete(ctx, serviceProvidersResourceURL(client, spID), nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// GetSAML2Metadata retrieves the SAML2 metadata of the Identity service as an
+// identity provider, which is registered in the service providers.
+func GetSAML2Metadata(ctx context.Context, client *gophercloud.ServiceClient) (r GetSAML2MetadataResult) {
+	resp, err := client.Get(ctx, saml2MetadataURL(client), nil, &gophercloud.RequestOpts{
+		MoreHeaders:      map[string]string{"Accept": "text/xml"},
+		OkCodes:          []int{200},
+		KeepResponseBody: true,
+	})
+	r.Body, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// ListProjects enumerates the projects a federated user can scope a token
+// to, according to the groups of the federated token of the client.
+func ListProjects(client *gophercloud.ServiceClient) pagination.Pager {
+	return pagination.NewPager(client, projectsURL(client), func(r pagination.PageResult) pagination.Page {
+		return projects.ProjectPage{LinkedPageBase: pagination.LinkedPageBase{PageResult: r}}
+	})
+}
+
+// ListDomains enumerates the domains a federated user can scope a token to,
+// according to the groups of the federated token of the client.
+func ListDomains(client *gophercloud.ServiceClient) pagination.Pager {
+	return pagination.NewPager(client, domainsURL(client), func(r pagination.PageResult) pagination.Page {
+		return domains.DomainPage{LinkedPageBase: pagination.LinkedPageBase{PageResult: r}}
+	})
+}
+
+// CreateAssertionOptsBuilder allows extensions to add additional parameters
+// to the CreateSAML2Assertion and CreateECPAssertion requests.
+type CreateAssertionOptsBuilder interface {
+	ToAssertionCreateMap() (map[string]any, error)
+}
+
+// CreateAssertionOpts provides options for generating a SAML2 assertion of a
+// user for a service provider.
+type CreateAssertionOpts struct {
+	// TokenID is the token of the user the assertion is about.
+	TokenID string
+
+	// ServiceProviderID is the ID of the service provider the assertion is
+	// for.
+	ServiceProviderID string
+}
+
+// ToAssertionCreateMap formats a CreateAssertionOpts into a create request.
+func (opts CreateAssertionOpts) ToAssertionCreateMap() (map[string]any, error) {
+	if opts.TokenID == "" {
+		return nil, gophercloud.ErrMissingInput{Argument: "TokenID"}
+	}
+	if opts.ServiceProviderID == "" {
+		return nil, gophercloud.ErrMissingInput{Argument: "ServiceProviderID"}
+	}
+	return map[string]any{
+		"auth": map[string]any{
+			"identity": map[string]any{
+				"methods": []string{"token"},
+				"token": map[string]any{
+					"id": opts.TokenID,
+				},
+			},
+			"scope": map[string]any{
+				"service_provider": map[string]any{
+					"id": opts.ServiceProviderID,
+				},
+			},
+		},
+	}, nil
+}
+
+// CreateSAML2Assertion generates a SAML2 assertion of a user for a service
+// provider.
+func CreateSAML2Assertion(ctx context.Context, client *gophercloud.ServiceClient, opts CreateAssertionOptsBuilder) (r CreateAssertionResult) {
+	return createAssertion(ctx, client, saml2AssertionURL(client), "text/xml", opts)
+}
+
+// CreateECPAssertion generates a SAML2 assertion of a user for a service
+// provider, wrapped in a SOAP envelope to be posted to the ECP endpoint of
+// the service provider.
+func CreateECPAssertion(ctx context.Context, client *gophercloud.ServiceClient, opts CreateAssertionOptsBuilder) (r CreateAssertionResult) {
+	return createAssertion(ctx, client, ecpAssertionURL(client), "application/vnd.paos+xml", opts)
+}
+
+func createAssertion(ctx context.Context, client *gophercloud.ServiceClient, url, accept string, opts CreateAssertionOptsBuilder) (r CreateAssertionResult) {
+	b, err := opts.ToAssertionCreateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Post(ctx, url, b, nil, &gophercloud.RequestOpts{
+		MoreHeaders:      map[string]string{"Accept": accept},
+		OkCodes:          []int{200},
+		KeepResponseBody: true,
+	})
+	r.Body, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
+// AuthenticateOptsBuilder allows the federated authentication methods to
+// provide the credentials of the Authenticate request.
+type AuthenticateOptsBuilder interface {
+	ToFederationAuthenticateHeaders() (map[string]string, error)
+}
+
+// AuthenticateOpts provides the credentials of an Authenticate request with
+// a protocol such as openid.
+type AuthenticateOpts struct {
+	// AccessToken is an OAuth 2.0 access token or an OpenID Connect ID token
+	// issued by the identity provider. It is sent as a bearer token.
+	AccessToken string
+}
+
+// ToFederationAuthenticateHeaders formats an AuthenticateOpts into the
+// headers of an Authenticate request.
+func (opts AuthenticateOpts) ToFederationAuthenticateHeaders() (map[string]string, error) {
+	if opts.AccessToken == "" {
+		return nil, gophercloud.ErrMissingInput{Argument: "AccessToken"}
+	}
+	return map[string]string{"Authorization": "Bearer " + opts.AccessToken}, nil
+}
+
+// Authenticate exchanges the credentials issued by an identity provider for
+// an unscoped token, through the given federation protocol of the identity
+// provider.
+func Authenticate(ctx context.Context, client *gophercloud.ServiceClient, idpID, protocolID string, opts AuthenticateOptsBuilder) (r tokens.CreateResult) {
+	h, err := opts.ToFederationAuthenticateHeaders()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	resp, err := client.Post(ctx, authURL(client, idpID, protocolID), nil, &r.Body, &gophercloud.RequestOpts{
+		MoreHeaders: h,
+		OmitHeaders: []string{"X-Auth-Token"},
+		OkCodes:     []int{201},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
@@ -343,3 +343,674 @@ func HandleDeleteMappingSuccessfully(t *testing.T, fakeServer th.FakeServer) {
 		w.WriteHeader(http.StatusNoContent)
 	})
 }
+
+// AuthenticateOutput is a sample response to an Authenticate request.
+const AuthenticateOutput = `
+{
+	"token": {
+		"methods": ["openid"],
+		"expires_at": "2030-01-01T00:00:00.000000Z",
+		"user": {
+			"id": "a6d4b6a9a1d84b9f8bc5e1c3a8ef3f1d",
+			"name": "jdoe",
+			"domain": {"id": "Federated", "name": "Federated"}
+		}
+	}
+}
+`
+
+// HandleAuthenticateSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/identity_providers/ACME/protocols/openid/auth` on the test
+// handler mux that responds with an unscoped token.
+func HandleAuthenticateSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/identity_providers/ACME/protocols/openid/auth", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "Authorization", "Bearer access-token")
+
+		w.Header().Set("X-Subject-Token", "unscoped-token")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, AuthenticateOutput)
+	})
+}
+
+const ListIdentityProvidersOutput = `
+{
+    "links": {
+        "next": null,
+        "previous": null,
+        "self": "http://example.com/identity/v3/OS-FEDERATION/identity_providers"
+    },
+    "identity_providers": [
+        {
+            "id": "ACME",
+            "domain_id": "1789d1",
+            "description": "Stores ACME identities",
+            "enabled": true,
+            "remote_ids": ["https://acme.example.com/idp"],
+            "authorization_ttl": null,
+            "links": {
+                "protocols": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols",
+                "self": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME"
+            }
+        }
+    ]
+}
+`
+
+const CreateIdentityProviderRequest = `
+{
+    "identity_provider": {
+        "domain_id": "1789d1",
+        "description": "Stores ACME identities",
+        "enabled": true,
+        "remote_ids": ["https://acme.example.com/idp"]
+    }
+}
+`
+
+const GetIdentityProviderOutput = `
+{
+    "identity_provider": {
+        "id": "ACME",
+        "domain_id": "1789d1",
+        "description": "Stores ACME identities",
+        "enabled": true,
+        "remote_ids": ["https://acme.example.com/idp"],
+        "authorization_ttl": null,
+        "links": {
+            "protocols": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols",
+            "self": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME"
+        }
+    }
+}
+`
+
+const UpdateIdentityProviderRequest = `
+{
+    "identity_provider": {
+        "enabled": false,
+        "remote_ids": ["https://acme.example.com/idp", "https://sso.acme.example.com"],
+        "authorization_ttl": 60
+    }
+}
+`
+
+const UpdateIdentityProviderOutput = `
+{
+    "identity_provider": {
+        "id": "ACME",
+        "domain_id": "1789d1",
+        "description": "Stores ACME identities",
+        "enabled": false,
+        "remote_ids": ["https://acme.example.com/idp", "https://sso.acme.example.com"],
+        "authorization_ttl": 60,
+        "links": {
+            "protocols": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols",
+            "self": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME"
+        }
+    }
+}
+`
+
+var authorizationTTL = 60
+
+var IdentityProviderACME = federation.IdentityProvider{
+	ID:          "ACME",
+	DomainID:    "1789d1",
+	Description: "Stores ACME identities",
+	Enabled:     true,
+	RemoteIDs:   []string{"https://acme.example.com/idp"},
+	Links: map[string]any{
+		"protocols": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols",
+		"self":      "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME",
+	},
+}
+
+var IdentityProviderUpdated = federation.IdentityProvider{
+	ID:               "ACME",
+	DomainID:         "1789d1",
+	Description:      "Stores ACME identities",
+	Enabled:          false,
+	RemoteIDs:        []string{"https://acme.example.com/idp", "https://sso.acme.example.com"},
+	AuthorizationTTL: &authorizationTTL,
+	Links: map[string]any{
+		"protocols": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols",
+		"self":      "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME",
+	},
+}
+
+// HandleListIdentityProvidersSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/identity_providers` on the test handler mux that responds
+// with a list of identity providers.
+func HandleListIdentityProvidersSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/identity_providers", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Accept", "application/json")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		th.TestFormValues(t, r, map[string]string{"enabled": "true"})
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListIdentityProvidersOutput)
+	})
+}
+
+// HandleCreateIdentityProviderSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/identity_providers/ACME` on the test handler mux that tests
+// identity provider creation.
+func HandleCreateIdentityProviderSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/identity_providers/ACME", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		th.TestJSONRequest(t, r, CreateIdentityProviderRequest)
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, GetIdentityProviderOutput)
+	})
+}
+
+// HandleGetIdentityProviderSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/identity_providers/ACME` on the test handler mux that
+// responds with a single identity provider.
+func HandleGetIdentityProviderSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/identity_providers/ACME", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Accept", "application/json")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, GetIdentityProviderOutput)
+	})
+}
+
+// HandleUpdateIdentityProviderSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/identity_providers/ACME` on the test handler mux that tests
+// identity provider update.
+func HandleUpdateIdentityProviderSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/identity_providers/ACME", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PATCH")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		th.TestJSONRequest(t, r, UpdateIdentityProviderRequest)
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, UpdateIdentityProviderOutput)
+	})
+}
+
+// HandleDeleteIdentityProviderSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/identity_providers/ACME` on the test handler mux that tests
+// identity provider deletion.
+func HandleDeleteIdentityProviderSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/identity_providers/ACME", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "DELETE")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+const ListProtocolsOutput = `
+{
+    "links": {
+        "next": null,
+        "previous": null,
+        "self": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols"
+    },
+    "protocols": [
+        {
+            "id": "saml2",
+            "mapping_id": "ACME",
+            "remote_id_attribute": "Shib-Identity-Provider",
+            "links": {
+                "identity_provider": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME",
+                "self": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2"
+            }
+        }
+    ]
+}
+`
+
+const CreateProtocolRequest = `
+{
+    "protocol": {
+        "mapping_id": "ACME",
+        "remote_id_attribute": "Shib-Identity-Provider"
+    }
+}
+`
+
+const GetProtocolOutput = `
+{
+    "protocol": {
+        "id": "saml2",
+        "mapping_id": "ACME",
+        "remote_id_attribute": "Shib-Identity-Provider",
+        "links": {
+            "identity_provider": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME",
+            "self": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2"
+        }
+    }
+}
+`
+
+const UpdateProtocolRequest = `
+{
+    "protocol": {
+        "mapping_id": "ACME-v2"
+    }
+}
+`
+
+const UpdateProtocolOutput = `
+{
+    "protocol": {
+        "id": "saml2",
+        "mapping_id": "ACME-v2",
+        "remote_id_attribute": "Shib-Identity-Provider",
+        "links": {
+            "identity_provider": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME",
+            "self": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2"
+        }
+    }
+}
+`
+
+var ProtocolSAML2 = federation.Protocol{
+	ID:                "saml2",
+	MappingID:         "ACME",
+	RemoteIDAttribute: "Shib-Identity-Provider",
+	Links: map[string]any{
+		"identity_provider": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME",
+		"self":              "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2",
+	},
+}
+
+var ProtocolUpdated = federation.Protocol{
+	ID:                "saml2",
+	MappingID:         "ACME-v2",
+	RemoteIDAttribute: "Shib-Identity-Provider",
+	Links: map[string]any{
+		"identity_provider": "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME",
+		"self":              "http://example.com/identity/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2",
+	},
+}
+
+// HandleListProtocolsSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/identity_providers/ACME/protocols` on the test handler mux
+// that responds with a list of protocols.
+func HandleListProtocolsSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/identity_providers/ACME/protocols", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Accept", "application/json")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListProtocolsOutput)
+	})
+}
+
+// HandleCreateProtocolSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/identity_providers/ACME/protocols/saml2` on the test
+// handler mux that tests protocol creation.
+func HandleCreateProtocolSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/identity_providers/ACME/protocols/saml2", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		th.TestJSONRequest(t, r, CreateProtocolRequest)
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, GetProtocolOutput)
+	})
+}
+
+// HandleGetProtocolSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/identity_providers/ACME/protocols/saml2` on the test
+// handler mux that responds with a single protocol.
+func HandleGetProtocolSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/identity_providers/ACME/protocols/saml2", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Accept", "application/json")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, GetProtocolOutput)
+	})
+}
+
+// HandleUpdateProtocolSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/identity_providers/ACME/protocols/saml2` on the test
+// handler mux that tests protocol update.
+func HandleUpdateProtocolSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/identity_providers/ACME/protocols/saml2", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PATCH")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		th.TestJSONRequest(t, r, UpdateProtocolRequest)
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, UpdateProtocolOutput)
+	})
+}
+
+// HandleDeleteProtocolSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/identity_providers/ACME/protocols/saml2` on the test
+// handler mux that tests protocol deletion.
+func HandleDeleteProtocolSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/identity_providers/ACME/protocols/saml2", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "DELETE")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+const ListServiceProvidersOutput = `
+{
+    "links": {
+        "next": null,
+        "previous": null,
+        "self": "http://example.com/identity/v3/OS-FEDERATION/service_providers"
+    },
+    "service_providers": [
+        {
+            "id": "BETA",
+            "auth_url": "https://beta.example.com/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2/auth",
+            "sp_url": "https://beta.example.com/Shibboleth.sso/SAML2/ECP",
+            "description": "Remote region",
+            "enabled": true,
+            "relay_state_prefix": "ss:mem:",
+            "links": {
+                "self": "http://example.com/identity/v3/OS-FEDERATION/service_providers/BETA"
+            }
+        }
+    ]
+}
+`
+
+const CreateServiceProviderRequest = `
+{
+    "service_provider": {
+        "auth_url": "https://beta.example.com/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2/auth",
+        "sp_url": "https://beta.example.com/Shibboleth.sso/SAML2/ECP",
+        "description": "Remote region",
+        "enabled": true
+    }
+}
+`
+
+const GetServiceProviderOutput = `
+{
+    "service_provider": {
+        "id": "BETA",
+        "auth_url": "https://beta.example.com/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2/auth",
+        "sp_url": "https://beta.example.com/Shibboleth.sso/SAML2/ECP",
+        "description": "Remote region",
+        "enabled": true,
+        "relay_state_prefix": "ss:mem:",
+        "links": {
+            "self": "http://example.com/identity/v3/OS-FEDERATION/service_providers/BETA"
+        }
+    }
+}
+`
+
+const UpdateServiceProviderRequest = `
+{
+    "service_provider": {
+        "description": "",
+        "enabled": false
+    }
+}
+`
+
+const UpdateServiceProviderOutput = `
+{
+    "service_provider": {
+        "id": "BETA",
+        "auth_url": "https://beta.example.com/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2/auth",
+        "sp_url": "https://beta.example.com/Shibboleth.sso/SAML2/ECP",
+        "description": "",
+        "enabled": false,
+        "relay_state_prefix": "ss:mem:",
+        "links": {
+            "self": "http://example.com/identity/v3/OS-FEDERATION/service_providers/BETA"
+        }
+    }
+}
+`
+
+var ServiceProviderBETA = federation.ServiceProvider{
+	ID:               "BETA",
+	AuthURL:          "https://beta.example.com/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2/auth",
+	SPURL:            "https://beta.example.com/Shibboleth.sso/SAML2/ECP",
+	Description:      "Remote region",
+	Enabled:          true,
+	RelayStatePrefix: "ss:mem:",
+	Links: map[string]any{
+		"self": "http://example.com/identity/v3/OS-FEDERATION/service_providers/BETA",
+	},
+}
+
+var ServiceProviderUpdated = federation.ServiceProvider{
+	ID:               "BETA",
+	AuthURL:          "https://beta.example.com/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2/auth",
+	SPURL:            "https://beta.example.com/Shibboleth.sso/SAML2/ECP",
+	RelayStatePrefix: "ss:mem:",
+	Links: map[string]any{
+		"self": "http://example.com/identity/v3/OS-FEDERATION/service_providers/BETA",
+	},
+}
+
+// HandleListServiceProvidersSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/service_providers` on the test handler mux that responds
+// with a list of service providers.
+func HandleListServiceProvidersSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/service_providers", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Accept", "application/json")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListServiceProvidersOutput)
+	})
+}
+
+// HandleCreateServiceProviderSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/service_providers/BETA` on the test handler mux that tests
+// service provider creation.
+func HandleCreateServiceProviderSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/service_providers/BETA", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		th.TestJSONRequest(t, r, CreateServiceProviderRequest)
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, GetServiceProviderOutput)
+	})
+}
+
+// HandleGetServiceProviderSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/service_providers/BETA` on the test handler mux that
+// responds with a single service provider.
+func HandleGetServiceProviderSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/service_providers/BETA", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Accept", "application/json")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, GetServiceProviderOutput)
+	})
+}
+
+// HandleUpdateServiceProviderSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/service_providers/BETA` on the test handler mux that tests
+// service provider update.
+func HandleUpdateServiceProviderSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/service_providers/BETA", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PATCH")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		th.TestJSONRequest(t, r, UpdateServiceProviderRequest)
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, UpdateServiceProviderOutput)
+	})
+}
+
+// HandleDeleteServiceProviderSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/service_providers/BETA` on the test handler mux that tests
+// service provider deletion.
+func HandleDeleteServiceProviderSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/service_providers/BETA", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "DELETE")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// SAML2MetadataOutput is a sample SAML2 metadata document.
+const SAML2MetadataOutput = `<?xml version="1.0" encoding="UTF-8"?>
+<ns0:EntityDescriptor xmlns:ns0="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://example.com/v3/OS-FEDERATION/saml2/idp">
+  <ns0:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <ns0:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:SOAP" Location="https://example.com/v3/OS-FEDERATION/saml2/sso"/>
+  </ns0:IDPSSODescriptor>
+</ns0:EntityDescriptor>
+`
+
+// HandleGetSAML2MetadataSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/saml2/metadata` on the test handler mux that responds with
+// the SAML2 metadata.
+func HandleGetSAML2MetadataSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/saml2/metadata", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Accept", "text/xml")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Set("Content-Type", "text/xml")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, SAML2MetadataOutput)
+	})
+}
+
+const ListProjectsOutput = `
+{
+    "links": {
+        "next": null,
+        "previous": null,
+        "self": "http://example.com/identity/v3/OS-FEDERATION/projects"
+    },
+    "projects": [
+        {
+            "id": "263fd9",
+            "domain_id": "1789d1",
+            "enabled": true,
+            "name": "Test Group",
+            "links": {
+                "self": "http://example.com/identity/v3/projects/263fd9"
+            }
+        }
+    ]
+}
+`
+
+const ListDomainsOutput = `
+{
+    "links": {
+        "next": null,
+        "previous": null,
+        "self": "http://example.com/identity/v3/OS-FEDERATION/domains"
+    },
+    "domains": [
+        {
+            "id": "1789d1",
+            "enabled": true,
+            "name": "acme",
+            "links": {
+                "self": "http://example.com/identity/v3/domains/1789d1"
+            }
+        }
+    ]
+}
+`
+
+// HandleListProjectsSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/projects` on the test handler mux that responds with the
+// projects available to a federated token.
+func HandleListProjectsSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/projects", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Accept", "application/json")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListProjectsOutput)
+	})
+}
+
+// HandleListDomainsSuccessfully creates an HTTP handler at
+// `/OS-FEDERATION/domains` on the test handler mux that responds with the
+// domains available to a federated token.
+func HandleListDomainsSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/OS-FEDERATION/domains", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Accept", "application/json")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListDomainsOutput)
+	})
+}
+
+const CreateAssertionRequest = `
+{
+    "auth": {
+        "identity": {
+            "methods": ["token"],
+            "token": {
+                "id": "local-token"
+            }
+        },
+        "scope": {
+            "service_provider": {
+                "id": "BETA"
+            }
+        }
+    }
+}
+`
+
+// ECPAssertionOutput is a sample ECP wrapped SAML2 assertion.
+const ECPAssertionOutput = `<?xml version="1.0" encoding="UTF-8"?>
+<soap11:Envelope xmlns:soap11="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap11:Header>
+    <ecp:RelayState xmlns:ecp="urn:oasis:names:tc:SAML:2.0:profiles:SSO:ecp" soap11:actor="http://schemas.xmlsoap.org/soap/actor/next" soap11:mustUnderstand="1">ss:mem:1234</ecp:RelayState>
+  </soap11:Header>
+  <soap11:Body>
+    <samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" ID="_a1b2c3" Version="2.0"/>
+  </soap11:Body>
+</soap11:Envelope>
+`
+
+// HandleCreateECPAssertionSuccessfully creates an HTTP handler at
+// `/auth/OS-FEDERATION/saml2/ecp` on the test handler mux that responds with
+// an ECP wrapped SAML2 assertion.
+func HandleCreateECPAssertionSuccessfully(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/auth/OS-FEDERATION/saml2/ecp", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "Accept", "application/vnd.paos+xml")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		th.TestJSONRequest(t, r, CreateAssertionRequest)
+
+		w.Header().Set("Content-Type", "application/vnd.paos+xml")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ECPAssertionOutput)
+	})
+}
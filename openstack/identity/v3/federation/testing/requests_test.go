@@ -2,9 +2,13 @@ package testing
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/domains"
 	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/federation"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/projects"
 	"github.com/gophercloud/gophercloud/v2/pagination"
 	th "github.com/gophercloud/gophercloud/v2/testhelper"
 	"github.com/gophercloud/gophercloud/v2/testhelper/client"
@@ -142,3 +146,283 @@ func TestDeleteMapping(t *testing.T) {
 	res := federation.DeleteMapping(context.TODO(), client.ServiceClient(fakeServer), "ACME")
 	th.AssertNoErr(t, res.Err)
 }
+
+func TestAuthenticate(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleAuthenticateSuccessfully(t, fakeServer)
+
+	result := federation.Authenticate(context.TODO(), client.ServiceClient(fakeServer), "ACME", "openid", federation.AuthenticateOpts{
+		AccessToken: "access-token",
+	})
+	tokenID, err := result.ExtractTokenID()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "unscoped-token", tokenID)
+
+	user, err := result.ExtractUser()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "jdoe", user.Name)
+
+	err = federation.Authenticate(context.TODO(), client.ServiceClient(fakeServer), "ACME", "openid", federation.AuthenticateOpts{}).Err
+	th.AssertEquals(t, true, errors.As(err, new(gophercloud.ErrMissingInput)))
+}
+
+func TestListIdentityProviders(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleListIdentityProvidersSuccessfully(t, fakeServer)
+
+	count := 0
+	err := federation.ListIdentityProviders(client.ServiceClient(fakeServer), federation.ListIdentityProvidersOpts{
+		Enabled: gophercloud.Enabled,
+	}).EachPage(context.TODO(), func(_ context.Context, page pagination.Page) (bool, error) {
+		count++
+
+		actual, err := federation.ExtractIdentityProviders(page)
+		th.AssertNoErr(t, err)
+
+		th.CheckDeepEquals(t, []federation.IdentityProvider{IdentityProviderACME}, actual)
+
+		return true, nil
+	})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, count, 1)
+}
+
+func TestCreateIdentityProvider(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleCreateIdentityProviderSuccessfully(t, fakeServer)
+
+	createOpts := federation.CreateIdentityProviderOpts{
+		DomainID:    "1789d1",
+		Description: "Stores ACME identities",
+		Enabled:     gophercloud.Enabled,
+		RemoteIDs:   []string{"https://acme.example.com/idp"},
+	}
+
+	actual, err := federation.CreateIdentityProvider(context.TODO(), client.ServiceClient(fakeServer), "ACME", createOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, IdentityProviderACME, *actual)
+}
+
+func TestGetIdentityProvider(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleGetIdentityProviderSuccessfully(t, fakeServer)
+
+	actual, err := federation.GetIdentityProvider(context.TODO(), client.ServiceClient(fakeServer), "ACME").Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, IdentityProviderACME, *actual)
+}
+
+func TestUpdateIdentityProvider(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleUpdateIdentityProviderSuccessfully(t, fakeServer)
+
+	remoteIDs := []string{"https://acme.example.com/idp", "https://sso.acme.example.com"}
+	ttl := 60
+	updateOpts := federation.UpdateIdentityProviderOpts{
+		Enabled:          gophercloud.Disabled,
+		RemoteIDs:        &remoteIDs,
+		AuthorizationTTL: &ttl,
+	}
+
+	actual, err := federation.UpdateIdentityProvider(context.TODO(), client.ServiceClient(fakeServer), "ACME", updateOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, IdentityProviderUpdated, *actual)
+}
+
+func TestDeleteIdentityProvider(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleDeleteIdentityProviderSuccessfully(t, fakeServer)
+
+	res := federation.DeleteIdentityProvider(context.TODO(), client.ServiceClient(fakeServer), "ACME")
+	th.AssertNoErr(t, res.Err)
+}
+
+func TestListProtocols(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleListProtocolsSuccessfully(t, fakeServer)
+
+	allPages, err := federation.ListProtocols(client.ServiceClient(fakeServer), "ACME").AllPages(context.TODO())
+	th.AssertNoErr(t, err)
+	actual, err := federation.ExtractProtocols(allPages)
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, []federation.Protocol{ProtocolSAML2}, actual)
+}
+
+func TestCreateProtocol(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleCreateProtocolSuccessfully(t, fakeServer)
+
+	createOpts := federation.CreateProtocolOpts{
+		MappingID:         "ACME",
+		RemoteIDAttribute: "Shib-Identity-Provider",
+	}
+
+	actual, err := federation.CreateProtocol(context.TODO(), client.ServiceClient(fakeServer), "ACME", "saml2", createOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ProtocolSAML2, *actual)
+
+	err = federation.CreateProtocol(context.TODO(), client.ServiceClient(fakeServer), "ACME", "saml2", federation.CreateProtocolOpts{}).Err
+	th.AssertEquals(t, true, errors.As(err, new(gophercloud.ErrMissingInput)))
+}
+
+func TestGetProtocol(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleGetProtocolSuccessfully(t, fakeServer)
+
+	actual, err := federation.GetProtocol(context.TODO(), client.ServiceClient(fakeServer), "ACME", "saml2").Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ProtocolSAML2, *actual)
+}
+
+func TestUpdateProtocol(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleUpdateProtocolSuccessfully(t, fakeServer)
+
+	updateOpts := federation.UpdateProtocolOpts{
+		MappingID: "ACME-v2",
+	}
+
+	actual, err := federation.UpdateProtocol(context.TODO(), client.ServiceClient(fakeServer), "ACME", "saml2", updateOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ProtocolUpdated, *actual)
+}
+
+func TestDeleteProtocol(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleDeleteProtocolSuccessfully(t, fakeServer)
+
+	res := federation.DeleteProtocol(context.TODO(), client.ServiceClient(fakeServer), "ACME", "saml2")
+	th.AssertNoErr(t, res.Err)
+}
+
+func TestListServiceProviders(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleListServiceProvidersSuccessfully(t, fakeServer)
+
+	allPages, err := federation.ListServiceProviders(client.ServiceClient(fakeServer), nil).AllPages(context.TODO())
+	th.AssertNoErr(t, err)
+	actual, err := federation.ExtractServiceProviders(allPages)
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, []federation.ServiceProvider{ServiceProviderBETA}, actual)
+}
+
+func TestCreateServiceProvider(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleCreateServiceProviderSuccessfully(t, fakeServer)
+
+	createOpts := federation.CreateServiceProviderOpts{
+		AuthURL:     "https://beta.example.com/v3/OS-FEDERATION/identity_providers/ACME/protocols/saml2/auth",
+		SPURL:       "https://beta.example.com/Shibboleth.sso/SAML2/ECP",
+		Description: "Remote region",
+		Enabled:     gophercloud.Enabled,
+	}
+
+	actual, err := federation.CreateServiceProvider(context.TODO(), client.ServiceClient(fakeServer), "BETA", createOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ServiceProviderBETA, *actual)
+}
+
+func TestGetServiceProvider(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleGetServiceProviderSuccessfully(t, fakeServer)
+
+	actual, err := federation.GetServiceProvider(context.TODO(), client.ServiceClient(fakeServer), "BETA").Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ServiceProviderBETA, *actual)
+}
+
+func TestUpdateServiceProvider(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleUpdateServiceProviderSuccessfully(t, fakeServer)
+
+	description := ""
+	updateOpts := federation.UpdateServiceProviderOpts{
+		Description: &description,
+		Enabled:     gophercloud.Disabled,
+	}
+
+	actual, err := federation.UpdateServiceProvider(context.TODO(), client.ServiceClient(fakeServer), "BETA", updateOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, ServiceProviderUpdated, *actual)
+}
+
+func TestDeleteServiceProvider(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleDeleteServiceProviderSuccessfully(t, fakeServer)
+
+	res := federation.DeleteServiceProvider(context.TODO(), client.ServiceClient(fakeServer), "BETA")
+	th.AssertNoErr(t, res.Err)
+}
+
+func TestGetSAML2Metadata(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleGetSAML2MetadataSuccessfully(t, fakeServer)
+
+	actual, err := federation.GetSAML2Metadata(context.TODO(), client.ServiceClient(fakeServer)).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, SAML2MetadataOutput, string(actual))
+}
+
+func TestListProjects(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleListProjectsSuccessfully(t, fakeServer)
+
+	allPages, err := federation.ListProjects(client.ServiceClient(fakeServer)).AllPages(context.TODO())
+	th.AssertNoErr(t, err)
+	actual, err := projects.ExtractProjects(allPages)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(actual))
+	th.CheckEquals(t, "263fd9", actual[0].ID)
+	th.CheckEquals(t, "Test Group", actual[0].Name)
+	th.CheckEquals(t, "1789d1", actual[0].DomainID)
+}
+
+func TestListDomains(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleListDomainsSuccessfully(t, fakeServer)
+
+	allPages, err := federation.ListDomains(client.ServiceClient(fakeServer)).AllPages(context.TODO())
+	th.AssertNoErr(t, err)
+	actual, err := domains.ExtractDomains(allPages)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(actual))
+	th.CheckEquals(t, "1789d1", actual[0].ID)
+	th.CheckEquals(t, "acme", actual[0].Name)
+}
+
+func TestCreateECPAssertion(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	HandleCreateECPAssertionSuccessfully(t, fakeServer)
+
+	createOpts := federation.CreateAssertionOpts{
+		TokenID:           "local-token",
+		ServiceProviderID: "BETA",
+	}
+
+	actual, err := federation.CreateECPAssertion(context.TODO(), client.ServiceClient(fakeServer), createOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, ECPAssertionOutput, string(actual))
+
+	err = federation.CreateECPAssertion(context.TODO(), client.ServiceClient(fakeServer), federation.CreateAssertionOpts{TokenID: "local-token"}).Err
+	th.AssertEquals(t, true, errors.As(err, new(gophercloud.ErrMissingInput)))
+}
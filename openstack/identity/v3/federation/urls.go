@@ -3,8 +3,17 @@ package federation
 import "github.com/gophercloud/gophercloud/v2"
 
 const (
-	rootPath     = "OS-FEDERATION"
-	mappingsPath = "mappings"
+	rootPath      = "OS-FEDERATION"
+	mappingsPath  = "mappings"
+	idpsPath      = "identity_providers"
+	protocolsPath = "protocols"
+	authPath      = "auth"
+	spsPath       = "service_providers"
+	saml2Path     = "saml2"
+	metadataPath  = "metadata"
+	projectsPath  = "projects"
+	domainsPath   = "domains"
+	ecpPath       = "ecp"
 )
 
 func mappingsRootURL(c *gophercloud.ServiceClient) string {
@@ -14,3 +23,51 @@ func mappingsRootURL(c *gophercloud.ServiceClient) string {
 func mappingsResourceURL(c *gophercloud.ServiceClient, mappingID string) string {
 	return c.ServiceURL(rootPath, mappingsPath, mappingID)
 }
+
+func authURL(c *gophercloud.ServiceClient, idpID, protocolID string) string {
+	return c.ServiceURL(rootPath, idpsPath, idpID, protocolsPath, protocolID, authPath)
+}
+
+func identityProvidersRootURL(c *gophercloud.ServiceClient) string {
+	return c.ServiceURL(rootPath, idpsPath)
+}
+
+func identityProvidersResourceURL(c *gophercloud.ServiceClient, idpID string) string {
+	return c.ServiceURL(rootPath, idpsPath, idpID)
+}
+
+func protocolsRootURL(c *gophercloud.ServiceClient, idpID string) string {
+	return c.ServiceURL(rootPath, idpsPath, idpID, protocolsPath)
+}
+
+func protocolsResourceURL(c *gophercloud.ServiceClient, idpID, protocolID string) string {
+	return c.ServiceURL(rootPath, idpsPath, idpID, protocolsPath, protocolID)
+}
+
+func serviceProvidersRootURL(c *gophercloud.ServiceClient) string {
+	return c.ServiceURL(rootPath, spsPath)
+}
+
+func serviceProvidersResourceURL(c *gophercloud.ServiceClient, spID string) string {
+	return c.ServiceURL(rootPath, spsPath, spID)
+}
+
+func saml2MetadataURL(c *gophercloud.ServiceClient) string {
+	return c.ServiceURL(rootPath, saml2Path, metadataPath)
+}
+
+func projectsURL(c *gophercloud.ServiceClient) string {
+	return c.ServiceURL(rootPath, projectsPath)
+}
+
+func domainsURL(c *gophercloud.ServiceClient) string {
+	return c.ServiceURL(rootPath, domainsPath)
+}
+
+func saml2AssertionURL(c *gophercloud.ServiceClient) string {
+	return c.ServiceURL(authPath, rootPath, saml2Path)
+}
+
+func ecpAssertionURL(c *gophercloud.ServiceClient) string {
+	return c.ServiceURL(authPath, rootPath, saml2Path, ecpPath)
+}
@@ -0,0 +1,65 @@
+/*
+Package k2k enables authenticating with the Identity service of a remote cloud
+through Keystone to Keystone federation, like the Keystone2Keystone plugin of
+keystoneauth.
+
+The local Identity service acts as the identity provider of the remote one,
+which is registered in it as a service provider. The options obtain an ECP
+wrapped SAML2 assertion for the service provider from the local Identity
+service, post it to the service provider, and exchange the resulting session
+for an unscoped token of the remote Identity service. The unscoped token is
+then rescoped to the project or domain of the gophercloud.AuthOptions they are
+set in.
+
+The service providers available to a user are listed in the tokens of the
+local Identity service, see tokens.ExtractServiceProviders.
+
+Example to Authenticate with a Remote Cloud
+
+	local, err := openstack.AuthenticatedClient(context.TODO(), localAuthOpts)
+	if err != nil {
+		panic(err)
+	}
+
+	remote, err := k2k.AuthenticatedClient(context.TODO(), local, "beta", gophercloud.AuthOptions{
+		TenantName:  "demo",
+		DomainName:  "Default",
+		AllowReauth: true,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	computeClient, err := openstack.NewComputeV2(context.TODO(), remote, gophercloud.EndpointOpts{
+		Region: "RegionTwo",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+Example to Authenticate with the Options of a Remote Cloud
+
+	localIdentity, err := openstack.NewIdentityV3(context.TODO(), local, gophercloud.EndpointOpts{})
+	if err != nil {
+		panic(err)
+	}
+
+	opts := gophercloud.AuthOptions{
+		IdentityEndpoint: "https://beta.example.com:5000/v3",
+		TenantID:         "3c44b3d1bdb24e1cbe9d6d77ba8d6cbc",
+		Federated: &k2k.AuthOptions{
+			Local:           localIdentity,
+			ServiceProvider: "beta",
+		},
+	}
+
+	remote, err := openstack.AuthenticatedClient(context.TODO(), opts)
+	if err != nil {
+		panic(err)
+	}
+
+When AllowReauth is set, a new assertion is requested with the token of the
+local client once the token of the remote cloud expires, so the local client
+should be able to reauthenticate as well.
+*/
+package k2k
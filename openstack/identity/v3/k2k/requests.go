@@ -0,0 +1,171 @@
+package k2k
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/internal/ecp"
+	"github.com/gophercloud/gophercloud/v2/openstack"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/federation"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/tokens"
+)
+
+// AuthOptions represents options for authenticating with the Identity service
+// of a service provider, with a SAML2 assertion issued by the local Identity
+// service, like the keystoneauth Keystone2Keystone plugin. It satisfies the
+// gophercloud.FederatedAuth interface, to be set as the Federated field of
+// the gophercloud.AuthOptions of the remote cloud.
+type AuthOptions struct {
+	// Local is an authenticated client of the Identity V3 API of the local
+	// cloud, which acts as the identity provider.
+	Local *gophercloud.ServiceClient
+
+	// ServiceProvider is the ID of the service provider of the remote cloud
+	// in the local Identity service.
+	ServiceProvider string
+}
+
+// FederatedToken obtains an ECP wrapped SAML2 assertion for the service
+// provider from the local Identity service, posts it to the service provider
+// and returns the unscoped token issued by its Identity service.
+func (opts AuthOptions) FederatedToken(ctx context.Context, client *gophercloud.ServiceClient) (string, error) {
+	sp, err := opts.serviceProvider(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var assertion []byte
+	err = opts.withLocalToken(func(tokenID string) error {
+		assertion, err = federation.CreateECPAssertion(ctx, opts.Local, federation.CreateAssertionOpts{
+			TokenID:           tokenID,
+			ServiceProviderID: opts.ServiceProvider,
+		}).Extract()
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return exchangeAssertion(ctx, client.ProviderClient, sp, assertion)
+}
+
+// serviceProvider returns the service provider of the options, as listed in
+// the token of the local client.
+func (opts AuthOptions) serviceProvider(ctx context.Context) (*tokens.ServiceProvider, error) {
+	if opts.Local == nil {
+		return nil, gophercloud.ErrMissingInput{Argument: "Local"}
+	}
+	if opts.ServiceProvider == "" {
+		return nil, gophercloud.ErrMissingInput{Argument: "ServiceProvider"}
+	}
+
+	var sps []tokens.ServiceProvider
+	var err error
+	if r, ok := opts.Local.GetAuthResult().(interface {
+		ExtractServiceProviders() ([]tokens.ServiceProvider, error)
+	}); ok {
+		sps, err = r.ExtractServiceProviders()
+	} else {
+		err = opts.withLocalToken(func(tokenID string) error {
+			sps, err = tokens.Get(ctx, opts.Local, tokenID, nil).ExtractServiceProviders()
+			return err
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sp := range sps {
+		if sp.ID == opts.ServiceProvider {
+			return &sp, nil
+		}
+	}
+	return nil, gophercloud.ErrResourceNotFound{Name: opts.ServiceProvider, ResourceType: "service provider"}
+}
+
+// withLocalToken calls f with the token of the local client, which f sends in
+// a request body or header. If the local client reauthenticated while f was
+// sending its request, the retried request still carried the expired token,
+// so f is called again with the new one.
+func (opts AuthOptions) withLocalToken(f func(tokenID string) error) error {
+	tokenID := opts.Local.Token()
+	err := f(tokenID)
+	if err != nil && opts.Local.Token() != tokenID {
+		return f(opts.Local.Token())
+	}
+	return err
+}
+
+// exchangeAssertion posts an ECP wrapped SAML2 assertion to the ECP endpoint
+// of a service provider, and exchanges the resulting SAML2 session for an
+// unscoped token at the federated auth endpoint of its Identity service.
+func exchangeAssertion(ctx context.Context, client *gophercloud.ProviderClient, sp *tokens.ServiceProvider, assertion []byte) (string, error) {
+	session, err := ecp.NewSession(client)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sp.SPURL, bytes.NewReader(assertion))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", ecp.ContentType)
+	if _, _, err := session.Do(req, http.StatusOK, http.StatusFound, http.StatusSeeOther); err != nil {
+		return "", err
+	}
+
+	return session.Token(ctx, sp.AuthURL)
+}
+
+// AuthenticatedClient authenticates with the Identity service of a service
+// provider of the cloud of local, and returns a ProviderClient of the remote
+// cloud. The token is scoped and reauthenticated according to opts, whose
+// Federated field is set by AuthenticatedClient. If the IdentityEndpoint of
+// opts is not set, it is derived from the federated auth URL of the service
+// provider.
+func AuthenticatedClient(ctx context.Context, local *gophercloud.ProviderClient, serviceProvider string, opts gophercloud.AuthOptions) (*gophercloud.ProviderClient, error) {
+	identity, err := openstack.NewIdentityV3(ctx, local, gophercloud.EndpointOpts{})
+	if err != nil {
+		return nil, err
+	}
+
+	k2kOpts := &AuthOptions{
+		Local:           identity,
+		ServiceProvider: serviceProvider,
+	}
+	if opts.IdentityEndpoint == "" {
+		sp, err := k2kOpts.serviceProvider(ctx)
+		if err != nil {
+			return nil, err
+		}
+		opts.IdentityEndpoint = remoteIdentityEndpoint(sp.AuthURL)
+	}
+	opts.Federated = k2kOpts
+
+	remote, err := openstack.NewClient(opts.IdentityEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	remote.HTTPClient = local.HTTPClient
+	remote.UserAgent = local.UserAgent
+
+	err = openstack.AuthenticateV3(ctx, remote, &opts, gophercloud.EndpointOpts{})
+	if err != nil {
+		return nil, err
+	}
+	return remote, nil
+}
+
+// remoteIdentityEndpoint returns the endpoint of the Identity service of a
+// service provider, which is the part of its federated auth URL before the
+// OS-FEDERATION path, like in keystoneauth. The whole auth URL is returned if
+// it does not contain such a path.
+func remoteIdentityEndpoint(authURL string) string {
+	if i := strings.Index(authURL, "/OS-FEDERATION/"); i >= 0 {
+		return authURL[:i]
+	}
+	return authURL
+}
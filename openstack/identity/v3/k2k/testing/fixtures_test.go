@@ -0,0 +1,195 @@
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+const (
+	// UnscopedTokenID is the token returned by the federated auth endpoint
+	// of the service provider.
+	UnscopedTokenID = "unscoped-token"
+
+	// ScopedTokenID is the token returned by the rescope request of the
+	// service provider.
+	ScopedTokenID = "scoped-token"
+
+	// SessionCookie is the SAML2 session cookie of the service provider.
+	SessionCookie = "_shibsession_beta"
+
+	// ECPAssertion is the assertion issued by the local cloud.
+	ECPAssertion = `<soap11:Envelope xmlns:soap11="http://schemas.xmlsoap.org/soap/envelope/"/>`
+)
+
+// LocalTokenOutput is the body of the token responses of the local Identity
+// service, which lists the service provider of the remote cloud.
+const LocalTokenOutput = `
+{
+	"token": {
+		"methods": ["password"],
+		"expires_at": "2030-01-01T00:00:00.000000Z",
+		"service_providers": [
+			{
+				"id": "beta",
+				"auth_url": "%[1]sremote/v3/OS-FEDERATION/identity_providers/acme/protocols/saml2/auth",
+				"sp_url": "%[1]sremote/Shibboleth.sso/SAML2/ECP"
+			}
+		]
+	}
+}
+`
+
+// RemoteTokenOutput is the body of the token responses of the remote Identity
+// service.
+const RemoteTokenOutput = `
+{
+	"token": {
+		"methods": ["token"],
+		"expires_at": "2030-01-01T00:00:00.000000Z",
+		"catalog": [
+			{
+				"type": "compute",
+				"name": "nova",
+				"endpoints": [
+					{"interface": "public", "region": "RegionTwo", "url": "%sremote/compute/v2.1/"}
+				]
+			}
+		]
+	}
+}
+`
+
+// RescopeRequest is the expected rescope request of the unscoped token.
+const RescopeRequest = `
+{
+	"auth": {
+		"identity": {
+			"methods": ["token"],
+			"token": {"id": "unscoped-token"}
+		},
+		"scope": {
+			"project": {"id": "3c44b3d1bdb24e1cbe9d6d77ba8d6cbc"}
+		}
+	}
+}
+`
+
+// LocalIdentity is the local Identity service, which issues the tokens
+// local-token-1, local-token-2... and counts the assertions it issues.
+type LocalIdentity struct {
+	Tokens     int
+	Assertions int
+
+	// Expired, if set, makes the current token expire before the next
+	// assertion request.
+	Expired bool
+}
+
+// TokenID returns the current token of the local Identity service.
+func (l *LocalIdentity) TokenID() string {
+	return fmt.Sprintf("local-token-%d", l.Tokens)
+}
+
+// HandleLocalIdentity registers the token and ECP assertion endpoints of the
+// local Identity service.
+func (l *LocalIdentity) HandleLocalIdentity(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/local/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		l.Tokens++
+		l.Expired = false
+
+		w.Header().Add("X-Subject-Token", l.TokenID())
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, LocalTokenOutput, fakeServer.Endpoint())
+	})
+
+	fakeServer.Mux.HandleFunc("/local/v3/auth/OS-FEDERATION/saml2/ecp", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		if l.Expired || r.Header.Get("X-Auth-Token") != l.TokenID() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var body struct {
+			Auth struct {
+				Identity struct {
+					Token struct {
+						ID string `json:"id"`
+					} `json:"token"`
+				} `json:"identity"`
+			} `json:"auth"`
+		}
+		b, err := io.ReadAll(r.Body)
+		th.AssertNoErr(t, err)
+		th.AssertNoErr(t, json.Unmarshal(b, &body))
+		// the token of the body is validated with the token of the header
+		if body.Auth.Identity.Token.ID != l.TokenID() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var actual any
+		th.AssertNoErr(t, json.Unmarshal(b, &actual))
+		th.CheckJSONEquals(t, fmt.Sprintf(`
+			{
+				"auth": {
+					"identity": {
+						"methods": ["token"],
+						"token": {"id": "%s"}
+					},
+					"scope": {
+						"service_provider": {"id": "beta"}
+					}
+				}
+			}
+		`, l.TokenID()), actual)
+		l.Assertions++
+
+		w.Header().Add("Content-Type", "application/vnd.paos+xml")
+		fmt.Fprint(w, ECPAssertion)
+	})
+}
+
+// HandleRemoteIdentity registers the ECP endpoint of the service provider and
+// the federated auth and token endpoints of the remote Identity service.
+func HandleRemoteIdentity(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/remote/Shibboleth.sso/SAML2/ECP", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "Content-Type", "application/vnd.paos+xml")
+		th.AssertEquals(t, "", r.Header.Get("X-Auth-Token"))
+		body, err := io.ReadAll(r.Body)
+		th.AssertNoErr(t, err)
+		th.AssertEquals(t, ECPAssertion, string(body))
+
+		http.SetCookie(w, &http.Cookie{Name: SessionCookie, Value: "session", Path: "/"})
+		http.Redirect(w, r, fakeServer.Endpoint()+"remote/v3/OS-FEDERATION/identity_providers/acme/protocols/saml2/auth", http.StatusFound)
+	})
+
+	fakeServer.Mux.HandleFunc("/remote/v3/OS-FEDERATION/identity_providers/acme/protocols/saml2/auth", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.AssertEquals(t, "", r.Header.Get("X-Auth-Token"))
+		cookie, err := r.Cookie(SessionCookie)
+		th.AssertNoErr(t, err)
+		th.AssertEquals(t, "session", cookie.Value)
+
+		w.Header().Add("X-Subject-Token", UnscopedTokenID)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, RemoteTokenOutput, fakeServer.Endpoint())
+	})
+
+	fakeServer.Mux.HandleFunc("/remote/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestJSONRequest(t, r, RescopeRequest)
+
+		w.Header().Add("X-Subject-Token", ScopedTokenID)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, RemoteTokenOutput, fakeServer.Endpoint())
+	})
+}
@@ -0,0 +1,181 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/k2k"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+const projectID = "3c44b3d1bdb24e1cbe9d6d77ba8d6cbc"
+
+func localClient(t *testing.T, fakeServer th.FakeServer) *gophercloud.ProviderClient {
+	local, err := openstack.AuthenticatedClient(context.TODO(), gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "local/v3/",
+		UserID:           "jdoe",
+		Password:         "secret",
+		AllowReauth:      true,
+	})
+	th.AssertNoErr(t, err)
+	return local
+}
+
+func TestAuthenticatedClient(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var localIdentity LocalIdentity
+	localIdentity.HandleLocalIdentity(t, fakeServer)
+	HandleRemoteIdentity(t, fakeServer)
+
+	remote, err := k2k.AuthenticatedClient(context.TODO(), localClient(t, fakeServer), "beta", gophercloud.AuthOptions{
+		TenantID: projectID,
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, ScopedTokenID, remote.TokenID)
+	th.AssertEquals(t, fakeServer.Endpoint()+"remote/v3/", remote.IdentityEndpoint)
+	th.AssertEquals(t, 1, localIdentity.Assertions)
+
+	compute, err := openstack.NewComputeV2(context.TODO(), remote, gophercloud.EndpointOpts{Region: "RegionTwo"})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, fakeServer.Endpoint()+"remote/compute/v2.1/", compute.Endpoint)
+}
+
+func TestAuthenticatedClientFederatedOptions(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var localIdentity LocalIdentity
+	localIdentity.HandleLocalIdentity(t, fakeServer)
+	HandleRemoteIdentity(t, fakeServer)
+
+	identity, err := openstack.NewIdentityV3(context.TODO(), localClient(t, fakeServer), gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+
+	remote, err := openstack.AuthenticatedClient(context.TODO(), gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "remote/v3/",
+		TenantID:         projectID,
+		Federated: &k2k.AuthOptions{
+			Local:           identity,
+			ServiceProvider: "beta",
+		},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, ScopedTokenID, remote.TokenID)
+}
+
+func TestReauthenticate(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var localIdentity LocalIdentity
+	localIdentity.HandleLocalIdentity(t, fakeServer)
+	HandleRemoteIdentity(t, fakeServer)
+
+	expired := true
+	fakeServer.Mux.HandleFunc("/remote/compute/v2.1/servers", func(w http.ResponseWriter, r *http.Request) {
+		if expired {
+			expired = false
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		th.TestHeader(t, r, "X-Auth-Token", ScopedTokenID)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	remote, err := k2k.AuthenticatedClient(context.TODO(), localClient(t, fakeServer), "beta", gophercloud.AuthOptions{
+		TenantID:    projectID,
+		AllowReauth: true,
+	})
+	th.AssertNoErr(t, err)
+
+	_, err = remote.Request(context.TODO(), "GET", fakeServer.Endpoint()+"remote/compute/v2.1/servers", &gophercloud.RequestOpts{
+		OkCodes: []int{http.StatusNoContent},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, localIdentity.Assertions)
+}
+
+func TestReauthenticateExpiredLocalToken(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var localIdentity LocalIdentity
+	localIdentity.HandleLocalIdentity(t, fakeServer)
+	HandleRemoteIdentity(t, fakeServer)
+
+	expired := true
+	fakeServer.Mux.HandleFunc("/remote/compute/v2.1/servers", func(w http.ResponseWriter, r *http.Request) {
+		if expired {
+			expired = false
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		th.TestHeader(t, r, "X-Auth-Token", ScopedTokenID)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	remote, err := k2k.AuthenticatedClient(context.TODO(), localClient(t, fakeServer), "beta", gophercloud.AuthOptions{
+		TenantID:    projectID,
+		AllowReauth: true,
+	})
+	th.AssertNoErr(t, err)
+
+	// the local token expires along with the remote one, so the assertion
+	// is requested with the token of the reauthenticated local client
+	localIdentity.Expired = true
+	_, err = remote.Request(context.TODO(), "GET", fakeServer.Endpoint()+"remote/compute/v2.1/servers", &gophercloud.RequestOpts{
+		OkCodes: []int{http.StatusNoContent},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, localIdentity.Tokens)
+	th.AssertEquals(t, 2, localIdentity.Assertions)
+}
+
+func TestMissingSubjectToken(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var localIdentity LocalIdentity
+	localIdentity.HandleLocalIdentity(t, fakeServer)
+	fakeServer.Mux.HandleFunc("/remote/Shibboleth.sso/SAML2/ECP", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusFound)
+	})
+	fakeServer.Mux.HandleFunc("/remote/v3/OS-FEDERATION/identity_providers/acme/protocols/saml2/auth", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	fakeServer.Mux.HandleFunc("/remote/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected rescope request")
+	})
+
+	_, err := k2k.AuthenticatedClient(context.TODO(), localClient(t, fakeServer), "beta", gophercloud.AuthOptions{
+		TenantID: projectID,
+	})
+	th.AssertErr(t, err)
+	th.AssertEquals(t, true, strings.Contains(err.Error(), "X-Subject-Token"))
+}
+
+func TestServiceProviderErrors(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var localIdentity LocalIdentity
+	localIdentity.HandleLocalIdentity(t, fakeServer)
+	fakeServer.Mux.HandleFunc("/remote/Shibboleth.sso/SAML2/ECP", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	local := localClient(t, fakeServer)
+
+	_, err := k2k.AuthenticatedClient(context.TODO(), local, "gamma", gophercloud.AuthOptions{})
+	th.AssertEquals(t, true, errors.As(err, new(gophercloud.ErrResourceNotFound)))
+
+	_, err = k2k.AuthenticatedClient(context.TODO(), local, "beta", gophercloud.AuthOptions{})
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusForbidden))
+}
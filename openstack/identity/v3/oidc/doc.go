@@ -0,0 +1,80 @@
+/*
+Package oidc enables authenticating with the Identity service through an
+OpenID Connect identity provider, like the v3oidc* plugins of keystoneauth.
+
+The options obtain an access token from the identity provider with one of the
+supported OAuth 2.0 grants, exchange it for an unscoped token at the
+OS-FEDERATION auth endpoint of the identity provider and protocol, and the
+unscoped token is then rescoped to the project or domain of the
+gophercloud.AuthOptions they are set in.
+
+Example to Authenticate with a Username and a Password (v3oidcpassword)
+
+	opts := gophercloud.AuthOptions{
+		IdentityEndpoint: "https://keystone.example.com:5000/v3",
+		TenantName:       "demo",
+		DomainName:       "Default",
+		AllowReauth:      true,
+		Federated: &oidc.AuthOptions{
+			IdentityProvider:  "sso",
+			Protocol:          "openid",
+			GrantType:         oidc.GrantPassword,
+			ClientID:          "openstack",
+			ClientSecret:      "secret",
+			DiscoveryEndpoint: "https://sso.example.com/.well-known/openid-configuration",
+			Username:          "jdoe",
+			Password:          "password",
+		},
+	}
+
+	provider, err := openstack.AuthenticatedClient(context.TODO(), opts)
+	if err != nil {
+		panic(err)
+	}
+
+Example to Authenticate with an Access Token (v3oidcaccesstoken)
+
+	opts := gophercloud.AuthOptions{
+		IdentityEndpoint: "https://keystone.example.com:5000/v3",
+		TenantID:         "3c44b3d1bdb24e1cbe9d6d77ba8d6cbc",
+		Federated: &oidc.AuthOptions{
+			IdentityProvider: "sso",
+			Protocol:         "openid",
+			AccessToken:      accessToken,
+		},
+	}
+
+	provider, err := openstack.AuthenticatedClient(context.TODO(), opts)
+	if err != nil {
+		panic(err)
+	}
+
+Example to Authenticate in a Browser (v3oidcdeviceauthz)
+
+	opts := gophercloud.AuthOptions{
+		IdentityEndpoint: "https://keystone.example.com:5000/v3",
+		TenantID:         "3c44b3d1bdb24e1cbe9d6d77ba8d6cbc",
+		Federated: &oidc.AuthOptions{
+			IdentityProvider:    "sso",
+			Protocol:            "openid",
+			GrantType:           oidc.GrantDeviceCode,
+			ClientID:            "openstack",
+			DiscoveryEndpoint:   "https://sso.example.com/.well-known/openid-configuration",
+			CodeChallengeMethod: "S256",
+			DevicePrompt: func(a oidc.DeviceAuthorization) {
+				fmt.Printf("Open %s and enter %s\n", a.VerificationURI, a.UserCode)
+			},
+		},
+	}
+
+	provider, err := openstack.AuthenticatedClient(context.TODO(), opts)
+	if err != nil {
+		panic(err)
+	}
+
+When AllowReauth is set, the whole exchange is performed again once the token
+expires. Since authorization codes can only be used once, and since the device
+authorization grant would prompt the user again, the client never
+reauthenticates with these two grants, regardless of AllowReauth.
+*/
+package oidc
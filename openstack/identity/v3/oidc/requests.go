@@ -0,0 +1,412 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/federation"
+)
+
+// GrantType is the OAuth 2.0 grant used to obtain an access token from the
+// identity provider.
+type GrantType string
+
+const (
+	// GrantPassword obtains an access token with the username and password
+	// of the user, like the v3oidcpassword plugin.
+	GrantPassword GrantType = "password"
+
+	// GrantClientCredentials obtains an access token with the credentials
+	// of the client, like the v3oidcclientcredentials plugin.
+	GrantClientCredentials GrantType = "client_credentials"
+
+	// GrantAuthorizationCode obtains an access token with an authorization
+	// code, like the v3oidcauthcode plugin.
+	GrantAuthorizationCode GrantType = "authorization_code"
+
+	// GrantDeviceCode obtains an access token with the device authorization
+	// grant, in which the user authorizes the request in a browser, like the
+	// v3oidcdeviceauthz plugin.
+	GrantDeviceCode GrantType = "urn:ietf:params:oauth:grant-type:device_code"
+)
+
+const (
+	// AccessTokenTypeAccessToken sends the OAuth 2.0 access token of the
+	// identity provider to the Identity service.
+	AccessTokenTypeAccessToken = "access_token"
+
+	// AccessTokenTypeIDToken sends the OpenID Connect ID token of the
+	// identity provider to the Identity service.
+	AccessTokenTypeIDToken = "id_token"
+
+	// DefaultScope is the OpenID Connect scope requested by default.
+	DefaultScope = "openid profile"
+
+	// defaultPollInterval is the interval between the token requests of the
+	// device authorization grant if the identity provider does not set it.
+	defaultPollInterval = 5 * time.Second
+)
+
+// AuthOptions represents options for authenticating with an OpenID Connect
+// identity provider of the Identity service. It satisfies the
+// gophercloud.FederatedAuth interface, to be set as the Federated field of a
+// gophercloud.AuthOptions.
+type AuthOptions struct {
+	// IdentityProvider is the ID of the identity provider in the Identity
+	// service.
+	IdentityProvider string `required:"true"`
+
+	// Protocol is the ID of the federation protocol of the identity provider
+	// in the Identity service, usually "openid".
+	Protocol string `required:"true"`
+
+	// AccessToken is an access token obtained beforehand from the identity
+	// provider, like with the v3oidcaccesstoken plugin. If set, it is sent
+	// to the Identity service as is and the other options are ignored.
+	AccessToken string
+
+	// GrantType is the grant used to obtain an access token from the
+	// identity provider. It is required unless AccessToken is set.
+	GrantType GrantType
+
+	// ClientID is the ID of the OAuth 2.0 client registered with the
+	// identity provider.
+	ClientID string
+
+	// ClientSecret is the secret of the client. If set, the client
+	// authenticates with HTTP basic authentication; otherwise it is a public
+	// client which only sends its ID.
+	ClientSecret string
+
+	// DiscoveryEndpoint is the URL of the OpenID Connect discovery document
+	// of the identity provider, usually ending with
+	// /.well-known/openid-configuration. It is used to find the endpoints
+	// which are not set below.
+	DiscoveryEndpoint string
+
+	// AccessTokenEndpoint is the URL of the token endpoint of the identity
+	// provider.
+	AccessTokenEndpoint string
+
+	// DeviceAuthorizationEndpoint is the URL of the device authorization
+	// endpoint of the identity provider, used with GrantDeviceCode.
+	DeviceAuthorizationEndpoint string
+
+	// OpenIDScope is the space-separated list of OpenID Connect scopes to
+	// request. It defaults to DefaultScope.
+	OpenIDScope string
+
+	// AccessTokenType is the token of the response of the identity provider
+	// which is sent to the Identity service: AccessTokenTypeAccessToken, the
+	// default, or AccessTokenTypeIDToken.
+	AccessTokenType string
+
+	// Username and Password are the credentials of the user with
+	// GrantPassword.
+	Username string
+	Password string
+
+	// Code is the authorization code with GrantAuthorizationCode, and
+	// RedirectURI the redirection URI it was issued for. CodeVerifier is the
+	// PKCE code verifier of the authorization request, if any.
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+
+	// CodeChallengeMethod enables PKCE with GrantDeviceCode: "S256" or
+	// "plain".
+	CodeChallengeMethod string
+
+	// DevicePrompt is called with GrantDeviceCode to ask the user to
+	// authorize the request. It defaults to printing the verification URI
+	// and the user code to the standard error.
+	DevicePrompt func(DeviceAuthorization)
+}
+
+// FederatedToken obtains an access token from the identity provider and
+// exchanges it for an unscoped token of the Identity service, to satisfy the
+// gophercloud.FederatedAuth interface.
+func (opts AuthOptions) FederatedToken(ctx context.Context, client *gophercloud.ServiceClient) (string, error) {
+	if opts.IdentityProvider == "" {
+		return "", gophercloud.ErrMissingInput{Argument: "IdentityProvider"}
+	}
+	if opts.Protocol == "" {
+		return "", gophercloud.ErrMissingInput{Argument: "Protocol"}
+	}
+
+	accessToken, err := opts.accessToken(ctx, client.ProviderClient)
+	if err != nil {
+		return "", err
+	}
+
+	return federation.Authenticate(ctx, client, opts.IdentityProvider, opts.Protocol, federation.AuthenticateOpts{
+		AccessToken: accessToken,
+	}).ExtractTokenID()
+}
+
+// CanReauth reports whether the options can obtain a token again, for
+// gophercloud.AuthOptions.CanReauth. An authorization code can only be used
+// once, and the device authorization grant would prompt the user again.
+func (opts AuthOptions) CanReauth() bool {
+	if opts.AccessToken != "" {
+		return true
+	}
+	return opts.GrantType != GrantAuthorizationCode && opts.GrantType != GrantDeviceCode
+}
+
+// accessToken returns the token sent to the Identity service, obtained from
+// the identity provider with the grant of the options.
+func (opts AuthOptions) accessToken(ctx context.Context, client *gophercloud.ProviderClient) (string, error) {
+	if opts.AccessToken != "" {
+		return opts.AccessToken, nil
+	}
+
+	form := url.Values{
+		"grant_type": {string(opts.GrantType)},
+		"scope":      {opts.OpenIDScope},
+	}
+	if opts.OpenIDScope == "" {
+		form.Set("scope", DefaultScope)
+	}
+
+	switch opts.GrantType {
+	case GrantPassword:
+		if opts.Username == "" {
+			return "", gophercloud.ErrMissingInput{Argument: "Username"}
+		}
+		if opts.Password == "" {
+			return "", gophercloud.ErrMissingInput{Argument: "Password"}
+		}
+		form.Set("username", opts.Username)
+		form.Set("password", opts.Password)
+	case GrantClientCredentials:
+	case GrantAuthorizationCode:
+		if opts.Code == "" {
+			return "", gophercloud.ErrMissingInput{Argument: "Code"}
+		}
+		if opts.RedirectURI == "" {
+			return "", gophercloud.ErrMissingInput{Argument: "RedirectURI"}
+		}
+		form.Set("code", opts.Code)
+		form.Set("redirect_uri", opts.RedirectURI)
+		if opts.CodeVerifier != "" {
+			form.Set("code_verifier", opts.CodeVerifier)
+		}
+	case GrantDeviceCode:
+		return opts.deviceAccessToken(ctx, client, form)
+	case "":
+		return "", gophercloud.ErrMissingInput{Argument: "GrantType"}
+	default:
+		return "", fmt.Errorf("unsupported OAuth 2.0 grant type %q", opts.GrantType)
+	}
+
+	tokenEndpoint, _, err := opts.endpoints(ctx, client)
+	if err != nil {
+		return "", err
+	}
+
+	var resp tokenResponse
+	if err := opts.post(ctx, client, tokenEndpoint, form, &resp); err != nil {
+		return "", err
+	}
+	return resp.token(opts.AccessTokenType)
+}
+
+// deviceAccessToken obtains an access token with the device authorization
+// grant: it requests a device code, prompts the user to authorize it, and
+// polls the token endpoint until they do.
+func (opts AuthOptions) deviceAccessToken(ctx context.Context, client *gophercloud.ProviderClient, form url.Values) (string, error) {
+	tokenEndpoint, deviceEndpoint, err := opts.endpoints(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	if deviceEndpoint == "" {
+		return "", gophercloud.ErrMissingInput{Argument: "DeviceAuthorizationEndpoint"}
+	}
+
+	var verifier string
+	authzForm := url.Values{"scope": form["scope"]}
+	if opts.CodeChallengeMethod != "" {
+		var challenge string
+		verifier, challenge, err = pkce(opts.CodeChallengeMethod)
+		if err != nil {
+			return "", err
+		}
+		authzForm.Set("code_challenge", challenge)
+		authzForm.Set("code_challenge_method", opts.CodeChallengeMethod)
+	}
+
+	var authz deviceAuthorizationResponse
+	if err := opts.post(ctx, client, deviceEndpoint, authzForm, &authz); err != nil {
+		return "", err
+	}
+	prompt := opts.DevicePrompt
+	if prompt == nil {
+		prompt = printDeviceAuthorization
+	}
+	prompt(authz.DeviceAuthorization)
+
+	form.Set("device_code", authz.DeviceCode)
+	if verifier != "" {
+		form.Set("code_verifier", verifier)
+	}
+
+	interval := defaultPollInterval
+	if authz.Interval != nil {
+		interval = time.Duration(*authz.Interval) * time.Second
+	}
+	if authz.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(authz.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("the device authorization was not granted: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+
+		var resp tokenResponse
+		err := opts.post(ctx, client, tokenEndpoint, form, &resp)
+		if err == nil {
+			return resp.token(opts.AccessTokenType)
+		}
+
+		// the user has not authorized the request yet
+		if e, ok := err.(ErrIdentityProvider); ok {
+			switch e.Code {
+			case "authorization_pending":
+				continue
+			case "slow_down":
+				interval += defaultPollInterval
+				continue
+			}
+		}
+		return "", err
+	}
+}
+
+// endpoints returns the token endpoint and the device authorization endpoint
+// of the identity provider, from the options or from its discovery document.
+func (opts AuthOptions) endpoints(ctx context.Context, client *gophercloud.ProviderClient) (string, string, error) {
+	tokenEndpoint, deviceEndpoint := opts.AccessTokenEndpoint, opts.DeviceAuthorizationEndpoint
+	needsDevice := opts.GrantType == GrantDeviceCode && deviceEndpoint == ""
+	if tokenEndpoint != "" && !needsDevice {
+		return tokenEndpoint, deviceEndpoint, nil
+	}
+	if opts.DiscoveryEndpoint == "" {
+		if tokenEndpoint == "" {
+			return "", "", gophercloud.ErrMissingInput{Argument: "AccessTokenEndpoint"}
+		}
+		return "", "", gophercloud.ErrMissingInput{Argument: "DeviceAuthorizationEndpoint"}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, opts.DiscoveryEndpoint, nil)
+	if err != nil {
+		return "", "", err
+	}
+	var discovery discoveryDocument
+	if err := do(client, req, &discovery); err != nil {
+		return "", "", err
+	}
+	if tokenEndpoint == "" {
+		tokenEndpoint = discovery.TokenEndpoint
+	}
+	if deviceEndpoint == "" {
+		deviceEndpoint = discovery.DeviceAuthorizationEndpoint
+	}
+	if tokenEndpoint == "" {
+		return "", "", fmt.Errorf("no token endpoint found in the discovery document %s", opts.DiscoveryEndpoint)
+	}
+	return tokenEndpoint, deviceEndpoint, nil
+}
+
+// post sends a form to an endpoint of the identity provider, authenticated
+// with the credentials of the client, and decodes the JSON response into v.
+func (opts AuthOptions) post(ctx context.Context, client *gophercloud.ProviderClient, endpoint string, form url.Values, v any) error {
+	if opts.ClientID == "" {
+		return gophercloud.ErrMissingInput{Argument: "ClientID"}
+	}
+	if opts.ClientSecret == "" {
+		form.Set("client_id", opts.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if opts.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(opts.ClientID), url.QueryEscape(opts.ClientSecret))
+	}
+	return do(client, req, v)
+}
+
+// do sends a request to the identity provider and decodes the JSON response
+// into v. The requests to the identity provider are not sent with
+// ProviderClient.Request, which would add the token of the Identity service
+// to them, but with its HTTP client, so that they share its configuration.
+func do(client *gophercloud.ProviderClient, req *http.Request, v any) error {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", client.UserAgent.Join())
+
+	resp, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		e := ErrIdentityProvider{
+			URL:        req.URL.String(),
+			StatusCode: resp.StatusCode,
+		}
+		_ = json.Unmarshal(body, &e)
+		return e
+	}
+	return json.Unmarshal(body, v)
+}
+
+// pkce returns a PKCE code verifier and its challenge for the given method.
+func pkce(method string) (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	verifier := base64.RawURLEncoding.EncodeToString(b)
+
+	switch method {
+	case "S256":
+		sum := sha256.Sum256([]byte(verifier))
+		return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+	case "plain":
+		return verifier, verifier, nil
+	default:
+		return "", "", fmt.Errorf("unsupported PKCE code challenge method %q", method)
+	}
+}
+
+func printDeviceAuthorization(a DeviceAuthorization) {
+	if a.VerificationURIComplete != "" {
+		fmt.Fprintf(os.Stderr, "To authenticate, visit %s\n", a.VerificationURIComplete)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "To authenticate, visit %s and enter the code %s\n", a.VerificationURI, a.UserCode)
+}
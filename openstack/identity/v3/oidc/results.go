@@ -0,0 +1,82 @@
+package oidc
+
+import "fmt"
+
+// DeviceAuthorization is the response of the identity provider to a device
+// authorization request, which the user completes in a browser.
+type DeviceAuthorization struct {
+	// DeviceCode is the code polled for with the token endpoint.
+	DeviceCode string `json:"device_code"`
+
+	// UserCode is the code the user enters at VerificationURI.
+	UserCode string `json:"user_code"`
+
+	// VerificationURI is the URL at which the user authorizes the request.
+	VerificationURI string `json:"verification_uri"`
+
+	// VerificationURIComplete, if set, is VerificationURI including the
+	// user code.
+	VerificationURIComplete string `json:"verification_uri_complete"`
+
+	// ExpiresIn is the lifetime of the codes, in seconds.
+	ExpiresIn int `json:"expires_in"`
+}
+
+// ErrIdentityProvider is returned when the identity provider rejects a
+// request. Code and Description are the OAuth 2.0 error, if the response
+// has one.
+type ErrIdentityProvider struct {
+	URL         string
+	StatusCode  int
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e ErrIdentityProvider) Error() string {
+	msg := fmt.Sprintf("the identity provider returned %d for %s", e.StatusCode, e.URL)
+	if e.Code != "" {
+		msg += ": " + e.Code
+	}
+	if e.Description != "" {
+		msg += ": " + e.Description
+	}
+	return msg
+}
+
+// deviceAuthorizationResponse is a DeviceAuthorization along with the
+// polling interval, which defaults to 5 seconds when it is not set.
+type deviceAuthorizationResponse struct {
+	DeviceAuthorization
+	Interval *int `json:"interval"`
+}
+
+// discoveryDocument is the part of the OpenID Connect discovery document of
+// an identity provider which is used to authenticate.
+type discoveryDocument struct {
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+}
+
+// tokenResponse is the response of the token endpoint of an identity
+// provider.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+}
+
+// token returns the token of the given type of the response.
+func (r tokenResponse) token(tokenType string) (string, error) {
+	var token string
+	switch tokenType {
+	case "", AccessTokenTypeAccessToken:
+		tokenType, token = AccessTokenTypeAccessToken, r.AccessToken
+	case AccessTokenTypeIDToken:
+		token = r.IDToken
+	default:
+		return "", fmt.Errorf("unsupported access token type %q", tokenType)
+	}
+	if token == "" {
+		return "", fmt.Errorf("the identity provider did not return an %s", tokenType)
+	}
+	return token, nil
+}
@@ -0,0 +1,182 @@
+package testing
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"testing"
+
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+const (
+	// UnscopedTokenID is the token returned by the federation auth endpoint.
+	UnscopedTokenID = "unscoped-token"
+
+	// ScopedTokenID is the token returned by the rescope request.
+	ScopedTokenID = "scoped-token"
+
+	// IdPAccessToken is the access token returned by the identity provider.
+	IdPAccessToken = "idp-access-token"
+
+	// IdPIDToken is the ID token returned by the identity provider.
+	IdPIDToken = "idp-id-token"
+)
+
+// TokenOutput is the body of the token responses of the Identity service.
+const TokenOutput = `
+{
+	"token": {
+		"methods": ["token"],
+		"expires_at": "2030-01-01T00:00:00.000000Z",
+		"issued_at": "2029-12-31T23:00:00.000000Z",
+		"user": {
+			"id": "a6d4b6a9a1d84b9f8bc5e1c3a8ef3f1d",
+			"name": "jdoe",
+			"domain": {"id": "Federated", "name": "Federated"},
+			"OS-FEDERATION": {
+				"identity_provider": {"id": "sso"},
+				"protocol": {"id": "openid"},
+				"groups": []
+			}
+		}
+	}
+}
+`
+
+// RescopeRequest is the expected rescope request of the unscoped token.
+const RescopeRequest = `
+{
+	"auth": {
+		"identity": {
+			"methods": ["token"],
+			"token": {"id": "unscoped-token"}
+		},
+		"scope": {
+			"project": {"id": "3c44b3d1bdb24e1cbe9d6d77ba8d6cbc"}
+		}
+	}
+}
+`
+
+// IdP serves the endpoints of a fake OpenID Connect identity provider and
+// counts the token requests it receives.
+type IdP struct {
+	TokenRequests int
+
+	// PendingPolls is the number of device token requests answered with
+	// authorization_pending.
+	PendingPolls int
+
+	challenge string
+}
+
+// HandleIdP registers the discovery, token and device authorization
+// endpoints of the identity provider. The token endpoint expects the form
+// values in expected, and the credentials of the confidential client if
+// clientSecret is set.
+func (idp *IdP) HandleIdP(t *testing.T, fakeServer th.FakeServer, clientSecret string, expected map[string]string) {
+	fakeServer.Mux.HandleFunc("/idp/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprintf(w, `{
+			"issuer": "%[1]sidp",
+			"token_endpoint": "%[1]sidp/token",
+			"device_authorization_endpoint": "%[1]sidp/device"
+		}`, fakeServer.Endpoint())
+	})
+
+	fakeServer.Mux.HandleFunc("/idp/device", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.AssertNoErr(t, r.ParseForm())
+		th.AssertEquals(t, "openstack", r.PostForm.Get("client_id"))
+		th.AssertEquals(t, "S256", r.PostForm.Get("code_challenge_method"))
+		idp.challenge = r.PostForm.Get("code_challenge")
+
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprintf(w, `{
+			"device_code": "device-code",
+			"user_code": "ABCD-EFGH",
+			"verification_uri": "%sidp/activate",
+			"expires_in": 600,
+			"interval": 0
+		}`, fakeServer.Endpoint())
+	})
+
+	fakeServer.Mux.HandleFunc("/idp/token", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "Content-Type", "application/x-www-form-urlencoded")
+		th.AssertEquals(t, "", r.Header.Get("X-Auth-Token"))
+		idp.TokenRequests++
+
+		user, password, ok := r.BasicAuth()
+		th.AssertEquals(t, clientSecret != "", ok)
+		if ok {
+			th.AssertEquals(t, "openstack", user)
+			th.AssertEquals(t, clientSecret, password)
+		}
+
+		th.AssertNoErr(t, r.ParseForm())
+		for k, v := range expected {
+			th.AssertEquals(t, v, r.PostForm.Get(k))
+		}
+
+		if r.PostForm.Get("grant_type") == "urn:ietf:params:oauth:grant-type:device_code" {
+			sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+			th.AssertEquals(t, idp.challenge, base64.RawURLEncoding.EncodeToString(sum[:]))
+			if idp.PendingPolls > 0 {
+				idp.PendingPolls--
+				w.Header().Add("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error": "authorization_pending"}`)
+				return
+			}
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprintf(w, `{
+			"access_token": "%s",
+			"id_token": "%s",
+			"token_type": "Bearer",
+			"expires_in": 300
+		}`, IdPAccessToken, IdPIDToken)
+	})
+}
+
+// HandleFederatedAuth registers the federation auth endpoint of the Identity
+// service, which expects the given bearer token, and counts its requests.
+func HandleFederatedAuth(t *testing.T, fakeServer th.FakeServer, bearer string, count *int) {
+	fakeServer.Mux.HandleFunc("/v3/OS-FEDERATION/identity_providers/sso/protocols/openid/auth", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "Authorization", "Bearer "+bearer)
+		*count++
+
+		w.Header().Add("X-Subject-Token", UnscopedTokenID)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, TokenOutput)
+	})
+}
+
+// HandleTokens registers the token endpoint of the Identity service, which
+// rescopes the unscoped token to a project, or validates it.
+func HandleTokens(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		switch r.Method {
+		case "POST":
+			th.TestJSONRequest(t, r, RescopeRequest)
+			w.Header().Add("X-Subject-Token", ScopedTokenID)
+			w.WriteHeader(http.StatusCreated)
+		case "GET":
+			th.TestHeader(t, r, "X-Auth-Token", UnscopedTokenID)
+			th.TestHeader(t, r, "X-Subject-Token", UnscopedTokenID)
+			w.Header().Add("X-Subject-Token", UnscopedTokenID)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		fmt.Fprint(w, TokenOutput)
+	})
+}
@@ -0,0 +1,322 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/oidc"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+const projectID = "3c44b3d1bdb24e1cbe9d6d77ba8d6cbc"
+
+func TestAuthenticatePassword(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var idp IdP
+	var federatedAuths int
+	idp.HandleIdP(t, fakeServer, "secret", map[string]string{
+		"grant_type": "password",
+		"scope":      "openid profile",
+		"username":   "jdoe",
+		"password":   "password",
+		"client_id":  "",
+	})
+	HandleFederatedAuth(t, fakeServer, IdPAccessToken, &federatedAuths)
+	HandleTokens(t, fakeServer)
+
+	client, err := openstack.AuthenticatedClient(context.TODO(), gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+		TenantID:         projectID,
+		Federated: &oidc.AuthOptions{
+			IdentityProvider:  "sso",
+			Protocol:          "openid",
+			GrantType:         oidc.GrantPassword,
+			ClientID:          "openstack",
+			ClientSecret:      "secret",
+			DiscoveryEndpoint: fakeServer.Endpoint() + "idp/.well-known/openid-configuration",
+			Username:          "jdoe",
+			Password:          "password",
+		},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, ScopedTokenID, client.TokenID)
+	th.AssertEquals(t, 1, idp.TokenRequests)
+	th.AssertEquals(t, 1, federatedAuths)
+}
+
+func TestAuthenticateClientCredentialsIDToken(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var idp IdP
+	var federatedAuths int
+	idp.HandleIdP(t, fakeServer, "secret", map[string]string{
+		"grant_type": "client_credentials",
+		"scope":      "openid",
+	})
+	HandleFederatedAuth(t, fakeServer, IdPIDToken, &federatedAuths)
+	HandleTokens(t, fakeServer)
+
+	client, err := openstack.AuthenticatedClient(context.TODO(), gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+		TenantID:         projectID,
+		Federated: &oidc.AuthOptions{
+			IdentityProvider:    "sso",
+			Protocol:            "openid",
+			GrantType:           oidc.GrantClientCredentials,
+			ClientID:            "openstack",
+			ClientSecret:        "secret",
+			AccessTokenEndpoint: fakeServer.Endpoint() + "idp/token",
+			OpenIDScope:         "openid",
+			AccessTokenType:     oidc.AccessTokenTypeIDToken,
+		},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, ScopedTokenID, client.TokenID)
+}
+
+func TestAuthenticateAuthorizationCode(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var idp IdP
+	var federatedAuths int
+	idp.HandleIdP(t, fakeServer, "", map[string]string{
+		"grant_type":    "authorization_code",
+		"code":          "auth-code",
+		"redirect_uri":  "http://localhost:8080/callback",
+		"code_verifier": "verifier",
+		"client_id":     "openstack",
+	})
+	HandleFederatedAuth(t, fakeServer, IdPAccessToken, &federatedAuths)
+	HandleTokens(t, fakeServer)
+
+	client, err := openstack.AuthenticatedClient(context.TODO(), gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+		TenantID:         projectID,
+		Federated: &oidc.AuthOptions{
+			IdentityProvider:  "sso",
+			Protocol:          "openid",
+			GrantType:         oidc.GrantAuthorizationCode,
+			ClientID:          "openstack",
+			DiscoveryEndpoint: fakeServer.Endpoint() + "idp/.well-known/openid-configuration",
+			Code:              "auth-code",
+			RedirectURI:       "http://localhost:8080/callback",
+			CodeVerifier:      "verifier",
+		},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, ScopedTokenID, client.TokenID)
+}
+
+func TestAuthenticateDeviceCode(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	idp := IdP{PendingPolls: 2}
+	var federatedAuths int
+	idp.HandleIdP(t, fakeServer, "", map[string]string{
+		"grant_type":  "urn:ietf:params:oauth:grant-type:device_code",
+		"device_code": "device-code",
+		"client_id":   "openstack",
+	})
+	HandleFederatedAuth(t, fakeServer, IdPAccessToken, &federatedAuths)
+	HandleTokens(t, fakeServer)
+
+	var prompted oidc.DeviceAuthorization
+	client, err := openstack.AuthenticatedClient(context.TODO(), gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+		TenantID:         projectID,
+		Federated: &oidc.AuthOptions{
+			IdentityProvider:    "sso",
+			Protocol:            "openid",
+			GrantType:           oidc.GrantDeviceCode,
+			ClientID:            "openstack",
+			DiscoveryEndpoint:   fakeServer.Endpoint() + "idp/.well-known/openid-configuration",
+			CodeChallengeMethod: "S256",
+			DevicePrompt: func(a oidc.DeviceAuthorization) {
+				prompted = a
+			},
+		},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, ScopedTokenID, client.TokenID)
+	th.AssertEquals(t, "ABCD-EFGH", prompted.UserCode)
+	th.AssertEquals(t, fakeServer.Endpoint()+"idp/activate", prompted.VerificationURI)
+	th.AssertEquals(t, 3, idp.TokenRequests)
+}
+
+func TestAuthenticateAccessTokenUnscoped(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var federatedAuths int
+	HandleFederatedAuth(t, fakeServer, "given-token", &federatedAuths)
+	HandleTokens(t, fakeServer)
+
+	client, err := openstack.AuthenticatedClient(context.TODO(), gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+		Federated: &oidc.AuthOptions{
+			IdentityProvider: "sso",
+			Protocol:         "openid",
+			AccessToken:      "given-token",
+		},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, UnscopedTokenID, client.TokenID)
+}
+
+func TestReauthenticate(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var idp IdP
+	var federatedAuths int
+	idp.HandleIdP(t, fakeServer, "secret", map[string]string{
+		"grant_type": "client_credentials",
+	})
+	HandleFederatedAuth(t, fakeServer, IdPAccessToken, &federatedAuths)
+	HandleTokens(t, fakeServer)
+
+	expired := true
+	fakeServer.Mux.HandleFunc("/resource", func(w http.ResponseWriter, r *http.Request) {
+		if expired {
+			expired = false
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		th.TestHeader(t, r, "X-Auth-Token", ScopedTokenID)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client, err := openstack.AuthenticatedClient(context.TODO(), gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+		TenantID:         projectID,
+		AllowReauth:      true,
+		Federated: &oidc.AuthOptions{
+			IdentityProvider:    "sso",
+			Protocol:            "openid",
+			GrantType:           oidc.GrantClientCredentials,
+			ClientID:            "openstack",
+			ClientSecret:        "secret",
+			AccessTokenEndpoint: fakeServer.Endpoint() + "idp/token",
+		},
+	})
+	th.AssertNoErr(t, err)
+
+	_, err = client.Request(context.TODO(), "GET", fakeServer.Endpoint()+"resource", &gophercloud.RequestOpts{
+		OkCodes: []int{http.StatusNoContent},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, idp.TokenRequests)
+	th.AssertEquals(t, 2, federatedAuths)
+}
+
+func TestReauthenticateSingleUseGrant(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var idp IdP
+	var federatedAuths int
+	idp.HandleIdP(t, fakeServer, "", map[string]string{
+		"grant_type": "authorization_code",
+		"code":       "auth-code",
+	})
+	HandleFederatedAuth(t, fakeServer, IdPAccessToken, &federatedAuths)
+	HandleTokens(t, fakeServer)
+
+	fakeServer.Mux.HandleFunc("/resource", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	scope := &gophercloud.AuthScope{ProjectID: projectID}
+	client, err := openstack.AuthenticatedClient(context.TODO(), gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+		Scope:            scope,
+		AllowReauth:      true,
+		Federated: &oidc.AuthOptions{
+			IdentityProvider:    "sso",
+			Protocol:            "openid",
+			GrantType:           oidc.GrantAuthorizationCode,
+			ClientID:            "openstack",
+			AccessTokenEndpoint: fakeServer.Endpoint() + "idp/token",
+			Code:                "auth-code",
+			RedirectURI:         "http://localhost:8080/callback",
+		},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, &gophercloud.AuthScope{ProjectID: projectID}, scope)
+
+	// the authorization code is not sent again
+	_, err = client.Request(context.TODO(), "GET", fakeServer.Endpoint()+"resource", &gophercloud.RequestOpts{
+		OkCodes: []int{http.StatusNoContent},
+	})
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusUnauthorized))
+	th.AssertEquals(t, 1, idp.TokenRequests)
+	th.AssertEquals(t, 1, federatedAuths)
+}
+
+func TestCanReauth(t *testing.T) {
+	for grant, expected := range map[oidc.GrantType]bool{
+		oidc.GrantPassword:          true,
+		oidc.GrantClientCredentials: true,
+		oidc.GrantAuthorizationCode: false,
+		oidc.GrantDeviceCode:        false,
+	} {
+		opts := gophercloud.AuthOptions{
+			AllowReauth: true,
+			Federated:   oidc.AuthOptions{GrantType: grant},
+		}
+		th.AssertEquals(t, expected, opts.CanReauth())
+	}
+
+	opts := gophercloud.AuthOptions{
+		AllowReauth: true,
+		Federated:   oidc.AuthOptions{GrantType: oidc.GrantDeviceCode, AccessToken: "given-token"},
+	}
+	th.AssertEquals(t, true, opts.CanReauth())
+}
+
+func TestIdentityProviderError(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	fakeServer.Mux.HandleFunc("/idp/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error": "invalid_client", "error_description": "Invalid client credentials"}`))
+	})
+
+	_, err := openstack.AuthenticatedClient(context.TODO(), gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+		Federated: &oidc.AuthOptions{
+			IdentityProvider:    "sso",
+			Protocol:            "openid",
+			GrantType:           oidc.GrantClientCredentials,
+			ClientID:            "openstack",
+			ClientSecret:        "wrong",
+			AccessTokenEndpoint: fakeServer.Endpoint() + "idp/token",
+		},
+	})
+	var idpErr oidc.ErrIdentityProvider
+	th.AssertEquals(t, true, errors.As(err, &idpErr))
+	th.AssertEquals(t, http.StatusUnauthorized, idpErr.StatusCode)
+	th.AssertEquals(t, "invalid_client", idpErr.Code)
+
+	_, err = openstack.AuthenticatedClient(context.TODO(), gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+		Federated: &oidc.AuthOptions{
+			IdentityProvider: "sso",
+			Protocol:         "openid",
+			GrantType:        oidc.GrantPassword,
+			ClientID:         "openstack",
+		},
+	})
+	th.AssertEquals(t, true, errors.As(err, new(gophercloud.ErrMissingInput)))
+}
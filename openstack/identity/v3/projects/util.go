@@ -0,0 +1,37 @@
+package projects
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/internal/find"
+)
+
+// IDFromName returns the ID of the project with the given name. It returns a
+// gophercloud.ErrResourceNotFound error if there is none, and a
+// gophercloud.ErrMultipleResourcesFound error if there are several.
+func IDFromName(ctx context.Context, client *gophercloud.ServiceClient, name string) (string, error) {
+	found, err := findByName(ctx, client, name)
+	if err != nil {
+		return "", err
+	}
+	return found.ID, nil
+}
+
+// Find returns the project with the given ID or, if there is none, the project
+// with the given name. It returns a gophercloud.ErrResourceNotFound error if
+// there is none, and a gophercloud.ErrMultipleResourcesFound error if several
+// projects have the given name.
+func Find(ctx context.Context, client *gophercloud.ServiceClient, nameOrID string) (*Project, error) {
+	return find.ByNameOrID(ctx, nameOrID, func(ctx context.Context, id string) (*Project, error) {
+		return Get(ctx, client, id).Extract()
+	}, func(ctx context.Context, name string) (*Project, error) {
+		return findByName(ctx, client, name)
+	})
+}
+
+// findByName returns the project with the given name.
+func findByName(ctx context.Context, client *gophercloud.ServiceClient, name string) (*Project, error) {
+	pager := List(client, ListOpts{Name: name})
+	return find.ByName(ctx, pager, ExtractProjects, func(v Project) string { return v.Name }, "project", name)
+}
@@ -0,0 +1,38 @@
+/*
+Package saml2 enables authenticating with the Identity service through a SAML2
+identity provider with a username and a password, using the Enhanced Client or
+Proxy (ECP) profile, like the v3samlpassword plugin of keystoneauth.
+
+The options request a SAML2 authentication request from the OS-FEDERATION auth
+endpoint of the identity provider and protocol, which is protected by a SAML2
+service provider such as mod_shib or mod_auth_mellon. They forward it to the
+ECP endpoint of the identity provider with the credentials of the user, post
+the resulting assertion back to the service provider, and exchange the SAML2
+session for an unscoped token. The unscoped token is then rescoped to the
+project or domain of the gophercloud.AuthOptions they are set in.
+
+Example to Authenticate with a Username and a Password (v3samlpassword)
+
+	opts := gophercloud.AuthOptions{
+		IdentityEndpoint: "https://keystone.example.com:5000/v3",
+		TenantName:       "demo",
+		DomainName:       "Default",
+		AllowReauth:      true,
+		Federated: &saml2.AuthOptions{
+			IdentityProvider:    "acme",
+			Protocol:            "saml2",
+			IdentityProviderURL: "https://idp.acme.example.com/idp/profile/SAML2/SOAP/ECP",
+			Username:            "jdoe",
+			Password:            "password",
+		},
+	}
+
+	provider, err := openstack.AuthenticatedClient(context.TODO(), opts)
+	if err != nil {
+		panic(err)
+	}
+
+When AllowReauth is set, the whole exchange is performed again once the token
+expires.
+*/
+package saml2
@@ -0,0 +1,116 @@
+package saml2
+
+import (
+	"bytes"
+	"context"
+	"mime"
+	"net/http"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/internal/ecp"
+)
+
+// paosHeader advertises the support of the ECP profile to the service
+// provider.
+const paosHeader = `ver="urn:liberty:paos:2003-08";"urn:oasis:names:tc:SAML:2.0:profiles:SSO:ecp"`
+
+// AuthOptions represents options for authenticating with a SAML2 identity
+// provider of the Identity service with a username and a password, through
+// the Enhanced Client or Proxy (ECP) profile, like the v3samlpassword plugin
+// of keystoneauth. It satisfies the gophercloud.FederatedAuth interface, to
+// be set as the Federated field of a gophercloud.AuthOptions.
+type AuthOptions struct {
+	// IdentityProvider is the ID of the identity provider in the Identity
+	// service.
+	IdentityProvider string `required:"true"`
+
+	// Protocol is the ID of the federation protocol of the identity provider
+	// in the Identity service, usually "saml2".
+	Protocol string `required:"true"`
+
+	// IdentityProviderURL is the URL of the SAML2 ECP endpoint of the
+	// identity provider, usually ending with /idp/profile/SAML2/SOAP/ECP.
+	IdentityProviderURL string `required:"true"`
+
+	// Username and Password are the credentials of the user at the identity
+	// provider, sent with HTTP basic authentication.
+	Username string `required:"true"`
+	Password string `required:"true"`
+}
+
+// FederatedToken performs the ECP exchange: it requests a SAML2
+// authentication request from the OS-FEDERATION auth endpoint of the Identity
+// service, forwards it to the identity provider with the credentials of the
+// user, posts the resulting assertion back to the service provider and
+// returns the unscoped token issued by the Identity service. It satisfies the
+// gophercloud.FederatedAuth interface.
+func (opts AuthOptions) FederatedToken(ctx context.Context, client *gophercloud.ServiceClient) (string, error) {
+	if opts.IdentityProvider == "" {
+		return "", gophercloud.ErrMissingInput{Argument: "IdentityProvider"}
+	}
+	if opts.Protocol == "" {
+		return "", gophercloud.ErrMissingInput{Argument: "Protocol"}
+	}
+	if opts.IdentityProviderURL == "" {
+		return "", gophercloud.ErrMissingInput{Argument: "IdentityProviderURL"}
+	}
+	if opts.Username == "" {
+		return "", gophercloud.ErrMissingInput{Argument: "Username"}
+	}
+	if opts.Password == "" {
+		return "", gophercloud.ErrMissingInput{Argument: "Password"}
+	}
+
+	session, err := ecp.NewSession(client.ProviderClient)
+	if err != nil {
+		return "", err
+	}
+
+	authURL := client.ServiceURL("OS-FEDERATION", "identity_providers", opts.IdentityProvider, "protocols", opts.Protocol, "auth")
+
+	// The service provider intercepts the request to the federated auth
+	// endpoint and responds with a SAML2 authentication request.
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, authURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "text/html, "+ecp.ContentType)
+	req.Header.Set("PAOS", paosHeader)
+	resp, body, err := session.Do(req, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	if ct, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); ct != ecp.ContentType {
+		return "", ErrInvalidResponse{URL: authURL, Reason: "the service provider did not respond with a PAOS request"}
+	}
+	authnRequest, consumerURL, relayState, err := parseAuthnRequest(authURL, body)
+	if err != nil {
+		return "", err
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, opts.IdentityProviderURL, bytes.NewReader(authnRequest))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+	req.SetBasicAuth(opts.Username, opts.Password)
+	_, body, err = session.Do(req, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	authnResponse, err := parseAuthnResponse(opts.IdentityProviderURL, body, consumerURL, relayState)
+	if err != nil {
+		return "", err
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, consumerURL, bytes.NewReader(authnResponse))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", ecp.ContentType)
+	if _, _, err := session.Do(req, http.StatusOK, http.StatusFound, http.StatusSeeOther); err != nil {
+		return "", err
+	}
+
+	return session.Token(ctx, authURL)
+}
@@ -0,0 +1,154 @@
+package saml2
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+const (
+	soapNamespace = "http://schemas.xmlsoap.org/soap/envelope/"
+	paosNamespace = "urn:liberty:paos:2003-08"
+	ecpNamespace  = "urn:oasis:names:tc:SAML:2.0:profiles:SSO:ecp"
+
+	// soapActorNext is the SOAP actor of the ECP header blocks.
+	soapActorNext = "http://schemas.xmlsoap.org/soap/actor/next"
+)
+
+// ErrInvalidResponse is returned when a response of the service provider or
+// of the identity provider is not a valid message of the ECP profile.
+type ErrInvalidResponse struct {
+	URL    string
+	Reason string
+}
+
+func (e ErrInvalidResponse) Error() string {
+	return fmt.Sprintf("invalid SAML2 ECP response from %s: %s", e.URL, e.Reason)
+}
+
+// ErrConsumerMismatch is returned when the assertion consumer service URLs of
+// the service provider and of the identity provider differ, in which case
+// the assertion is not sent to the service provider.
+type ErrConsumerMismatch struct {
+	ServiceProvider  string
+	IdentityProvider string
+}
+
+func (e ErrConsumerMismatch) Error() string {
+	return fmt.Sprintf("the consumer URLs of the service provider %s and of the identity provider %s are not equal", e.ServiceProvider, e.IdentityProvider)
+}
+
+// parseAuthnRequest parses the PAOS request of the service provider, and
+// returns the SAML2 authentication request to send to the identity provider,
+// which is the request without its SOAP header, the URL of the assertion
+// consumer service of the service provider, and its relay state.
+func parseAuthnRequest(url string, body []byte) ([]byte, string, string, error) {
+	header, err := findElement(body, xml.Name{Space: soapNamespace, Local: "Header"})
+	if err != nil {
+		return nil, "", "", ErrInvalidResponse{URL: url, Reason: err.Error()}
+	}
+	request, err := findElement(body, xml.Name{Space: paosNamespace, Local: "Request"})
+	if err != nil {
+		return nil, "", "", ErrInvalidResponse{URL: url, Reason: err.Error()}
+	}
+	relayState, err := findElement(body, xml.Name{Space: ecpNamespace, Local: "RelayState"})
+	if err != nil {
+		return nil, "", "", ErrInvalidResponse{URL: url, Reason: err.Error()}
+	}
+	consumerURL := request.attr("responseConsumerURL")
+	if consumerURL == "" {
+		return nil, "", "", ErrInvalidResponse{URL: url, Reason: "no responseConsumerURL in the PAOS request"}
+	}
+
+	return splice(body, header, nil), consumerURL, relayState.text, nil
+}
+
+// parseAuthnResponse parses the ECP response of the identity provider, checks
+// that it is addressed to the assertion consumer service of the service
+// provider, and returns the message to send to the latter, in which the
+// header of the identity provider is replaced with the relay state of the
+// service provider.
+func parseAuthnResponse(url string, body []byte, consumerURL, relayState string) ([]byte, error) {
+	response, err := findElement(body, xml.Name{Space: ecpNamespace, Local: "Response"})
+	if err != nil {
+		return nil, ErrInvalidResponse{URL: url, Reason: err.Error()}
+	}
+	if acsURL := response.attr("AssertionConsumerServiceURL"); acsURL != consumerURL {
+		return nil, ErrConsumerMismatch{ServiceProvider: consumerURL, IdentityProvider: acsURL}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<ecp:RelayState xmlns:ecp="%s" xmlns:S="%s" S:mustUnderstand="1" S:actor="%s">`, ecpNamespace, soapNamespace, soapActorNext)
+	if err := xml.EscapeText(&b, []byte(relayState)); err != nil {
+		return nil, err
+	}
+	b.WriteString(`</ecp:RelayState>`)
+
+	return splice(body, response, b.Bytes()), nil
+}
+
+// element is an XML element of a SOAP message, with its byte offsets in the
+// message so that the message can be edited without serializing it again,
+// which would invalidate the signatures it contains.
+type element struct {
+	start, end int64
+	attrs      []xml.Attr
+	text       string
+}
+
+func (e *element) attr(name string) string {
+	for _, a := range e.attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// findElement returns the first element of doc with the given name.
+func findElement(doc []byte, name xml.Name) (*element, error) {
+	d := xml.NewDecoder(bytes.NewReader(doc))
+	var e *element
+	var depth int
+	for {
+		offset := d.InputOffset()
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no %s element", name.Local)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if e != nil {
+				depth++
+			} else if t.Name == name {
+				e = &element{start: offset, attrs: t.Attr}
+			}
+		case xml.EndElement:
+			if e == nil {
+				continue
+			}
+			if depth == 0 {
+				e.end = d.InputOffset()
+				return e, nil
+			}
+			depth--
+		case xml.CharData:
+			if e != nil && depth == 0 {
+				e.text += string(t)
+			}
+		}
+	}
+}
+
+// splice returns a copy of doc in which e is replaced with replacement.
+func splice(doc []byte, e *element, replacement []byte) []byte {
+	b := make([]byte, 0, len(doc)-int(e.end-e.start)+len(replacement))
+	b = append(b, doc[:e.start]...)
+	b = append(b, replacement...)
+	return append(b, doc[e.end:]...)
+}
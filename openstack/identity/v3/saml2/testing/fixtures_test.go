@@ -0,0 +1,166 @@
+package testing
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+const (
+	// UnscopedTokenID is the token returned by the federated auth endpoint.
+	UnscopedTokenID = "unscoped-token"
+
+	// ScopedTokenID is the token returned by the rescope request.
+	ScopedTokenID = "scoped-token"
+
+	// SessionCookie is the SAML2 session cookie of the service provider.
+	SessionCookie = "_shibsession_keystone"
+
+	// RelayState is the relay state of the service provider.
+	RelayState = "ss:mem:6f1f20fee34a8d9c"
+)
+
+// AuthnRequestBody is the SOAP body of the PAOS request of the service
+// provider, which is forwarded to the identity provider.
+const AuthnRequestBody = `<S:Body><samlp:AuthnRequest xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" AssertionConsumerServiceURL="%[1]sShibboleth.sso/SAML2/ECP" ID="_a07186e3992e70e92c17b9d249495643" ProtocolBinding="urn:oasis:names:tc:SAML:2.0:bindings:PAOS" Version="2.0"><saml:Issuer xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion">https://keystone.example.com/shibboleth</saml:Issuer></samlp:AuthnRequest></S:Body>`
+
+// AuthnRequest is the PAOS request of the service provider.
+const AuthnRequest = `<?xml version="1.0" encoding="UTF-8"?>
+<S:Envelope xmlns:S="http://schemas.xmlsoap.org/soap/envelope/"><S:Header><paos:Request xmlns:paos="urn:liberty:paos:2003-08" S:actor="http://schemas.xmlsoap.org/soap/actor/next" S:mustUnderstand="1" responseConsumerURL="%[1]sShibboleth.sso/SAML2/ECP" service="urn:oasis:names:tc:SAML:2.0:profiles:SSO:ecp"/><ecp:Request xmlns:ecp="urn:oasis:names:tc:SAML:2.0:profiles:SSO:ecp" IsPassive="0" S:actor="http://schemas.xmlsoap.org/soap/actor/next" S:mustUnderstand="1"><saml:Issuer xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion">https://keystone.example.com/shibboleth</saml:Issuer></ecp:Request><ecp:RelayState xmlns:ecp="urn:oasis:names:tc:SAML:2.0:profiles:SSO:ecp" S:actor="http://schemas.xmlsoap.org/soap/actor/next" S:mustUnderstand="1">ss:mem:6f1f20fee34a8d9c</ecp:RelayState></S:Header>` + AuthnRequestBody + `</S:Envelope>`
+
+// ExpectedIdPRequest is the request sent to the identity provider: the PAOS
+// request without its header.
+const ExpectedIdPRequest = `<?xml version="1.0" encoding="UTF-8"?>
+<S:Envelope xmlns:S="http://schemas.xmlsoap.org/soap/envelope/">` + AuthnRequestBody + `</S:Envelope>`
+
+// AuthnResponseBody is the SOAP body of the response of the identity
+// provider, which is forwarded to the service provider as is.
+const AuthnResponseBody = `<soap11:Body><saml2p:Response xmlns:saml2p="urn:oasis:names:tc:SAML:2.0:protocol" Destination="%[1]sShibboleth.sso/SAML2/ECP" ID="_d2e1c6d4b1a7e1c2f0a6" InResponseTo="_a07186e3992e70e92c17b9d249495643" Version="2.0"><saml2:Assertion xmlns:saml2="urn:oasis:names:tc:SAML:2.0:assertion" ID="_5c1a2b"><ds:Signature xmlns:ds="http://www.w3.org/2000/09/xmldsig#"><ds:SignatureValue>c2lnbmF0dXJl</ds:SignatureValue></ds:Signature><saml2:Subject><saml2:NameID>jdoe</saml2:NameID></saml2:Subject></saml2:Assertion></saml2p:Response></soap11:Body>`
+
+// AuthnResponse is the response of the identity provider.
+const AuthnResponse = `<?xml version="1.0" encoding="UTF-8"?><soap11:Envelope xmlns:soap11="http://schemas.xmlsoap.org/soap/envelope/"><soap11:Header><ecp:Response xmlns:ecp="urn:oasis:names:tc:SAML:2.0:profiles:SSO:ecp" AssertionConsumerServiceURL="%[2]s" soap11:actor="http://schemas.xmlsoap.org/soap/actor/next" soap11:mustUnderstand="1"/></soap11:Header>` + AuthnResponseBody + `</soap11:Envelope>`
+
+// ExpectedSPResponse is the response sent to the service provider: the
+// response of the identity provider with the relay state of the service
+// provider as header.
+const ExpectedSPResponse = `<?xml version="1.0" encoding="UTF-8"?><soap11:Envelope xmlns:soap11="http://schemas.xmlsoap.org/soap/envelope/"><soap11:Header><ecp:RelayState xmlns:ecp="urn:oasis:names:tc:SAML:2.0:profiles:SSO:ecp" xmlns:S="http://schemas.xmlsoap.org/soap/envelope/" S:mustUnderstand="1" S:actor="http://schemas.xmlsoap.org/soap/actor/next">ss:mem:6f1f20fee34a8d9c</ecp:RelayState></soap11:Header>` + AuthnResponseBody + `</soap11:Envelope>`
+
+// TokenOutput is the body of the token responses of the Identity service.
+const TokenOutput = `
+{
+	"token": {
+		"methods": ["saml2"],
+		"expires_at": "2030-01-01T00:00:00.000000Z",
+		"catalog": []
+	}
+}
+`
+
+// RescopeRequest is the expected rescope request of the unscoped token.
+const RescopeRequest = `
+{
+	"auth": {
+		"identity": {
+			"methods": ["token"],
+			"token": {"id": "unscoped-token"}
+		},
+		"scope": {
+			"project": {"id": "3c44b3d1bdb24e1cbe9d6d77ba8d6cbc"}
+		}
+	}
+}
+`
+
+// IdP is a stand-in SAML2 identity provider, which authenticates jdoe and
+// responds with an assertion for the given assertion consumer service.
+type IdP struct {
+	ConsumerURL string
+	Requests    int
+}
+
+// HandleIdP registers the ECP endpoint of the identity provider.
+func (idp *IdP) HandleIdP(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/idp/profile/SAML2/SOAP/ECP", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.AssertEquals(t, "", r.Header.Get("X-Auth-Token"))
+		idp.Requests++
+
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "jdoe" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		th.AssertNoErr(t, err)
+		th.AssertEquals(t, fmt.Sprintf(ExpectedIdPRequest, fakeServer.Endpoint()), string(body))
+
+		w.Header().Add("Content-Type", "text/xml")
+		fmt.Fprintf(w, AuthnResponse, fakeServer.Endpoint(), idp.ConsumerURL)
+	})
+}
+
+// ServiceProvider is the service provider, which counts the requests of its
+// assertion consumer service.
+type ServiceProvider struct {
+	Assertions int
+
+	// OmitToken, if set, makes the federated auth endpoint respond without
+	// an X-Subject-Token.
+	OmitToken bool
+}
+
+// HandleServiceProvider registers the federated auth endpoint of the Identity
+// service, protected by the service provider, and the assertion consumer
+// service of the service provider.
+func (sp *ServiceProvider) HandleServiceProvider(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/v3/OS-FEDERATION/identity_providers/acme/protocols/saml2/auth", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.AssertEquals(t, "", r.Header.Get("X-Auth-Token"))
+
+		if _, err := r.Cookie(SessionCookie); err != nil {
+			th.TestHeader(t, r, "PAOS", `ver="urn:liberty:paos:2003-08";"urn:oasis:names:tc:SAML:2.0:profiles:SSO:ecp"`)
+			th.TestHeader(t, r, "Accept", "text/html, application/vnd.paos+xml")
+
+			w.Header().Add("Content-Type", "application/vnd.paos+xml")
+			fmt.Fprintf(w, AuthnRequest, fakeServer.Endpoint())
+			return
+		}
+
+		if !sp.OmitToken {
+			w.Header().Add("X-Subject-Token", UnscopedTokenID)
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, TokenOutput)
+	})
+
+	fakeServer.Mux.HandleFunc("/Shibboleth.sso/SAML2/ECP", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "Content-Type", "application/vnd.paos+xml")
+		body, err := io.ReadAll(r.Body)
+		th.AssertNoErr(t, err)
+		th.AssertEquals(t, fmt.Sprintf(ExpectedSPResponse, fakeServer.Endpoint()), string(body))
+		sp.Assertions++
+
+		http.SetCookie(w, &http.Cookie{Name: SessionCookie, Value: "session", Path: "/"})
+		http.Redirect(w, r, fakeServer.Endpoint()+"v3/OS-FEDERATION/identity_providers/acme/protocols/saml2/auth", http.StatusFound)
+	})
+}
+
+// HandleTokens registers the token endpoint of the Identity service, which
+// rescopes the unscoped token to a project.
+func HandleTokens(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestJSONRequest(t, r, RescopeRequest)
+
+		w.Header().Add("X-Subject-Token", ScopedTokenID)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, TokenOutput)
+	})
+}
@@ -0,0 +1,203 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack"
+	"github.com/gophercloud/gophercloud/v2/openstack/config"
+	"github.com/gophercloud/gophercloud/v2/openstack/config/clouds"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/saml2"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+const projectID = "3c44b3d1bdb24e1cbe9d6d77ba8d6cbc"
+
+func TestAuthenticate(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	idp := IdP{ConsumerURL: fakeServer.Endpoint() + "Shibboleth.sso/SAML2/ECP"}
+	var sp ServiceProvider
+	idp.HandleIdP(t, fakeServer)
+	sp.HandleServiceProvider(t, fakeServer)
+	HandleTokens(t, fakeServer)
+
+	client, err := openstack.AuthenticatedClient(context.TODO(), gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+		TenantID:         projectID,
+		Federated: &saml2.AuthOptions{
+			IdentityProvider:    "acme",
+			Protocol:            "saml2",
+			IdentityProviderURL: fakeServer.Endpoint() + "idp/profile/SAML2/SOAP/ECP",
+			Username:            "jdoe",
+			Password:            "secret",
+		},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, ScopedTokenID, client.TokenID)
+	th.AssertEquals(t, 1, idp.Requests)
+	th.AssertEquals(t, 1, sp.Assertions)
+}
+
+func TestNewProviderClientFromCloudsYAML(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	idp := IdP{ConsumerURL: fakeServer.Endpoint() + "Shibboleth.sso/SAML2/ECP"}
+	var sp ServiceProvider
+	idp.HandleIdP(t, fakeServer)
+	sp.HandleServiceProvider(t, fakeServer)
+	HandleTokens(t, fakeServer)
+
+	expired := true
+	fakeServer.Mux.HandleFunc("/resource", func(w http.ResponseWriter, r *http.Request) {
+		if expired {
+			expired = false
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		th.TestHeader(t, r, "X-Auth-Token", ScopedTokenID)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	cloudsYAML := fmt.Sprintf(`clouds:
+  gophercloud-test:
+    auth_type: v3samlpassword
+    auth:
+      auth_url: %[1]sv3/
+      identity_provider: acme
+      protocol: saml2
+      identity_provider_url: %[1]sidp/profile/SAML2/SOAP/ECP
+      username: jdoe
+      password: secret
+      project_id: %[2]s`, fakeServer.Endpoint(), projectID)
+
+	ao, _, _, err := clouds.Parse(
+		clouds.WithCloudsYAML(strings.NewReader(cloudsYAML)),
+		clouds.WithCloudName("gophercloud-test"),
+	)
+	th.AssertNoErr(t, err)
+	ao.AllowReauth = true
+
+	client, err := config.NewProviderClient(context.TODO(), ao)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, ScopedTokenID, client.TokenID)
+
+	_, err = client.Request(context.TODO(), "GET", fakeServer.Endpoint()+"resource", &gophercloud.RequestOpts{
+		OkCodes: []int{http.StatusNoContent},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, idp.Requests)
+	th.AssertEquals(t, 2, sp.Assertions)
+}
+
+func TestConsumerMismatch(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	idp := IdP{ConsumerURL: "https://evil.example.com/Shibboleth.sso/SAML2/ECP"}
+	var sp ServiceProvider
+	idp.HandleIdP(t, fakeServer)
+	sp.HandleServiceProvider(t, fakeServer)
+
+	_, err := openstack.AuthenticatedClient(context.TODO(), gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+		Federated: &saml2.AuthOptions{
+			IdentityProvider:    "acme",
+			Protocol:            "saml2",
+			IdentityProviderURL: fakeServer.Endpoint() + "idp/profile/SAML2/SOAP/ECP",
+			Username:            "jdoe",
+			Password:            "secret",
+		},
+	})
+	var mismatchErr saml2.ErrConsumerMismatch
+	th.AssertEquals(t, true, errors.As(err, &mismatchErr))
+	th.AssertEquals(t, fakeServer.Endpoint()+"Shibboleth.sso/SAML2/ECP", mismatchErr.ServiceProvider)
+	th.AssertEquals(t, "https://evil.example.com/Shibboleth.sso/SAML2/ECP", mismatchErr.IdentityProvider)
+	th.AssertEquals(t, 0, sp.Assertions)
+}
+
+func TestIdentityProviderError(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	idp := IdP{ConsumerURL: fakeServer.Endpoint() + "Shibboleth.sso/SAML2/ECP"}
+	var sp ServiceProvider
+	idp.HandleIdP(t, fakeServer)
+	sp.HandleServiceProvider(t, fakeServer)
+
+	opts := gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+		Federated: &saml2.AuthOptions{
+			IdentityProvider:    "acme",
+			Protocol:            "saml2",
+			IdentityProviderURL: fakeServer.Endpoint() + "idp/profile/SAML2/SOAP/ECP",
+			Username:            "jdoe",
+			Password:            "wrong",
+		},
+	}
+	_, err := openstack.AuthenticatedClient(context.TODO(), opts)
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusUnauthorized))
+	th.AssertEquals(t, 0, sp.Assertions)
+
+	opts.Federated = &saml2.AuthOptions{
+		IdentityProvider: "acme",
+		Protocol:         "saml2",
+		Username:         "jdoe",
+		Password:         "secret",
+	}
+	_, err = openstack.AuthenticatedClient(context.TODO(), opts)
+	th.AssertEquals(t, true, errors.As(err, new(gophercloud.ErrMissingInput)))
+}
+
+func TestMissingSubjectToken(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	idp := IdP{ConsumerURL: fakeServer.Endpoint() + "Shibboleth.sso/SAML2/ECP"}
+	sp := ServiceProvider{OmitToken: true}
+	idp.HandleIdP(t, fakeServer)
+	sp.HandleServiceProvider(t, fakeServer)
+
+	_, err := openstack.AuthenticatedClient(context.TODO(), gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+		Federated: &saml2.AuthOptions{
+			IdentityProvider:    "acme",
+			Protocol:            "saml2",
+			IdentityProviderURL: fakeServer.Endpoint() + "idp/profile/SAML2/SOAP/ECP",
+			Username:            "jdoe",
+			Password:            "secret",
+		},
+	})
+	th.AssertErr(t, err)
+	th.AssertEquals(t, true, strings.Contains(err.Error(), "X-Subject-Token"))
+	th.AssertEquals(t, 1, sp.Assertions)
+}
+
+func TestServiceProviderWithoutECP(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	fakeServer.Mux.HandleFunc("/v3/OS-FEDERATION/identity_providers/acme/protocols/saml2/auth", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "text/html")
+		fmt.Fprint(w, "<html></html>")
+	})
+
+	_, err := openstack.AuthenticatedClient(context.TODO(), gophercloud.AuthOptions{
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+		Federated: &saml2.AuthOptions{
+			IdentityProvider:    "acme",
+			Protocol:            "saml2",
+			IdentityProviderURL: fakeServer.Endpoint() + "idp/profile/SAML2/SOAP/ECP",
+			Username:            "jdoe",
+			Password:            "secret",
+		},
+	})
+	th.AssertEquals(t, true, errors.As(err, new(saml2.ErrInvalidResponse)))
+}
@@ -104,6 +104,30 @@ Example to Create a Token from a Username and Password with Project Name Scope
 		panic(err)
 	}
 
+Example to Complete Multi-Factor Authentication with a TOTP Passcode
+
+	authOptions := tokens.AuthOptions{
+		UserID:   "username",
+		Password: "password",
+	}
+
+	token, err := tokens.Create(context.TODO(), identityClient, &authOptions).ExtractToken()
+	var receiptErr tokens.ErrReceiptRequired
+	if errors.As(err, &receiptErr) {
+		fmt.Printf("Missing authentication methods: %v\n", receiptErr.MissingMethods())
+
+		authOptions = tokens.AuthOptions{
+			UserID:   "username",
+			Passcode: "123456",
+			Receipt:  receiptErr.Receipt,
+		}
+
+		token, err = tokens.Create(context.TODO(), identityClient, &authOptions).ExtractToken()
+	}
+	if err != nil {
+		panic(err)
+	}
+
 Example to Get a Token
 
 	token, err := tokens.Get(context.TODO(), identityClient, "token_id", nil).ExtractToken()
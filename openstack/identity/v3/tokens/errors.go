@@ -0,0 +1,105 @@
+package tokens
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// ErrReceiptRequired is returned by Create when the credentials are valid, but
+// the multi-factor authentication rules of the user require additional
+// authentication methods. To continue the authentication, call Create again
+// with Receipt and the credentials of the missing methods, for example a TOTP
+// Passcode.
+type ErrReceiptRequired struct {
+	gophercloud.ErrUnexpectedResponseCode
+
+	// Receipt is the ID of the auth receipt.
+	Receipt string
+
+	// Methods are the authentication methods which succeeded.
+	Methods []string
+
+	// RequiredMethods are the sets of authentication methods which satisfy
+	// the rules of the user. All the methods of one of the sets are required
+	// to obtain a token.
+	RequiredMethods [][]string
+
+	// ExpiresAt is the time at which the receipt expires.
+	ExpiresAt time.Time
+
+	// User is the user being authenticated.
+	User User
+}
+
+func (e ErrReceiptRequired) Error() string {
+	missing := e.MissingMethods()
+	rules := make([]string, 0, len(missing))
+	for _, methods := range missing {
+		rules = append(rules, "["+strings.Join(methods, ", ")+"]")
+	}
+	return fmt.Sprintf("Additional authentication methods are required: one of %s", strings.Join(rules, ", "))
+}
+
+// Unwrap returns the underlying ErrUnexpectedResponseCode, so that the error
+// still reports a 401 response code.
+func (e ErrReceiptRequired) Unwrap() error {
+	return e.ErrUnexpectedResponseCode
+}
+
+// MissingMethods returns, for each set of RequiredMethods, the methods which
+// have not succeeded yet.
+func (e ErrReceiptRequired) MissingMethods() [][]string {
+	missing := make([][]string, 0, len(e.RequiredMethods))
+	for _, rule := range e.RequiredMethods {
+		var methods []string
+		for _, method := range rule {
+			if !slices.Contains(e.Methods, method) {
+				methods = append(methods, method)
+			}
+		}
+		missing = append(missing, methods)
+	}
+	return missing
+}
+
+// receiptRequired converts the 401 response carrying an auth receipt of a
+// Create request into an ErrReceiptRequired. Other errors are returned as is.
+func receiptRequired(err error) error {
+	var codeErr gophercloud.ErrUnexpectedResponseCode
+	if !errors.As(err, &codeErr) || codeErr.Actual != http.StatusUnauthorized {
+		return err
+	}
+
+	receipt := codeErr.ResponseHeader.Get(authReceiptHeader)
+	if receipt == "" {
+		return err
+	}
+
+	var s struct {
+		Receipt struct {
+			Methods   []string  `json:"methods"`
+			ExpiresAt time.Time `json:"expires_at"`
+			User      User      `json:"user"`
+		} `json:"receipt"`
+		RequiredMethods [][]string `json:"required_auth_methods"`
+	}
+	if json.Unmarshal(codeErr.Body, &s) != nil {
+		return err
+	}
+
+	return ErrReceiptRequired{
+		ErrUnexpectedResponseCode: codeErr,
+		Receipt:                   receipt,
+		Methods:                   s.Receipt.Methods,
+		RequiredMethods:           s.RequiredMethods,
+		ExpiresAt:                 s.Receipt.ExpiresAt,
+		User:                      s.Receipt.User,
+	}
+}
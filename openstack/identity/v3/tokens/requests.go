@@ -7,7 +7,10 @@ import (
 	"github.com/gophercloud/gophercloud/v2"
 )
 
-const xSubjectTokenHeader = "X-Subject-Token"
+const (
+	xSubjectTokenHeader = "X-Subject-Token"
+	authReceiptHeader   = "Openstack-Auth-Receipt"
+)
 
 // Scope allows a created token to be limited to a specific domain or project.
 type Scope struct {
@@ -49,6 +52,11 @@ type AuthOptions struct {
 	// Passcode is used in TOTP authentication method
 	Passcode string `json:"passcode,omitempty"`
 
+	// Receipt is the ID of the auth receipt of an ErrReceiptRequired. Set it
+	// along with the credentials of the missing methods, typically Passcode,
+	// to continue the authentication.
+	Receipt string `json:"-"`
+
 	// At most one of DomainID and DomainName must be provided if using Username
 	// with Identity V3. Otherwise, either are optional.
 	DomainID   string `json:"-"`
@@ -112,17 +120,26 @@ func (opts *AuthOptions) CanReauth() bool {
 		return false
 	}
 
+	if opts.Receipt != "" {
+		// cannot reauth using an auth receipt, which expires
+		return false
+	}
+
 	return opts.AllowReauth
 }
 
 // ToTokenV3HeadersMap allows AuthOptions to satisfy the AuthOptionsBuilder
 // interface in the v3 tokens package.
 func (opts *AuthOptions) ToTokenV3HeadersMap(map[string]any) (map[string]string, error) {
-	return nil, nil
+	if opts.Receipt == "" {
+		return nil, nil
+	}
+	return map[string]string{authReceiptHeader: opts.Receipt}, nil
 }
 
 // Create authenticates and either generates a new token, or changes the Scope
-// of an existing token.
+// of an existing token. If the user must complete additional authentication
+// methods, the error is an ErrReceiptRequired.
 func Create(ctx context.Context, c *gophercloud.ServiceClient, opts AuthOptionsBuilder) (r CreateResult) {
 	scope, err := opts.ToTokenV3ScopeMap()
 	if err != nil {
@@ -136,10 +153,21 @@ func Create(ctx context.Context, c *gophercloud.ServiceClient, opts AuthOptionsB
 		return
 	}
 
+	h, err := opts.ToTokenV3HeadersMap(map[string]any{
+		"method": "POST",
+		"url":    tokenURL(c),
+	})
+	if err != nil {
+		r.Err = err
+		return
+	}
+
 	resp, err := c.Post(ctx, tokenURL(c), b, &r.Body, &gophercloud.RequestOpts{
+		MoreHeaders: h,
 		OmitHeaders: []string{"X-Auth-Token"},
 	})
 	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	r.Err = receiptRequired(r.Err)
 	return
 }
 
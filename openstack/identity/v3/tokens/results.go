@@ -1,6 +1,8 @@
 package tokens
 
 import (
+	"encoding/json"
+	"net/http"
 	"time"
 
 	"github.com/gophercloud/gophercloud/v2"
@@ -115,6 +117,18 @@ type ApplicationCredential struct {
 	AccessRules []AccessRule `json:"access_rules"`
 }
 
+// ServiceProvider represents a Keystone to Keystone federation service
+// provider included in a token, to whose cloud the token can be exchanged.
+type ServiceProvider struct {
+	// The ID of the service provider.
+	ID string `json:"id"`
+	// The URL at which the Identity service of the service provider issues
+	// unscoped tokens for SAML2 assertions.
+	AuthURL string `json:"auth_url"`
+	// The URL of the ECP endpoint of the service provider.
+	SPURL string `json:"sp_url"`
+}
+
 // commonResult is the response from a request. A commonResult has various
 // methods which can be used to extract different details about the result.
 type commonResult struct {
@@ -155,6 +169,17 @@ func (r GetResult) ExtractTokenID() (string, error) {
 	return r.Header.Get("X-Subject-Token"), r.Err
 }
 
+// ExtractExpiresAt implements the gophercloud.ExpiringAuthResult interface.
+// The returned time is the same as the ExpiresAt field of the Token struct
+// returned from ExtractToken().
+func (r commonResult) ExtractExpiresAt() (time.Time, error) {
+	t, err := r.ExtractToken()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.ExpiresAt, nil
+}
+
 // ExtractServiceCatalog returns the ServiceCatalog that was generated along
 // with the user's Token.
 func (r commonResult) ExtractServiceCatalog() (*ServiceCatalog, error) {
@@ -219,6 +244,16 @@ func (r commonResult) ExtractApplicationCredential() (*ApplicationCredential, er
 	return s.ApplicationCredential, err
 }
 
+// ExtractServiceProviders returns the Keystone to Keystone federation service
+// providers to whose clouds the token can be exchanged.
+func (r commonResult) ExtractServiceProviders() ([]ServiceProvider, error) {
+	var s struct {
+		ServiceProviders []ServiceProvider `json:"service_providers"`
+	}
+	err := r.ExtractInto(&s)
+	return s.ServiceProviders, err
+}
+
 // CreateResult is the response from a Create request. Use ExtractToken()
 // to interpret it as a Token, or ExtractServiceCatalog() to interpret it
 // as a service catalog.
@@ -226,6 +261,39 @@ type CreateResult struct {
 	commonResult
 }
 
+// ExtractCachedToken returns the token, its expiry and the response body in
+// a form which can be stored in a gophercloud.TokenCache.
+func (r CreateResult) ExtractCachedToken() (*gophercloud.CachedToken, error) {
+	tokenID, err := r.ExtractTokenID()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt, err := r.ExtractExpiresAt()
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &gophercloud.CachedToken{
+		TokenID:   tokenID,
+		ExpiresAt: expiresAt,
+		Body:      body,
+	}, nil
+}
+
+// CreateResultFromCachedToken rebuilds the CreateResult of the request which
+// issued a token stored in a gophercloud.TokenCache.
+func CreateResultFromCachedToken(t *gophercloud.CachedToken) CreateResult {
+	var r CreateResult
+	r.Body = t.Body
+	r.StatusCode = http.StatusCreated
+	r.Header = http.Header{}
+	r.Header.Set("X-Subject-Token", t.TokenID)
+	return r
+}
+
 // GetResult is the response from a Get request. Use ExtractToken()
 // to interpret it as a Token, or ExtractServiceCatalog() to interpret it
 // as a service catalog.
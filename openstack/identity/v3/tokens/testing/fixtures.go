@@ -28,6 +28,13 @@ const TokenOutput = `
          }
       ],
       "expires_at":"2017-06-03T02:19:49.000000Z",
+      "service_providers":[
+         {
+            "auth_url":"https://beta.example.com:5000/v3/OS-FEDERATION/identity_providers/acme/protocols/saml2/auth",
+            "id":"beta",
+            "sp_url":"https://beta.example.com:5000/Shibboleth.sso/SAML2/ECP"
+         }
+      ],
       "project":{
          "domain":{
             "id":"default",
@@ -298,6 +305,16 @@ var ExpectedProject = tokens.Project{
 	Name:   "admin",
 }
 
+// ExpectedServiceProviders contains expected service providers extracted from
+// token response.
+var ExpectedServiceProviders = []tokens.ServiceProvider{
+	{
+		ID:      "beta",
+		AuthURL: "https://beta.example.com:5000/v3/OS-FEDERATION/identity_providers/acme/protocols/saml2/auth",
+		SPURL:   "https://beta.example.com:5000/Shibboleth.sso/SAML2/ECP",
+	},
+}
+
 // ExpectedDomain contains expected domain extracted from token response.
 var ExpectedDomain = tokens.Domain{
 	ID:   "default",
@@ -393,3 +410,48 @@ func getGetApplicationCredentialResult(t *testing.T) tokens.GetResult {
 	th.AssertNoErr(t, err)
 	return result
 }
+
+// ReceiptID is the ID of the auth receipt of ReceiptOutput.
+const ReceiptID = "gAAAAABbPXDbmX4KqE3wDC1fYONUd9Ulm6QXd9Ymu7SWVEr4JbN0dYz2VUvm"
+
+// PasswordRequest is the password authentication request answered with
+// ReceiptOutput.
+const PasswordRequest = `
+{
+	"auth": {
+		"identity": {
+			"methods": ["password"],
+			"password": {
+				"user": {
+					"id": "ee4dfb6e5540447cb3741905149d9b6e",
+					"password": "secret"
+				}
+			}
+		}
+	}
+}
+`
+
+// ReceiptOutput is a sample response to a Token call of a user whose
+// multi-factor authentication rules require additional methods.
+const ReceiptOutput = `
+{
+	"receipt": {
+		"methods": ["password"],
+		"expires_at": "2018-07-05T08:39:02.000000Z",
+		"issued_at": "2018-07-05T08:34:02.000000Z",
+		"user": {
+			"domain": {
+				"id": "default",
+				"name": "Default"
+			},
+			"id": "ee4dfb6e5540447cb3741905149d9b6e",
+			"name": "admin"
+		}
+	},
+	"required_auth_methods": [
+		["password", "totp"],
+		["password", "custom-auth"]
+	]
+}
+`
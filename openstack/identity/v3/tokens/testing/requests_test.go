@@ -2,6 +2,7 @@ package testing
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
@@ -539,6 +540,132 @@ func TestCreateExtractsTokenFromResponse(t *testing.T) {
 	}
 }
 
+func TestCreateReceiptRequired(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	client := gophercloud.ServiceClient{
+		ProviderClient: &gophercloud.ProviderClient{},
+		Endpoint:       fakeServer.Endpoint(),
+	}
+
+	fakeServer.Mux.HandleFunc("/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.AssertEquals(t, "", r.Header.Get("Openstack-Auth-Receipt"))
+		th.TestJSONRequest(t, r, PasswordRequest)
+
+		w.Header().Add("Openstack-Auth-Receipt", ReceiptID)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, ReceiptOutput)
+	})
+
+	options := tokens.AuthOptions{UserID: "ee4dfb6e5540447cb3741905149d9b6e", Password: "secret"}
+	_, err := tokens.Create(context.TODO(), &client, &options).Extract()
+
+	var receiptErr tokens.ErrReceiptRequired
+	th.AssertEquals(t, true, errors.As(err, &receiptErr))
+	th.CheckEquals(t, ReceiptID, receiptErr.Receipt)
+	th.CheckDeepEquals(t, []string{"password"}, receiptErr.Methods)
+	th.CheckDeepEquals(t, [][]string{{"password", "totp"}, {"password", "custom-auth"}}, receiptErr.RequiredMethods)
+	th.CheckDeepEquals(t, [][]string{{"totp"}, {"custom-auth"}}, receiptErr.MissingMethods())
+	th.CheckEquals(t, time.Date(2018, 7, 5, 8, 39, 2, 0, time.UTC), receiptErr.ExpiresAt)
+	th.CheckEquals(t, "ee4dfb6e5540447cb3741905149d9b6e", receiptErr.User.ID)
+	th.CheckEquals(t, "Additional authentication methods are required: one of [totp], [custom-auth]", receiptErr.Error())
+	th.CheckEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusUnauthorized))
+}
+
+func TestCreateWithReceipt(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	client := gophercloud.ServiceClient{
+		ProviderClient: &gophercloud.ProviderClient{},
+		Endpoint:       fakeServer.Endpoint(),
+	}
+
+	fakeServer.Mux.HandleFunc("/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "Openstack-Auth-Receipt", ReceiptID)
+		th.TestJSONRequest(t, r, `
+			{
+				"auth": {
+					"identity": {
+						"methods": ["totp"],
+						"totp": {
+							"user": {
+								"id": "ee4dfb6e5540447cb3741905149d9b6e",
+								"passcode": "123456"
+							}
+						}
+					}
+				}
+			}
+		`)
+
+		w.Header().Add("X-Subject-Token", "aaa111")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{
+			"token": {
+				"methods": ["password", "totp"],
+				"expires_at": "2014-10-02T13:45:00.000000Z"
+			}
+		}`)
+	})
+
+	options := tokens.AuthOptions{
+		UserID:   "ee4dfb6e5540447cb3741905149d9b6e",
+		Passcode: "123456",
+		Receipt:  ReceiptID,
+	}
+	th.AssertEquals(t, false, options.CanReauth())
+
+	tokenID, err := tokens.Create(context.TODO(), &client, &options).ExtractTokenID()
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "aaa111", tokenID)
+}
+
+func TestCreateUnauthorizedWithoutReceipt(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	client := gophercloud.ServiceClient{
+		ProviderClient: &gophercloud.ProviderClient{},
+		Endpoint:       fakeServer.Endpoint(),
+	}
+
+	fakeServer.Mux.HandleFunc("/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	options := tokens.AuthOptions{UserID: "me", Password: "wrong"}
+	_, err := tokens.Create(context.TODO(), &client, &options).Extract()
+	th.CheckEquals(t, false, errors.As(err, new(tokens.ErrReceiptRequired)))
+	th.CheckEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusUnauthorized))
+}
+
+func TestCreateReceiptWithInvalidBody(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	client := gophercloud.ServiceClient{
+		ProviderClient: &gophercloud.ProviderClient{},
+		Endpoint:       fakeServer.Endpoint(),
+	}
+
+	fakeServer.Mux.HandleFunc("/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Openstack-Auth-Receipt", ReceiptID)
+		w.Header().Add("Content-Type", "text/html")
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, "<html>Unauthorized</html>")
+	})
+
+	options := tokens.AuthOptions{UserID: "me", Password: "secret"}
+	_, err := tokens.Create(context.TODO(), &client, &options).Extract()
+	th.CheckEquals(t, false, errors.As(err, new(tokens.ErrReceiptRequired)))
+	th.CheckEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusUnauthorized))
+}
+
 func TestCreateFailureEmptyAuth(t *testing.T) {
 	authTokenPostErr(t, tokens.AuthOptions{}, nil, false, gophercloud.ErrMissingPassword{})
 }
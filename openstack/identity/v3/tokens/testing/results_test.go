@@ -15,6 +15,15 @@ func TestExtractToken(t *testing.T) {
 	th.CheckDeepEquals(t, &ExpectedToken, token)
 }
 
+func TestExtractExpiresAt(t *testing.T) {
+	result := getGetResult(t)
+
+	expiresAt, err := result.ExtractExpiresAt()
+	th.AssertNoErr(t, err)
+
+	th.CheckEquals(t, ExpectedToken.ExpiresAt, expiresAt)
+}
+
 func TestExtractCatalog(t *testing.T) {
 	result := getGetResult(t)
 
@@ -79,3 +88,12 @@ func TestExtractApplicationCredentialNotPresent(t *testing.T) {
 		t.Errorf("Expected nil application credential, got %v", appCred)
 	}
 }
+
+func TestExtractServiceProviders(t *testing.T) {
+	result := getGetResult(t)
+
+	sps, err := result.ExtractServiceProviders()
+	th.AssertNoErr(t, err)
+
+	th.CheckDeepEquals(t, ExpectedServiceProviders, sps)
+}
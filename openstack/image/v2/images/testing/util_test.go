@@ -0,0 +1,155 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/image/v2/images"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/testhelper/client"
+)
+
+func TestWaitForStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		statuses []string
+		status   string
+		failed   string
+	}{
+		{"active", []string{"queued", "saving", "active"}, "active", ""},
+		{"killed", []string{"saving", "killed"}, "active", "killed"},
+		{"deleted", []string{"queued", "deleted"}, "active", "deleted"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeServer := th.SetupHTTP()
+			defer fakeServer.Teardown()
+
+			var polls int
+			fakeServer.Mux.HandleFunc("/images/da3b75d9-3f4a-40e7-8a2c-bfab23927dea", func(w http.ResponseWriter, r *http.Request) {
+				th.TestMethod(t, r, "GET")
+				th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+				w.Header().Add("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"id": "da3b75d9-3f4a-40e7-8a2c-bfab23927dea", "status": "%s"}`, tt.statuses[polls])
+				polls++
+			})
+
+			ctx := gophercloud.WithPoller(context.TODO(), &gophercloud.Poller{InitialInterval: time.Millisecond})
+			err := images.WaitForStatus(ctx, client.ServiceClient(fakeServer), "da3b75d9-3f4a-40e7-8a2c-bfab23927dea", images.ImageStatus(tt.status))
+			th.AssertEquals(t, len(tt.statuses), polls)
+			if tt.failed == "" {
+				th.AssertNoErr(t, err)
+				return
+			}
+			var failed gophercloud.ErrResourceFailed
+			th.AssertEquals(t, true, errors.As(err, &failed))
+			th.AssertEquals(t, tt.failed, failed.Status)
+		})
+	}
+}
+
+func TestWaitForImport(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	bodies := []string{
+		`{"id": "da3b75d9-3f4a-40e7-8a2c-bfab23927dea", "status": "importing", "os_glance_importing_to_stores": "fast"}`,
+		`{"id": "da3b75d9-3f4a-40e7-8a2c-bfab23927dea", "status": "active"}`,
+	}
+	fakeServer.Mux.HandleFunc("/images/da3b75d9-3f4a-40e7-8a2c-bfab23927dea", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, bodies[0])
+		bodies = bodies[1:]
+	})
+
+	ctx := gophercloud.WithPoller(context.TODO(), &gophercloud.Poller{InitialInterval: time.Millisecond})
+	err := images.WaitForImport(ctx, client.ServiceClient(fakeServer), "da3b75d9-3f4a-40e7-8a2c-bfab23927dea")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 0, len(bodies))
+}
+
+func TestWaitForImportFailed(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	fakeServer.Mux.HandleFunc("/images/da3b75d9-3f4a-40e7-8a2c-bfab23927dea", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": "da3b75d9-3f4a-40e7-8a2c-bfab23927dea", "status": "queued", "os_glance_importing_to_stores": "", "os_glance_failed_import": "fast,cheap"}`)
+	})
+
+	err := images.WaitForImport(context.TODO(), client.ServiceClient(fakeServer), "da3b75d9-3f4a-40e7-8a2c-bfab23927dea")
+	var failed gophercloud.ErrResourceFailed
+	th.AssertEquals(t, true, errors.As(err, &failed))
+	th.AssertEquals(t, "queued", failed.Status)
+	th.AssertEquals(t, "import failed for stores: fast,cheap", failed.Fault)
+}
+
+// handleImagesByName serves the images list filtered by name, and answers 404
+// to a Get of any image but cirros.
+func handleImagesByName(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/images", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+
+		w.Header().Add("Content-Type", "application/json")
+		switch r.URL.Query().Get("name") {
+		case "cirros":
+			fmt.Fprint(w, `{"images": [{"id": "07aa21a9-fa1a-430e-9a33-185be5982431", "name": "cirros"}]}`)
+		case "ubuntu":
+			fmt.Fprint(w, `{"images": [{"id": "1bea47ed-f6a9-463b-b423-14b9cca9ad27", "name": "ubuntu"}, {"id": "b6d8b5a3-4c9e-4a2c-9d0b-9a0f6c1f2f54", "name": "ubuntu"}]}`)
+		default:
+			fmt.Fprint(w, `{"images": []}`)
+		}
+	})
+	fakeServer.Mux.HandleFunc("/images/", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		if r.URL.Path != "/images/07aa21a9-fa1a-430e-9a33-185be5982431" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": "07aa21a9-fa1a-430e-9a33-185be5982431", "name": "cirros"}`)
+	})
+}
+
+func TestIDFromName(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	handleImagesByName(t, fakeServer)
+
+	id, err := images.IDFromName(context.TODO(), client.ServiceClient(fakeServer), "cirros")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "07aa21a9-fa1a-430e-9a33-185be5982431", id)
+
+	_, err = images.IDFromName(context.TODO(), client.ServiceClient(fakeServer), "fedora")
+	var notFound gophercloud.ErrResourceNotFound
+	th.AssertEquals(t, true, errors.As(err, &notFound))
+	th.AssertEquals(t, "image", notFound.ResourceType)
+
+	_, err = images.IDFromName(context.TODO(), client.ServiceClient(fakeServer), "ubuntu")
+	var multiple gophercloud.ErrMultipleResourcesFound
+	th.AssertEquals(t, true, errors.As(err, &multiple))
+	th.AssertEquals(t, 2, multiple.Count)
+}
+
+func TestFind(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	handleImagesByName(t, fakeServer)
+
+	image, err := images.Find(context.TODO(), client.ServiceClient(fakeServer), "07aa21a9-fa1a-430e-9a33-185be5982431")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "cirros", image.Name)
+
+	image, err = images.Find(context.TODO(), client.ServiceClient(fakeServer), "cirros")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "07aa21a9-fa1a-430e-9a33-185be5982431", image.ID)
+}
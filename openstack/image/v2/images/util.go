@@ -0,0 +1,99 @@
+package images
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/internal/find"
+)
+
+// FailureStatuses are the statuses of an image whose upload was aborted
+// (killed) or which was deleted.
+var FailureStatuses = []ImageStatus{ImageStatusKilled, ImageStatusDeleted}
+
+// WaitForStatus polls an image until it reaches the given status, and returns
+// a gophercloud.ErrResourceFailed error if the image is killed or deleted
+// meanwhile.
+func WaitForStatus(ctx context.Context, c *gophercloud.ServiceClient, id string, status ImageStatus) error {
+	return gophercloud.PollerFromContext(ctx).Poll(ctx, func(ctx context.Context) (bool, string, error) {
+		current, err := Get(ctx, c, id).Extract()
+		if err != nil {
+			return false, "", err
+		}
+		return checkStatus(current, status)
+	})
+}
+
+// WaitForImport polls an image until it is active after an import. It returns
+// a gophercloud.ErrResourceFailed error listing the stores for which the
+// import failed, if any.
+func WaitForImport(ctx context.Context, c *gophercloud.ServiceClient, id string) error {
+	return gophercloud.PollerFromContext(ctx).Poll(ctx, func(ctx context.Context) (bool, string, error) {
+		current, err := Get(ctx, c, id).Extract()
+		if err != nil {
+			return false, "", err
+		}
+
+		// While an import is in progress, the stores it still targets are
+		// listed in os_glance_importing_to_stores. The import failed if
+		// none is left and some stores are listed in
+		// os_glance_failed_import.
+		importing, _ := current.Properties["os_glance_importing_to_stores"].(string)
+		failed, _ := current.Properties["os_glance_failed_import"].(string)
+		if current.Status != ImageStatusActive && current.Status != ImageStatusImporting && importing == "" && failed != "" {
+			return false, string(current.Status), gophercloud.ErrResourceFailed{
+				ID:     id,
+				Status: string(current.Status),
+				Fault:  fmt.Sprintf("import failed for stores: %s", failed),
+			}
+		}
+
+		return checkStatus(current, ImageStatusActive)
+	})
+}
+
+// checkStatus reports whether the image has the given status, or returns an
+// error if it reached one of the FailureStatuses.
+func checkStatus(image *Image, status ImageStatus) (bool, string, error) {
+	if image.Status == status {
+		return true, string(image.Status), nil
+	}
+	if slices.Contains(FailureStatuses, image.Status) {
+		return false, string(image.Status), gophercloud.ErrResourceFailed{
+			ID:     image.ID,
+			Status: string(image.Status),
+		}
+	}
+	return false, string(image.Status), nil
+}
+
+// IDFromName returns the ID of the image with the given name. It returns a
+// gophercloud.ErrResourceNotFound error if there is none, and a
+// gophercloud.ErrMultipleResourcesFound error if there are several.
+func IDFromName(ctx context.Context, client *gophercloud.ServiceClient, name string) (string, error) {
+	found, err := findByName(ctx, client, name)
+	if err != nil {
+		return "", err
+	}
+	return found.ID, nil
+}
+
+// Find returns the image with the given ID or, if there is none, the image
+// with the given name. It returns a gophercloud.ErrResourceNotFound error if
+// there is none, and a gophercloud.ErrMultipleResourcesFound error if several
+// images have the given name.
+func Find(ctx context.Context, client *gophercloud.ServiceClient, nameOrID string) (*Image, error) {
+	return find.ByNameOrID(ctx, nameOrID, func(ctx context.Context, id string) (*Image, error) {
+		return Get(ctx, client, id).Extract()
+	}, func(ctx context.Context, name string) (*Image, error) {
+		return findByName(ctx, client, name)
+	})
+}
+
+// findByName returns the image with the given name.
+func findByName(ctx context.Context, client *gophercloud.ServiceClient, name string) (*Image, error) {
+	pager := List(client, ListOpts{Name: name})
+	return find.ByName(ctx, pager, ExtractImages, func(v Image) string { return v.Name }, "image", name)
+}
@@ -0,0 +1,24 @@
+package listeners
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// FailureStatuses are the provisioning statuses in which Octavia leaves a
+// listener when an operation on it failed.
+var FailureStatuses = []string{"ERROR"}
+
+// WaitForStatus polls a listener until its provisioning status is the given
+// one, and returns a gophercloud.ErrResourceFailed error if it goes to ERROR
+// instead.
+func WaitForStatus(ctx context.Context, c *gophercloud.ServiceClient, id, status string) error {
+	return gophercloud.WaitForStatus(ctx, id, status, FailureStatuses, func(ctx context.Context) (gophercloud.ResourceStatus, error) {
+		current, err := Get(ctx, c, id).Extract()
+		if err != nil {
+			return gophercloud.ResourceStatus{}, err
+		}
+		return gophercloud.ResourceStatus{Status: current.ProvisioningStatus}, nil
+	})
+}
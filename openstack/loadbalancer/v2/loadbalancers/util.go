@@ -0,0 +1,55 @@
+package loadbalancers
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/internal/find"
+)
+
+// FailureStatuses are the provisioning statuses in which Octavia leaves a load
+// balancer when an operation on it failed.
+var FailureStatuses = []string{"ERROR"}
+
+// WaitForStatus polls a load balancer until its provisioning status is the
+// given one, typically ACTIVE after a create or an update. It returns a
+// gophercloud.ErrResourceFailed error if the load balancer goes to ERROR
+// instead.
+func WaitForStatus(ctx context.Context, c *gophercloud.ServiceClient, id, status string) error {
+	return gophercloud.WaitForStatus(ctx, id, status, FailureStatuses, func(ctx context.Context) (gophercloud.ResourceStatus, error) {
+		current, err := Get(ctx, c, id).Extract()
+		if err != nil {
+			return gophercloud.ResourceStatus{}, err
+		}
+		return gophercloud.ResourceStatus{Status: current.ProvisioningStatus}, nil
+	})
+}
+
+// IDFromName returns the ID of the load balancer with the given name. It returns a
+// gophercloud.ErrResourceNotFound error if there is none, and a
+// gophercloud.ErrMultipleResourcesFound error if there are several.
+func IDFromName(ctx context.Context, client *gophercloud.ServiceClient, name string) (string, error) {
+	found, err := findByName(ctx, client, name)
+	if err != nil {
+		return "", err
+	}
+	return found.ID, nil
+}
+
+// Find returns the load balancer with the given ID or, if there is none, the load balancer
+// with the given name. It returns a gophercloud.ErrResourceNotFound error if
+// there is none, and a gophercloud.ErrMultipleResourcesFound error if several
+// load balancers have the given name.
+func Find(ctx context.Context, client *gophercloud.ServiceClient, nameOrID string) (*LoadBalancer, error) {
+	return find.ByNameOrID(ctx, nameOrID, func(ctx context.Context, id string) (*LoadBalancer, error) {
+		return Get(ctx, client, id).Extract()
+	}, func(ctx context.Context, name string) (*LoadBalancer, error) {
+		return findByName(ctx, client, name)
+	})
+}
+
+// findByName returns the load balancer with the given name.
+func findByName(ctx context.Context, client *gophercloud.ServiceClient, name string) (*LoadBalancer, error) {
+	pager := List(client, ListOpts{Name: name})
+	return find.ByName(ctx, pager, ExtractLoadBalancers, func(v LoadBalancer) string { return v.Name }, "load balancer", name)
+}
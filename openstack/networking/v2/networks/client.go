@@ -0,0 +1,62 @@
+package networks
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// Client performs the basic network operations. Application code can depend
+// on it rather than on a ServiceClient, so that it can be tested with the
+// in-memory implementation of the networks/fake package instead of an HTTP
+// fixture.
+type Client interface {
+	// Get returns the network with the given ID.
+	Get(ctx context.Context, id string) (*Network, error)
+
+	// List returns all the networks matching opts, following the pagination.
+	List(ctx context.Context, opts ListOptsBuilder) ([]Network, error)
+
+	// Create creates a network.
+	Create(ctx context.Context, opts CreateOptsBuilder) (*Network, error)
+
+	// Update changes the attributes of the network with the given ID.
+	Update(ctx context.Context, id string, opts UpdateOptsBuilder) (*Network, error)
+
+	// Delete deletes the network with the given ID.
+	Delete(ctx context.Context, id string) error
+}
+
+// NewClient returns a Client which performs the network operations with the
+// functions of this package and the given networking client.
+func NewClient(client *gophercloud.ServiceClient) Client {
+	return serviceClient{client: client}
+}
+
+type serviceClient struct {
+	client *gophercloud.ServiceClient
+}
+
+func (c serviceClient) Get(ctx context.Context, id string) (*Network, error) {
+	return Get(ctx, c.client, id).Extract()
+}
+
+func (c serviceClient) List(ctx context.Context, opts ListOptsBuilder) ([]Network, error) {
+	pages, err := List(c.client, opts).AllPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ExtractNetworks(pages)
+}
+
+func (c serviceClient) Create(ctx context.Context, opts CreateOptsBuilder) (*Network, error) {
+	return Create(ctx, c.client, opts).Extract()
+}
+
+func (c serviceClient) Update(ctx context.Context, id string, opts UpdateOptsBuilder) (*Network, error) {
+	return Update(ctx, c.client, id, opts).Extract()
+}
+
+func (c serviceClient) Delete(ctx context.Context, id string) error {
+	return Delete(ctx, c.client, id).ExtractErr()
+}
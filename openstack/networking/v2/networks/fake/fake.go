@@ -0,0 +1,149 @@
+/*
+Package fake provides an in-memory implementation of networks.Client, for the
+tests of code which depends on that interface.
+
+Networks are created in the ACTIVE status, and missing networks are reported
+with the same 404 error as the Networking API:
+
+	client := fake.New(networks.Network{ID: "existing", Name: "public"})
+	network, err := client.Create(ctx, networks.CreateOpts{Name: "private"})
+
+	_, err = client.Get(ctx, "missing")
+	gophercloud.ResponseCodeIs(err, http.StatusNotFound) // true
+*/
+package fake
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2/internal/fakestore"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/networks"
+)
+
+// Client is an in-memory networks.Client. It is safe for concurrent use.
+type Client struct {
+	store *fakestore.Store[networks.Network]
+}
+
+var _ networks.Client = (*Client)(nil)
+
+// New returns a Client holding the given networks.
+func New(initial ...networks.Network) *Client {
+	c := &Client{store: fakestore.New("networks", clone)}
+	for _, n := range initial {
+		c.store.Put(n.ID, n)
+	}
+	return c
+}
+
+// clone copies the slices of a network.
+func clone(n networks.Network) networks.Network {
+	n.Subnets = slices.Clone(n.Subnets)
+	n.AvailabilityZoneHints = slices.Clone(n.AvailabilityZoneHints)
+	n.Tags = slices.Clone(n.Tags)
+	return n
+}
+
+// Get implements networks.Client.
+func (c *Client) Get(_ context.Context, id string) (*networks.Network, error) {
+	return c.store.Get(id)
+}
+
+// List implements networks.Client. When opts is a networks.ListOpts, the
+// networks are filtered by ID, Name, Status, ProjectID and Shared.
+func (c *Client) List(_ context.Context, opts networks.ListOptsBuilder) ([]networks.Network, error) {
+	listOpts, _ := opts.(networks.ListOpts)
+	return c.store.List(func(n networks.Network) bool {
+		return (listOpts.ID == "" || listOpts.ID == n.ID) &&
+			(listOpts.Name == "" || listOpts.Name == n.Name) &&
+			(listOpts.Status == "" || listOpts.Status == n.Status) &&
+			(listOpts.ProjectID == "" || listOpts.ProjectID == n.ProjectID) &&
+			(listOpts.Shared == nil || *listOpts.Shared == n.Shared)
+	}), nil
+}
+
+// Create implements networks.Client.
+func (c *Client) Create(_ context.Context, opts networks.CreateOptsBuilder) (*networks.Network, error) {
+	b, err := opts.ToNetworkCreateMap()
+	if err != nil {
+		return nil, err
+	}
+	var n struct {
+		Name                  string   `json:"name"`
+		Description           string   `json:"description"`
+		AdminStateUp          *bool    `json:"admin_state_up"`
+		Shared                bool     `json:"shared"`
+		TenantID              string   `json:"tenant_id"`
+		ProjectID             string   `json:"project_id"`
+		AvailabilityZoneHints []string `json:"availability_zone_hints"`
+	}
+	if err := fakestore.Decode(b, "network", &n); err != nil {
+		return nil, err
+	}
+
+	projectID := n.ProjectID
+	if projectID == "" {
+		projectID = n.TenantID
+	}
+	now := time.Now().UTC()
+	network := networks.Network{
+		ID:                    fakestore.NewID(),
+		Name:                  n.Name,
+		Description:           n.Description,
+		AdminStateUp:          n.AdminStateUp == nil || *n.AdminStateUp,
+		Status:                "ACTIVE",
+		Subnets:               []string{},
+		TenantID:              projectID,
+		ProjectID:             projectID,
+		Shared:                n.Shared,
+		AvailabilityZoneHints: n.AvailabilityZoneHints,
+		Tags:                  []string{},
+		RevisionNumber:        1,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+	c.store.Put(network.ID, network)
+	return &network, nil
+}
+
+// Update implements networks.Client.
+func (c *Client) Update(_ context.Context, id string, opts networks.UpdateOptsBuilder) (*networks.Network, error) {
+	b, err := opts.ToNetworkUpdateMap()
+	if err != nil {
+		return nil, err
+	}
+	var u struct {
+		Name         *string `json:"name"`
+		Description  *string `json:"description"`
+		AdminStateUp *bool   `json:"admin_state_up"`
+		Shared       *bool   `json:"shared"`
+	}
+	if err := fakestore.Decode(b, "network", &u); err != nil {
+		return nil, err
+	}
+
+	return c.store.Update(id, func(n *networks.Network) error {
+		if u.Name != nil {
+			n.Name = *u.Name
+		}
+		if u.Description != nil {
+			n.Description = *u.Description
+		}
+		if u.AdminStateUp != nil {
+			n.AdminStateUp = *u.AdminStateUp
+		}
+		if u.Shared != nil {
+			n.Shared = *u.Shared
+		}
+		n.RevisionNumber++
+		n.UpdatedAt = time.Now().UTC()
+		return nil
+	})
+}
+
+// Delete implements networks.Client.
+func (c *Client) Delete(_ context.Context, id string) error {
+	return c.store.Delete(id)
+}
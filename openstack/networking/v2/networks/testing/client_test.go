@@ -0,0 +1,78 @@
+package testing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/networks"
+	networksfake "github.com/gophercloud/gophercloud/v2/openstack/networking/v2/networks/fake"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+func TestFakeClient(t *testing.T) {
+	var c networks.Client = networksfake.New(Network1)
+
+	created, err := c.Create(context.TODO(), networks.CreateOpts{
+		Name:                  "private",
+		ProjectID:             "26a7980765d0414dbc1fc1f88cdb7e6e",
+		AvailabilityZoneHints: []string{"nova"},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "ACTIVE", created.Status)
+	th.AssertEquals(t, true, created.AdminStateUp)
+	th.AssertEquals(t, "26a7980765d0414dbc1fc1f88cdb7e6e", created.TenantID)
+	th.CheckDeepEquals(t, []string{"nova"}, created.AvailabilityZoneHints)
+
+	actual, err := c.Get(context.TODO(), created.ID)
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, created, actual)
+
+	all, err := c.List(context.TODO(), nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(all))
+
+	shared := true
+	filtered, err := c.List(context.TODO(), networks.ListOpts{Shared: &shared})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(filtered))
+	th.AssertEquals(t, Network1.ID, filtered[0].ID)
+
+	name := "private-2"
+	updated, err := c.Update(context.TODO(), created.ID, networks.UpdateOpts{Name: &name})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "private-2", updated.Name)
+	th.AssertEquals(t, 2, updated.RevisionNumber)
+
+	th.AssertNoErr(t, c.Delete(context.TODO(), created.ID))
+	_, err = c.Get(context.TODO(), created.ID)
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusNotFound))
+	err = c.Delete(context.TODO(), created.ID)
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusNotFound))
+}
+
+func TestFakeClientCopies(t *testing.T) {
+	network := Network1
+	network.Tags = []string{"public"}
+	c := networksfake.New(network)
+
+	// the client does not share the slices of the networks it was given
+	network.Tags[0] = "changed"
+
+	got, err := c.Get(context.TODO(), Network1.ID)
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, []string{"public"}, got.Tags)
+
+	// nor those of the networks it returns
+	got.Tags[0] = "changed"
+	got.Subnets[0] = "changed"
+	all, err := c.List(context.TODO(), nil)
+	th.AssertNoErr(t, err)
+	all[0].Tags[0] = "changed"
+
+	got, err = c.Get(context.TODO(), Network1.ID)
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, []string{"public"}, got.Tags)
+	th.CheckDeepEquals(t, Network1.Subnets, got.Subnets)
+}
@@ -0,0 +1,81 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	fake "github.com/gophercloud/gophercloud/v2/openstack/networking/v2/common"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/networks"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+// handleNetworksByName serves the networks list filtered by name, and
+// answers 404 to a Get of any network.
+func handleNetworksByName(t *testing.T, fakeServer th.FakeServer) {
+	fakeServer.Mux.HandleFunc("/v2.0/networks", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+
+		w.Header().Add("Content-Type", "application/json")
+		switch r.URL.Query().Get("name") {
+		case "private":
+			fmt.Fprint(w, `{"networks": [{"id": "db193ab3-96e3-4cb3-8fc5-05f4296d0324", "name": "private"}]}`)
+		case "shared":
+			fmt.Fprint(w, `{"networks": [{"id": "f8b1b4d1-11e3-4a0a-9d5b-08c6b3b6d8d6", "name": "shared"}, {"id": "a5a2d48c-45b1-4ab0-b3f8-06b2c4c4b0f6", "name": "shared"}]}`)
+		default:
+			fmt.Fprint(w, `{"networks": []}`)
+		}
+	})
+	fakeServer.Mux.HandleFunc("/v2.0/networks/", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.WriteHeader(http.StatusNotFound)
+	})
+}
+
+func TestIDFromName(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	handleNetworksByName(t, fakeServer)
+
+	client := fake.ServiceClient(fakeServer)
+
+	id, err := networks.IDFromName(context.TODO(), client, "private")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "db193ab3-96e3-4cb3-8fc5-05f4296d0324", id)
+
+	_, err = networks.IDFromName(context.TODO(), client, "public")
+	var notFound gophercloud.ErrResourceNotFound
+	th.AssertEquals(t, true, errors.As(err, &notFound))
+	th.AssertEquals(t, "network", notFound.ResourceType)
+
+	_, err = networks.IDFromName(context.TODO(), client, "shared")
+	var multiple gophercloud.ErrMultipleResourcesFound
+	th.AssertEquals(t, true, errors.As(err, &multiple))
+	th.AssertEquals(t, 2, multiple.Count)
+}
+
+func TestFind(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	handleNetworksByName(t, fakeServer)
+	fakeServer.Mux.HandleFunc("/v2.0/networks/d32019d3-bc6e-4319-9c1d-6722fc136a22", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, GetResponse)
+	})
+
+	client := fake.ServiceClient(fakeServer)
+
+	n, err := networks.Find(context.TODO(), client, "d32019d3-bc6e-4319-9c1d-6722fc136a22")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "d32019d3-bc6e-4319-9c1d-6722fc136a22", n.ID)
+
+	n, err = networks.Find(context.TODO(), client, "private")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "db193ab3-96e3-4cb3-8fc5-05f4296d0324", n.ID)
+}
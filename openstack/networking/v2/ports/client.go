@@ -0,0 +1,62 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// Client performs the basic port operations. Application code can depend
+// on it rather than on a ServiceClient, so that it can be tested with the
+// in-memory implementation of the ports/fake package instead of an HTTP
+// fixture.
+type Client interface {
+	// Get returns the port with the given ID.
+	Get(ctx context.Context, id string) (*Port, error)
+
+	// List returns all the ports matching opts, following the pagination.
+	List(ctx context.Context, opts ListOptsBuilder) ([]Port, error)
+
+	// Create creates a port.
+	Create(ctx context.Context, opts CreateOptsBuilder) (*Port, error)
+
+	// Update changes the attributes of the port with the given ID.
+	Update(ctx context.Context, id string, opts UpdateOptsBuilder) (*Port, error)
+
+	// Delete deletes the port with the given ID.
+	Delete(ctx context.Context, id string) error
+}
+
+// NewClient returns a Client which performs the port operations with the
+// functions of this package and the given networking client.
+func NewClient(client *gophercloud.ServiceClient) Client {
+	return serviceClient{client: client}
+}
+
+type serviceClient struct {
+	client *gophercloud.ServiceClient
+}
+
+func (c serviceClient) Get(ctx context.Context, id string) (*Port, error) {
+	return Get(ctx, c.client, id).Extract()
+}
+
+func (c serviceClient) List(ctx context.Context, opts ListOptsBuilder) ([]Port, error) {
+	pages, err := List(c.client, opts).AllPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ExtractPorts(pages)
+}
+
+func (c serviceClient) Create(ctx context.Context, opts CreateOptsBuilder) (*Port, error) {
+	return Create(ctx, c.client, opts).Extract()
+}
+
+func (c serviceClient) Update(ctx context.Context, id string, opts UpdateOptsBuilder) (*Port, error) {
+	return Update(ctx, c.client, id, opts).Extract()
+}
+
+func (c serviceClient) Delete(ctx context.Context, id string) error {
+	return Delete(ctx, c.client, id).ExtractErr()
+}
@@ -0,0 +1,200 @@
+/*
+Package fake provides an in-memory implementation of ports.Client, for the
+tests of code which depends on that interface.
+
+Ports are created in the DOWN status, or ACTIVE when they are bound to a
+device, and missing ports are reported with the same 404 error as the
+Networking API:
+
+	client := fake.New(ports.Port{ID: "existing", NetworkID: "net"})
+	port, err := client.Create(ctx, ports.CreateOpts{NetworkID: "net"})
+
+	_, err = client.Get(ctx, "missing")
+	gophercloud.ResponseCodeIs(err, http.StatusNotFound) // true
+*/
+package fake
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2/internal/fakestore"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/ports"
+)
+
+// Client is an in-memory ports.Client. It is safe for concurrent use.
+type Client struct {
+	store *fakestore.Store[ports.Port]
+}
+
+var _ ports.Client = (*Client)(nil)
+
+// New returns a Client holding the given ports.
+func New(initial ...ports.Port) *Client {
+	c := &Client{store: fakestore.New("ports", clone)}
+	for _, p := range initial {
+		c.store.Put(p.ID, p)
+	}
+	return c
+}
+
+// clone copies the slices of a port.
+func clone(p ports.Port) ports.Port {
+	p.FixedIPs = slices.Clone(p.FixedIPs)
+	p.SecurityGroups = slices.Clone(p.SecurityGroups)
+	p.AllowedAddressPairs = slices.Clone(p.AllowedAddressPairs)
+	p.Tags = slices.Clone(p.Tags)
+	return p
+}
+
+// Get implements ports.Client.
+func (c *Client) Get(_ context.Context, id string) (*ports.Port, error) {
+	return c.store.Get(id)
+}
+
+// List implements ports.Client. When opts is a ports.ListOpts, the ports are
+// filtered by ID, Name, Status, NetworkID, ProjectID, DeviceID, DeviceOwner
+// and MACAddress.
+func (c *Client) List(_ context.Context, opts ports.ListOptsBuilder) ([]ports.Port, error) {
+	listOpts, _ := opts.(ports.ListOpts)
+	return c.store.List(func(p ports.Port) bool {
+		return (listOpts.ID == "" || listOpts.ID == p.ID) &&
+			(listOpts.Name == "" || listOpts.Name == p.Name) &&
+			(listOpts.Status == "" || listOpts.Status == p.Status) &&
+			(listOpts.NetworkID == "" || listOpts.NetworkID == p.NetworkID) &&
+			(listOpts.ProjectID == "" || listOpts.ProjectID == p.ProjectID) &&
+			(listOpts.DeviceID == "" || listOpts.DeviceID == p.DeviceID) &&
+			(listOpts.DeviceOwner == "" || listOpts.DeviceOwner == p.DeviceOwner) &&
+			(listOpts.MACAddress == "" || listOpts.MACAddress == p.MACAddress)
+	}), nil
+}
+
+// Create implements ports.Client.
+func (c *Client) Create(_ context.Context, opts ports.CreateOptsBuilder) (*ports.Port, error) {
+	b, err := opts.ToPortCreateMap()
+	if err != nil {
+		return nil, err
+	}
+	var p struct {
+		NetworkID             string              `json:"network_id"`
+		Name                  string              `json:"name"`
+		Description           string              `json:"description"`
+		AdminStateUp          *bool               `json:"admin_state_up"`
+		MACAddress            string              `json:"mac_address"`
+		FixedIPs              []ports.IP          `json:"fixed_ips"`
+		DeviceID              string              `json:"device_id"`
+		DeviceOwner           string              `json:"device_owner"`
+		TenantID              string              `json:"tenant_id"`
+		ProjectID             string              `json:"project_id"`
+		SecurityGroups        []string            `json:"security_groups"`
+		AllowedAddressPairs   []ports.AddressPair `json:"allowed_address_pairs"`
+		PropagateUplinkStatus bool                `json:"propagate_uplink_status"`
+	}
+	if err := fakestore.Decode(b, "port", &p); err != nil {
+		return nil, err
+	}
+
+	projectID := p.ProjectID
+	if projectID == "" {
+		projectID = p.TenantID
+	}
+	now := time.Now().UTC()
+	port := ports.Port{
+		ID:                    fakestore.NewID(),
+		NetworkID:             p.NetworkID,
+		Name:                  p.Name,
+		Description:           p.Description,
+		AdminStateUp:          p.AdminStateUp == nil || *p.AdminStateUp,
+		MACAddress:            p.MACAddress,
+		FixedIPs:              p.FixedIPs,
+		TenantID:              projectID,
+		ProjectID:             projectID,
+		DeviceOwner:           p.DeviceOwner,
+		SecurityGroups:        p.SecurityGroups,
+		DeviceID:              p.DeviceID,
+		AllowedAddressPairs:   p.AllowedAddressPairs,
+		Tags:                  []string{},
+		PropagateUplinkStatus: p.PropagateUplinkStatus,
+		RevisionNumber:        1,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+	setStatus(&port)
+	c.store.Put(port.ID, port)
+	return &port, nil
+}
+
+// Update implements ports.Client.
+func (c *Client) Update(_ context.Context, id string, opts ports.UpdateOptsBuilder) (*ports.Port, error) {
+	b, err := opts.ToPortUpdateMap()
+	if err != nil {
+		return nil, err
+	}
+	var u struct {
+		Name                  *string              `json:"name"`
+		Description           *string              `json:"description"`
+		AdminStateUp          *bool                `json:"admin_state_up"`
+		FixedIPs              *[]ports.IP          `json:"fixed_ips"`
+		DeviceID              *string              `json:"device_id"`
+		DeviceOwner           *string              `json:"device_owner"`
+		SecurityGroups        *[]string            `json:"security_groups"`
+		AllowedAddressPairs   *[]ports.AddressPair `json:"allowed_address_pairs"`
+		PropagateUplinkStatus *bool                `json:"propagate_uplink_status"`
+		MACAddress            *string              `json:"mac_address"`
+	}
+	if err := fakestore.Decode(b, "port", &u); err != nil {
+		return nil, err
+	}
+
+	return c.store.Update(id, func(p *ports.Port) error {
+		if u.Name != nil {
+			p.Name = *u.Name
+		}
+		if u.Description != nil {
+			p.Description = *u.Description
+		}
+		if u.AdminStateUp != nil {
+			p.AdminStateUp = *u.AdminStateUp
+		}
+		if u.FixedIPs != nil {
+			p.FixedIPs = *u.FixedIPs
+		}
+		if u.DeviceID != nil {
+			p.DeviceID = *u.DeviceID
+		}
+		if u.DeviceOwner != nil {
+			p.DeviceOwner = *u.DeviceOwner
+		}
+		if u.SecurityGroups != nil {
+			p.SecurityGroups = *u.SecurityGroups
+		}
+		if u.AllowedAddressPairs != nil {
+			p.AllowedAddressPairs = *u.AllowedAddressPairs
+		}
+		if u.PropagateUplinkStatus != nil {
+			p.PropagateUplinkStatus = *u.PropagateUplinkStatus
+		}
+		if u.MACAddress != nil {
+			p.MACAddress = *u.MACAddress
+		}
+		setStatus(p)
+		p.RevisionNumber++
+		p.UpdatedAt = time.Now().UTC()
+		return nil
+	})
+}
+
+// Delete implements ports.Client.
+func (c *Client) Delete(_ context.Context, id string) error {
+	return c.store.Delete(id)
+}
+
+// setStatus sets the status of a port from its binding to a device.
+func setStatus(p *ports.Port) {
+	if p.DeviceID != "" {
+		p.Status = "ACTIVE"
+	} else {
+		p.Status = "DOWN"
+	}
+}
@@ -0,0 +1,44 @@
+package testing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/ports"
+	portsfake "github.com/gophercloud/gophercloud/v2/openstack/networking/v2/ports/fake"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+func TestFakeClient(t *testing.T) {
+	var c ports.Client = portsfake.New()
+
+	created, err := c.Create(context.TODO(), ports.CreateOpts{
+		NetworkID: "a87cc70a-3e15-4acf-8205-9b711a3531b7",
+		Name:      "private-port",
+		FixedIPs:  []ports.IP{{SubnetID: "a0304c3a-4f08-4c43-88af-d796509c97d2", IPAddress: "10.0.0.2"}},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "DOWN", created.Status)
+	th.AssertEquals(t, true, created.AdminStateUp)
+	th.CheckDeepEquals(t, []ports.IP{{SubnetID: "a0304c3a-4f08-4c43-88af-d796509c97d2", IPAddress: "10.0.0.2"}}, created.FixedIPs)
+
+	_, err = c.Create(context.TODO(), ports.CreateOpts{NetworkID: "other"})
+	th.AssertNoErr(t, err)
+
+	filtered, err := c.List(context.TODO(), ports.ListOpts{NetworkID: "a87cc70a-3e15-4acf-8205-9b711a3531b7"})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(filtered))
+	th.AssertEquals(t, created.ID, filtered[0].ID)
+
+	deviceID := "5e3898d7-11be-483e-9732-b2f5eccd2b2e"
+	updated, err := c.Update(context.TODO(), created.ID, ports.UpdateOpts{DeviceID: &deviceID})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "ACTIVE", updated.Status)
+	th.AssertEquals(t, 2, updated.RevisionNumber)
+
+	th.AssertNoErr(t, c.Delete(context.TODO(), created.ID))
+	_, err = c.Get(context.TODO(), created.ID)
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusNotFound))
+}
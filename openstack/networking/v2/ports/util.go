@@ -0,0 +1,37 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/internal/find"
+)
+
+// IDFromName returns the ID of the port with the given name. It returns a
+// gophercloud.ErrResourceNotFound error if there is none, and a
+// gophercloud.ErrMultipleResourcesFound error if there are several.
+func IDFromName(ctx context.Context, client *gophercloud.ServiceClient, name string) (string, error) {
+	found, err := findByName(ctx, client, name)
+	if err != nil {
+		return "", err
+	}
+	return found.ID, nil
+}
+
+// Find returns the port with the given ID or, if there is none, the port
+// with the given name. It returns a gophercloud.ErrResourceNotFound error if
+// there is none, and a gophercloud.ErrMultipleResourcesFound error if several
+// ports have the given name.
+func Find(ctx context.Context, client *gophercloud.ServiceClient, nameOrID string) (*Port, error) {
+	return find.ByNameOrID(ctx, nameOrID, func(ctx context.Context, id string) (*Port, error) {
+		return Get(ctx, client, id).Extract()
+	}, func(ctx context.Context, name string) (*Port, error) {
+		return findByName(ctx, client, name)
+	})
+}
+
+// findByName returns the port with the given name.
+func findByName(ctx context.Context, client *gophercloud.ServiceClient, name string) (*Port, error) {
+	pager := List(client, ListOpts{Name: name})
+	return find.ByName(ctx, pager, ExtractPorts, func(v Port) string { return v.Name }, "port", name)
+}
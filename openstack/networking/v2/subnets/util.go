@@ -0,0 +1,37 @@
+package subnets
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/internal/find"
+)
+
+// IDFromName returns the ID of the subnet with the given name. It returns a
+// gophercloud.ErrResourceNotFound error if there is none, and a
+// gophercloud.ErrMultipleResourcesFound error if there are several.
+func IDFromName(ctx context.Context, client *gophercloud.ServiceClient, name string) (string, error) {
+	found, err := findByName(ctx, client, name)
+	if err != nil {
+		return "", err
+	}
+	return found.ID, nil
+}
+
+// Find returns the subnet with the given ID or, if there is none, the subnet
+// with the given name. It returns a gophercloud.ErrResourceNotFound error if
+// there is none, and a gophercloud.ErrMultipleResourcesFound error if several
+// subnets have the given name.
+func Find(ctx context.Context, client *gophercloud.ServiceClient, nameOrID string) (*Subnet, error) {
+	return find.ByNameOrID(ctx, nameOrID, func(ctx context.Context, id string) (*Subnet, error) {
+		return Get(ctx, client, id).Extract()
+	}, func(ctx context.Context, name string) (*Subnet, error) {
+		return findByName(ctx, client, name)
+	})
+}
+
+// findByName returns the subnet with the given name.
+func findByName(ctx context.Context, client *gophercloud.ServiceClient, name string) (*Subnet, error) {
+	pager := List(client, ListOpts{Name: name})
+	return find.ByName(ctx, pager, ExtractSubnets, func(v Subnet) string { return v.Name }, "subnet", name)
+}
@@ -0,0 +1,66 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/orchestration/v1/stacks"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/testhelper/client"
+)
+
+func TestWaitForStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		statuses []string
+		status   string
+		failed   string
+	}{
+		{"create complete", []string{"CREATE_IN_PROGRESS", "CREATE_COMPLETE"}, "CREATE_COMPLETE", ""},
+		{"create failed", []string{"CREATE_IN_PROGRESS", "CREATE_FAILED"}, "CREATE_COMPLETE", "CREATE_FAILED"},
+		{"rolled back", []string{"CREATE_IN_PROGRESS", "ROLLBACK_IN_PROGRESS", "ROLLBACK_COMPLETE"}, "CREATE_COMPLETE", "ROLLBACK_COMPLETE"},
+		{"rollback failed", []string{"ROLLBACK_IN_PROGRESS", "ROLLBACK_FAILED"}, "CREATE_COMPLETE", "ROLLBACK_FAILED"},
+		{"awaited rollback", []string{"ROLLBACK_IN_PROGRESS", "ROLLBACK_COMPLETE"}, "ROLLBACK_COMPLETE", ""},
+		{"update failed", []string{"UPDATE_IN_PROGRESS", "UPDATE_FAILED"}, "UPDATE_COMPLETE", "UPDATE_FAILED"},
+		{"update rolled back", []string{"UPDATE_IN_PROGRESS", "UPDATE_ROLLBACK_IN_PROGRESS", "UPDATE_ROLLBACK_COMPLETE"}, "UPDATE_COMPLETE", "UPDATE_ROLLBACK_COMPLETE"},
+		{"update rollback failed", []string{"UPDATE_ROLLBACK_IN_PROGRESS", "UPDATE_ROLLBACK_FAILED"}, "UPDATE_COMPLETE", "UPDATE_ROLLBACK_FAILED"},
+		{"delete failed", []string{"DELETE_IN_PROGRESS", "DELETE_FAILED"}, "DELETE_COMPLETE", "DELETE_FAILED"},
+		{"suspend failed", []string{"SUSPEND_IN_PROGRESS", "SUSPEND_FAILED"}, "SUSPEND_COMPLETE", "SUSPEND_FAILED"},
+		{"resume failed", []string{"RESUME_IN_PROGRESS", "RESUME_FAILED"}, "RESUME_COMPLETE", "RESUME_FAILED"},
+		{"adopt failed", []string{"ADOPT_IN_PROGRESS", "ADOPT_FAILED"}, "ADOPT_COMPLETE", "ADOPT_FAILED"},
+		{"snapshot failed", []string{"SNAPSHOT_IN_PROGRESS", "SNAPSHOT_FAILED"}, "SNAPSHOT_COMPLETE", "SNAPSHOT_FAILED"},
+		{"check failed", []string{"CHECK_IN_PROGRESS", "CHECK_FAILED"}, "CHECK_COMPLETE", "CHECK_FAILED"},
+		{"restore failed", []string{"RESTORE_IN_PROGRESS", "RESTORE_FAILED"}, "RESTORE_COMPLETE", "RESTORE_FAILED"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeServer := th.SetupHTTP()
+			defer fakeServer.Teardown()
+
+			var polls int
+			fakeServer.Mux.HandleFunc("/stacks/postman_stack/16ef0584-4458-41eb-87c8-0dc8d5f66c87", func(w http.ResponseWriter, r *http.Request) {
+				th.TestMethod(t, r, "GET")
+				th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+				w.Header().Add("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"stack": {"id": "16ef0584-4458-41eb-87c8-0dc8d5f66c87", "stack_name": "postman_stack", "stack_status": "%s", "stack_status_reason": "Resource CREATE failed: Quota exceeded"}}`, tt.statuses[polls])
+				polls++
+			})
+
+			ctx := gophercloud.WithPoller(context.TODO(), &gophercloud.Poller{InitialInterval: time.Millisecond})
+			err := stacks.WaitForStatus(ctx, client.ServiceClient(fakeServer), "postman_stack", "16ef0584-4458-41eb-87c8-0dc8d5f66c87", tt.status)
+			th.AssertEquals(t, len(tt.statuses), polls)
+			if tt.failed == "" {
+				th.AssertNoErr(t, err)
+				return
+			}
+			var failed gophercloud.ErrResourceFailed
+			th.AssertEquals(t, true, errors.As(err, &failed))
+			th.AssertEquals(t, tt.failed, failed.Status)
+			th.AssertEquals(t, "Resource CREATE failed: Quota exceeded", failed.Fault)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package stacks
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// FailureStatuses are the statuses in which Heat leaves a stack when an action
+// on it failed, along with the statuses of a stack whose failed create or
+// update was rolled back.
+var FailureStatuses = []string{
+	"CREATE_FAILED",
+	"UPDATE_FAILED",
+	"DELETE_FAILED",
+	"ROLLBACK_FAILED",
+	"ROLLBACK_COMPLETE",
+	"UPDATE_ROLLBACK_FAILED",
+	"UPDATE_ROLLBACK_COMPLETE",
+	"SUSPEND_FAILED",
+	"RESUME_FAILED",
+	"ADOPT_FAILED",
+	"SNAPSHOT_FAILED",
+	"CHECK_FAILED",
+	"RESTORE_FAILED",
+}
+
+// WaitForStatus polls a stack until its stack_status is the given one, such as
+// CREATE_COMPLETE. The stack_status_reason of a failed stack is the Fault of
+// the returned gophercloud.ErrResourceFailed error.
+func WaitForStatus(ctx context.Context, c *gophercloud.ServiceClient, stackName, stackID, status string) error {
+	return gophercloud.WaitForStatus(ctx, stackID, status, FailureStatuses, func(ctx context.Context) (gophercloud.ResourceStatus, error) {
+		current, err := Get(ctx, c, stackName, stackID).Extract()
+		if err != nil {
+			return gophercloud.ResourceStatus{}, err
+		}
+		return gophercloud.ResourceStatus{
+			Status: current.Status,
+			Fault:  current.StatusReason,
+		}, nil
+	})
+}
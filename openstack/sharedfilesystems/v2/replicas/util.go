@@ -0,0 +1,24 @@
+package replicas
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// FailureStatuses are the statuses of a share replica which Manila failed to
+// create or to delete.
+var FailureStatuses = []string{"error", "error_deleting"}
+
+// WaitForStatus polls a share replica until its status is the given one. Its
+// replica_state, which tracks the synchronization with the active replica, is
+// not checked.
+func WaitForStatus(ctx context.Context, c *gophercloud.ServiceClient, id, status string) error {
+	return gophercloud.WaitForStatus(ctx, id, status, FailureStatuses, func(ctx context.Context) (gophercloud.ResourceStatus, error) {
+		current, err := Get(ctx, c, id).Extract()
+		if err != nil {
+			return gophercloud.ResourceStatus{}, err
+		}
+		return gophercloud.ResourceStatus{Status: current.Status}, nil
+	})
+}
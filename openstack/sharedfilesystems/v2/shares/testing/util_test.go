@@ -0,0 +1,58 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/sharedfilesystems/v2/shares"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/testhelper/client"
+)
+
+func TestWaitForStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		statuses []string
+		status   string
+		failed   string
+	}{
+		{"available", []string{"creating", "available"}, "available", ""},
+		{"error", []string{"creating", "error"}, "available", "error"},
+		{"error deleting", []string{"deleting", "error_deleting"}, "deleted", "error_deleting"},
+		{"extending error", []string{"extending", "extending_error"}, "available", "extending_error"},
+		{"shrinking error", []string{"shrinking", "shrinking_error"}, "available", "shrinking_error"},
+		{"reverting error", []string{"reverting", "reverting_error"}, "available", "reverting_error"},
+		{"manage error", []string{"manage_starting", "manage_error"}, "available", "manage_error"},
+		{"unmanage error", []string{"unmanage_starting", "unmanage_error"}, "unmanaged", "unmanage_error"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeServer := th.SetupHTTP()
+			defer fakeServer.Teardown()
+
+			var polls int
+			fakeServer.Mux.HandleFunc("/shares/011d21e2-fbc3-4e4a-9993-9ea223f73264", func(w http.ResponseWriter, r *http.Request) {
+				th.TestMethod(t, r, "GET")
+				th.TestHeader(t, r, "X-Auth-Token", client.TokenID)
+				w.Header().Add("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"share": {"id": "011d21e2-fbc3-4e4a-9993-9ea223f73264", "status": "%s"}}`, tt.statuses[polls])
+				polls++
+			})
+
+			ctx := gophercloud.WithPoller(context.TODO(), &gophercloud.Poller{InitialInterval: time.Millisecond})
+			err := shares.WaitForStatus(ctx, client.ServiceClient(fakeServer), "011d21e2-fbc3-4e4a-9993-9ea223f73264", tt.status)
+			th.AssertEquals(t, len(tt.statuses), polls)
+			if tt.failed == "" {
+				th.AssertNoErr(t, err)
+				return
+			}
+			var failed gophercloud.ErrResourceFailed
+			th.AssertEquals(t, true, errors.As(err, &failed))
+			th.AssertEquals(t, tt.failed, failed.Status)
+		})
+	}
+}
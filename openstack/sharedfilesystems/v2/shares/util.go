@@ -0,0 +1,32 @@
+package shares
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// FailureStatuses are the error statuses in which Manila leaves a share after a
+// failed operation, such as extending_error after a failed extend.
+var FailureStatuses = []string{
+	"error",
+	"error_deleting",
+	"extending_error",
+	"shrinking_error",
+	"reverting_error",
+	"manage_error",
+	"unmanage_error",
+}
+
+// WaitForStatus polls a share until it reaches the given status, such as
+// available, and returns a gophercloud.ErrResourceFailed error as soon as the
+// share is in one of the FailureStatuses.
+func WaitForStatus(ctx context.Context, c *gophercloud.ServiceClient, id, status string) error {
+	return gophercloud.WaitForStatus(ctx, id, status, FailureStatuses, func(ctx context.Context) (gophercloud.ResourceStatus, error) {
+		current, err := Get(ctx, c, id).Extract()
+		if err != nil {
+			return gophercloud.ResourceStatus{}, err
+		}
+		return gophercloud.ResourceStatus{Status: current.Status}, nil
+	})
+}
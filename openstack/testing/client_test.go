@@ -2,13 +2,16 @@ package testing
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
 
 	"github.com/gophercloud/gophercloud/v2"
 	"github.com/gophercloud/gophercloud/v2/openstack"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/tokens"
 	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/tokencache"
 )
 
 const ID = "0123456789"
@@ -314,3 +317,327 @@ func TestAuthenticatedClientV3Fails(t *testing.T) {
 func TestAuthenticatedClientV2Fails(t *testing.T) {
 	testAuthenticatedClientFails(t, "http://bad-address.example.com/v2.0")
 }
+
+func TestAuthenticatedClientV3TokenCache(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	fakeServer.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `
+			{
+				"versions": {
+					"values": [
+						{
+							"status": "stable",
+							"id": "v3.0",
+							"links": [
+								{ "href": "%s", "rel": "self" }
+							]
+						}
+					]
+				}
+			}
+		`, fakeServer.Endpoint()+"v3/")
+	})
+
+	var tokenRequests int
+	fakeServer.Mux.HandleFunc("/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		// the cached token is validated before it is used
+		if r.Method == http.MethodHead {
+			th.TestHeader(t, r, "X-Subject-Token", ID)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		th.TestMethod(t, r, "POST")
+		tokenRequests++
+		w.Header().Add("X-Subject-Token", ID)
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `
+			{
+				"token": {
+					"expires_at": "2099-02-02T18:30:59.000000Z",
+					"catalog": [
+						{
+							"type": "compute",
+							"name": "nova",
+							"endpoints": [
+								{ "interface": "public", "region": "RegionOne", "url": "%s" }
+							]
+						}
+					]
+				}
+			}
+		`, fakeServer.Endpoint()+"compute/v2.1/")
+	})
+
+	cache, err := tokencache.NewFileCache(t.TempDir())
+	th.AssertNoErr(t, err)
+
+	options := gophercloud.AuthOptions{
+		Username:         "me",
+		Password:         "secret",
+		DomainName:       "default",
+		TenantName:       "project",
+		IdentityEndpoint: fakeServer.Endpoint(),
+		TokenCache:       cache,
+	}
+
+	for range 2 {
+		client, err := openstack.AuthenticatedClient(context.TODO(), options)
+		th.AssertNoErr(t, err)
+		th.CheckEquals(t, ID, client.TokenID)
+
+		compute, err := openstack.NewComputeV2(context.TODO(), client, gophercloud.EndpointOpts{Region: "RegionOne"})
+		th.AssertNoErr(t, err)
+		th.CheckEquals(t, fakeServer.Endpoint()+"compute/v2.1/", compute.Endpoint)
+	}
+	th.CheckEquals(t, 1, tokenRequests)
+
+	// different credentials must not reuse the cached token
+	options.Password = "other"
+	_, err = openstack.AuthenticatedClient(context.TODO(), options)
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, 2, tokenRequests)
+}
+
+// federatedToken is a gophercloud.FederatedAuth which returns a fixed token.
+type federatedToken string
+
+func (f federatedToken) FederatedToken(context.Context, *gophercloud.ServiceClient) (string, error) {
+	return string(f), nil
+}
+
+func TestAuthenticatedClientV3TokenCacheInvalidation(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var tokens int
+	revoked := make(map[string]bool)
+	failCreate := false
+	fakeServer.Mux.HandleFunc("/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			if revoked[r.Header.Get("X-Auth-Token")] {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		th.TestMethod(t, r, "POST")
+		if failCreate {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		tokens++
+		w.Header().Add("X-Subject-Token", fmt.Sprintf("token-%d", tokens))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"token": {"expires_at": "2099-02-02T18:30:59.000000Z", "catalog": []}}`)
+	})
+
+	cache, err := tokencache.NewFileCache(t.TempDir())
+	th.AssertNoErr(t, err)
+
+	options := gophercloud.AuthOptions{
+		UserID:           "me",
+		Password:         "secret",
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+		TokenCache:       cache,
+	}
+
+	client, err := openstack.AuthenticatedClient(context.TODO(), options)
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "token-1", client.TokenID)
+
+	// a revoked token is not loaded from the cache
+	revoked["token-1"] = true
+	client, err = openstack.AuthenticatedClient(context.TODO(), options)
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "token-2", client.TokenID)
+	cached, err := cache.Load(context.TODO(), options.TokenCacheKey())
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "token-2", cached.TokenID)
+
+	// the cached token is deleted when the client reauthenticates, even
+	// if the reauthentication fails
+	options.AllowReauth = true
+	client, err = openstack.AuthenticatedClient(context.TODO(), options)
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "token-2", client.TokenID)
+	failCreate = true
+	th.AssertErr(t, client.Reauthenticate(context.TODO(), ""))
+	cached, err = cache.Load(context.TODO(), options.TokenCacheKey())
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, true, cached == nil)
+
+	// a federated authentication cannot use the cache
+	options.Federated = federatedToken("federated-token")
+	_, err = openstack.AuthenticatedClient(context.TODO(), options)
+	th.AssertErr(t, err)
+}
+
+func TestAuthenticatedClientV3Receipt(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	fakeServer.Mux.HandleFunc("/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		if r.Header.Get("Openstack-Auth-Receipt") != "receipt" {
+			w.Header().Add("Openstack-Auth-Receipt", "receipt")
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `
+				{
+					"receipt": {
+						"methods": ["password"],
+						"expires_at": "2099-02-02T18:30:59.000000Z",
+						"user": { "id": "me" }
+					},
+					"required_auth_methods": [["password", "totp"]]
+				}
+			`)
+			return
+		}
+
+		th.TestJSONRequest(t, r, `
+			{
+				"auth": {
+					"identity": {
+						"methods": ["totp"],
+						"totp": { "user": { "id": "me", "passcode": "123456" } }
+					},
+					"scope": { "project": { "id": "project" } }
+				}
+			}
+		`)
+		w.Header().Add("X-Subject-Token", ID)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{ "token": { "expires_at": "2099-02-02T18:30:59.000000Z" } }`)
+	})
+
+	options := gophercloud.AuthOptions{
+		UserID:           "me",
+		Password:         "secret",
+		TenantID:         "project",
+		IdentityEndpoint: fakeServer.Endpoint() + "v3/",
+	}
+	_, err := openstack.AuthenticatedClient(context.TODO(), options)
+	var receiptErr tokens.ErrReceiptRequired
+	th.AssertEquals(t, true, errors.As(err, &receiptErr))
+	th.CheckDeepEquals(t, [][]string{{"totp"}}, receiptErr.MissingMethods())
+
+	options.Password = ""
+	options.Passcode = "123456"
+	options.Receipt = receiptErr.Receipt
+	client, err := openstack.AuthenticatedClient(context.TODO(), options)
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, ID, client.TokenID)
+}
+
+func TestNewComputeV2MaxMicroversion(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	fakeServer.Mux.HandleFunc("/compute/v2.1/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `
+			{
+				"version": {
+					"id": "v2.1",
+					"status": "CURRENT",
+					"version": "2.90",
+					"min_version": "2.1",
+					"links": [
+						{ "href": "%s", "rel": "self" }
+					]
+				}
+			}
+		`, fakeServer.Endpoint()+"compute/v2.1/")
+	})
+
+	client := &gophercloud.ProviderClient{
+		EndpointLocator: func(context.Context, gophercloud.EndpointOpts) (string, error) {
+			return fakeServer.Endpoint() + "compute/v2.1/", nil
+		},
+	}
+
+	compute, err := openstack.NewComputeV2(context.TODO(), client, gophercloud.EndpointOpts{MaxMicroversion: "2.53"})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "2.53", compute.Microversion)
+
+	compute, err = openstack.NewComputeV2(context.TODO(), client, gophercloud.EndpointOpts{MaxMicroversion: "latest"})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "2.90", compute.Microversion)
+
+	compute, err = openstack.NewComputeV2(context.TODO(), client, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "", compute.Microversion)
+}
+
+func TestNewNetworkV2IgnoresMaxMicroversion(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	fakeServer.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL)
+	})
+
+	client := &gophercloud.ProviderClient{
+		EndpointLocator: func(context.Context, gophercloud.EndpointOpts) (string, error) {
+			return fakeServer.Endpoint() + "network/", nil
+		},
+	}
+
+	network, err := openstack.NewNetworkV2(context.TODO(), client, gophercloud.EndpointOpts{MaxMicroversion: "2.79"})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "", network.Microversion)
+}
+
+func TestNewBlockStorageMaxMicroversion(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var requests int
+	fakeServer.Mux.HandleFunc("/volume/v3/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `
+			{
+				"version": {
+					"id": "v3.0",
+					"status": "CURRENT",
+					"version": "3.70",
+					"min_version": "3.0",
+					"links": [
+						{ "href": "%s", "rel": "self" }
+					]
+				}
+			}
+		`, fakeServer.Endpoint()+"volume/v3/")
+	})
+	fakeServer.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL)
+	})
+
+	client := &gophercloud.ProviderClient{
+		EndpointLocator: func(_ context.Context, eo gophercloud.EndpointOpts) (string, error) {
+			return fakeServer.Endpoint() + fmt.Sprintf("volume/v%d/", eo.Version), nil
+		},
+	}
+
+	// the block-storage v2 API has no microversions
+	v2, err := openstack.NewBlockStorageV2(context.TODO(), client, gophercloud.EndpointOpts{MaxMicroversion: "latest"})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "", v2.Microversion)
+
+	// a compute ceiling does not apply to block-storage
+	v3, err := openstack.NewBlockStorageV3(context.TODO(), client, gophercloud.EndpointOpts{MaxMicroversion: "2.90"})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "", v3.Microversion)
+	th.CheckEquals(t, 0, requests)
+
+	v3, err = openstack.NewBlockStorageV3(context.TODO(), client, gophercloud.EndpointOpts{MaxMicroversion: "3.60"})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "3.60", v3.Microversion)
+
+	v3, err = openstack.NewBlockStorageV3(context.TODO(), client, gophercloud.EndpointOpts{MaxMicroversion: "latest"})
+	th.AssertNoErr(t, err)
+	th.CheckEquals(t, "3.70", v3.Microversion)
+}
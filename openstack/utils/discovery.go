@@ -280,6 +280,34 @@ func RequireMicroversion(ctx context.Context, client gophercloud.ServiceClient,
 	return client, nil
 }
 
+// NegotiateMicroversion returns the highest microversion that is supported by
+// the ServiceClient Endpoint and is not higher than maxVersion. If maxVersion
+// is "latest", the highest microversion supported by the endpoint is returned.
+func NegotiateMicroversion(ctx context.Context, client *gophercloud.ServiceClient, maxVersion string) (string, error) {
+	supportedMicroversions, err := GetSupportedMicroversions(ctx, client)
+	if err != nil {
+		return "", fmt.Errorf("unable to determine supported microversions: %w", err)
+	}
+
+	highest := fmt.Sprintf("%d.%d", supportedMicroversions.MaxMajor, supportedMicroversions.MaxMinor)
+	if maxVersion == "latest" {
+		return highest, nil
+	}
+
+	major, minor, err := ParseMicroversion(maxVersion)
+	if err != nil {
+		return "", err
+	}
+
+	if major > supportedMicroversions.MaxMajor || (major == supportedMicroversions.MaxMajor && minor >= supportedMicroversions.MaxMinor) {
+		return highest, nil
+	}
+	if major < supportedMicroversions.MinMajor || (major == supportedMicroversions.MinMajor && minor < supportedMicroversions.MinMinor) {
+		return "", fmt.Errorf("no supported microversion up to %s. Supported versions: %v", maxVersion, supportedMicroversions)
+	}
+	return maxVersion, nil
+}
+
 // IsSupported checks if a microversion falls in the supported interval.
 // It returns true if the version is within the interval and false otherwise.
 func (supported SupportedMicroversions) IsSupported(version string) (bool, error) {
@@ -488,3 +488,74 @@ func TestMicroversionSupported(t *testing.T) {
 		})
 	}
 }
+
+func TestNegotiateMicroversion(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	setupMultiServiceVersionHandler(fakeServer)
+
+	tests := []struct {
+		name        string
+		endpoint    string
+		maxVersion  string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:       "latest",
+			endpoint:   fakeServer.Endpoint() + "compute/v2.1/",
+			maxVersion: "latest",
+			expected:   "2.90",
+		},
+		{
+			name:       "within the supported range",
+			endpoint:   fakeServer.Endpoint() + "compute/v2.1/",
+			maxVersion: "2.53",
+			expected:   "2.53",
+		},
+		{
+			name:       "above the supported range",
+			endpoint:   fakeServer.Endpoint() + "compute/v2.1/",
+			maxVersion: "2.100",
+			expected:   "2.90",
+		},
+		{
+			name:        "below the supported range",
+			endpoint:    fakeServer.Endpoint() + "baremetal/v1/",
+			maxVersion:  "1.0",
+			expectedErr: "no supported microversion",
+		},
+		{
+			name:        "invalid maximum",
+			endpoint:    fakeServer.Endpoint() + "compute/v2.1/",
+			maxVersion:  "2",
+			expectedErr: "invalid microversion",
+		},
+		{
+			name:        "microversions not supported",
+			endpoint:    fakeServer.Endpoint() + "compute/v2/",
+			maxVersion:  "2.53",
+			expectedErr: "not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &gophercloud.ServiceClient{
+				ProviderClient: &gophercloud.ProviderClient{},
+				Endpoint:       tt.endpoint,
+			}
+
+			actual, err := utils.NegotiateMicroversion(context.TODO(), client, tt.maxVersion)
+
+			if tt.expectedErr != "" {
+				th.AssertErr(t, err)
+				if !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("Expected error to contain '%s', got '%s'", tt.expectedErr, err)
+				}
+				return
+			}
+			th.AssertNoErr(t, err)
+			th.AssertEquals(t, tt.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package pagination
+
+// Cursor is an opaque position in a collection returned by a Pager. It can
+// be serialized, for example to a file or a database, and used later, even by
+// another process, to resume the listing of the collection where it stopped.
+//
+// An empty Cursor marks the end of a collection.
+type Cursor string
+
+// CursorAfter returns the Cursor of the page which follows the given page,
+// which must have been returned by this Pager. It returns an empty Cursor if
+// the given page is the last one.
+//
+// Example of a listing which saves its progress after each page:
+//
+//	pager := users.List(client, nil)
+//	err := pager.EachPage(ctx, func(_ context.Context, page pagination.Page) (bool, error) {
+//		...
+//		cursor, err := pager.CursorAfter(page)
+//		if err != nil {
+//			return false, err
+//		}
+//		return true, saveCheckpoint(cursor)
+//	})
+func (p Pager) CursorAfter(page Page) (Cursor, error) {
+	url, err := page.NextPageURL(p.client.ServiceURL())
+	if err != nil {
+		return "", err
+	}
+	return Cursor(url), nil
+}
+
+// FromCursor returns a copy of the Pager which starts at the position of the
+// given Cursor, rather than at the first page. The Pager must be created
+// with the same List function and options as the Pager which returned the
+// Cursor. If the Cursor is empty, the returned Pager yields no pages and its
+// AllPages method returns an empty page, from which the Extract function of
+// the List function extracts no items.
+//
+// Example:
+//
+//	pager := users.List(client, nil).FromCursor(loadCheckpoint())
+func (p Pager) FromCursor(cursor Cursor) Pager {
+	p.initialURL = string(cursor)
+	p.firstPage = nil
+	p.exhausted = cursor == ""
+	return p
+}
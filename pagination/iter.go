@@ -0,0 +1,74 @@
+package pagination
+
+import (
+	"context"
+	"iter"
+)
+
+// Pages returns an iterator over the pages returned by a Pager. Pages are
+// fetched lazily, one at a time, as the iteration progresses. If a page
+// cannot be fetched, the iterator yields a nil Page with the error and
+// stops.
+//
+// Example:
+//
+//	for page, err := range servers.List(client, nil).Pages(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (p Pager) Pages(ctx context.Context) iter.Seq2[Page, error] {
+	return func(yield func(Page, error) bool) {
+		stopped := false
+		err := p.EachPage(ctx, func(_ context.Context, page Page) (bool, error) {
+			if !yield(page, nil) {
+				stopped = true
+				return false, nil
+			}
+			return true, nil
+		})
+		if err != nil && !stopped {
+			yield(nil, err)
+		}
+	}
+}
+
+// Items returns an iterator over the items of all the pages returned by a
+// Pager, using extract to get the items of each page. It is typically used
+// with the Extract function of a resource package. Pages are fetched lazily
+// as the iteration progresses, so that large collections can be processed
+// without loading them in memory at once. If a page cannot be fetched or
+// extracted, the iterator yields the zero value of T with the error and
+// stops.
+//
+// Example:
+//
+//	pager := ports.List(client, ports.ListOpts{})
+//	for port, err := range pagination.Items(ctx, pager, ports.ExtractPorts) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(port.ID)
+//	}
+func Items[T any](ctx context.Context, p Pager, extract func(Page) ([]T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for page, err := range p.Pages(ctx) {
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			items, err := extract(page)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
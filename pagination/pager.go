@@ -44,10 +44,21 @@ type Pager struct {
 
 	firstPage Page
 
+	// exhausted is set when the Pager starts from the Cursor which marks the
+	// end of the collection.
+	exhausted bool
+
 	Err error
 
 	// Headers supplies additional HTTP headers to populate on each paged request.
 	Headers map[string]string
+
+	// Prefetch, if greater than zero, makes EachPage fetch up to Prefetch
+	// pages ahead of the page being handled, concurrently with the handler.
+	// This reduces the time spent listing large collections, at the cost of
+	// fetching pages which are not handled if the iteration stops early. It
+	// has no effect on collections returned in a single page.
+	Prefetch int
 }
 
 // NewPager constructs a manually-configured pager.
@@ -63,11 +74,9 @@ func NewPager(client *gophercloud.ServiceClient, initialURL string, createPage f
 // WithPageCreator returns a new Pager that substitutes a different page creation function. This is
 // useful for overriding List functions in delegation.
 func (p Pager) WithPageCreator(createPage func(r PageResult) Page) Pager {
-	return Pager{
-		client:     p.client,
-		initialURL: p.initialURL,
-		createPage: createPage,
-	}
+	np := p
+	np.createPage = createPage
+	return np
 }
 
 func (p Pager) fetchNextPage(ctx context.Context, url string) (Page, error) {
@@ -91,6 +100,12 @@ func (p Pager) EachPage(ctx context.Context, handler func(context.Context, Page)
 	if p.Err != nil {
 		return p.Err
 	}
+	if p.exhausted {
+		return nil
+	}
+	if p.Prefetch > 0 {
+		return p.eachPagePrefetch(ctx, handler)
+	}
 	currentURL := p.initialURL
 	for {
 		var currentPage Page
@@ -139,6 +154,15 @@ func (p Pager) AllPages(ctx context.Context) (Page, error) {
 	if p.Err != nil {
 		return nil, p.Err
 	}
+	if p.exhausted {
+		// an empty page of the type returned by the List function, which
+		// its Extract function accepts
+		h := make(http.Header)
+		for k, v := range p.Headers {
+			h.Add(k, v)
+		}
+		return p.createPage(PageResult{Result: gophercloud.Result{Header: h}}), nil
+	}
 	// pagesSlice holds all the pages until they get converted into as Page Body.
 	var pagesSlice []any
 	// body will contain the final concatenated Page body.
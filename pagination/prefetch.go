@@ -0,0 +1,111 @@
+package pagination
+
+import (
+	"context"
+	"sync"
+)
+
+// prefetchedPage is a page fetched ahead of the handler, or the error which
+// occurred while fetching it.
+type prefetchedPage struct {
+	page Page
+	err  error
+}
+
+// eachPagePrefetch is the implementation of EachPage when Prefetch is set.
+// The first page is fetched synchronously. If it links to a next page, the
+// following pages are fetched by a goroutine and buffered until the handler
+// is ready for them.
+func (p Pager) eachPagePrefetch(ctx context.Context, handler func(context.Context, Page) (bool, error)) error {
+	page := p.firstPage
+	if page == nil {
+		var err error
+		page, err = p.fetchNextPage(ctx, p.initialURL)
+		if err != nil {
+			return err
+		}
+	}
+
+	empty, err := page.IsEmpty()
+	if err != nil {
+		return err
+	}
+	if empty {
+		return nil
+	}
+
+	nextURL, err := page.NextPageURL(p.client.ServiceURL())
+	if err != nil {
+		return err
+	}
+	if nextURL == "" {
+		// there is nothing to prefetch
+		_, err := handler(ctx, page)
+		return err
+	}
+
+	prefetchCtx, cancel := context.WithCancel(ctx)
+	pages := make(chan prefetchedPage, p.Prefetch-1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(pages)
+		p.prefetch(prefetchCtx, nextURL, pages)
+	}()
+	// stop the prefetching goroutine and wait for it to exit, so that no
+	// request is left in flight when EachPage returns
+	defer wg.Wait()
+	defer cancel()
+
+	for {
+		ok, err := handler(ctx, page)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		next, more := <-pages
+		if !more {
+			// the goroutine stops without sending an error only if there
+			// are no more pages or if ctx is done
+			return ctx.Err()
+		}
+		if next.err != nil {
+			return next.err
+		}
+		page = next.page
+	}
+}
+
+// prefetch fetches the pages starting at url and sends them to pages, until
+// the last page or an error is reached, or until ctx is done.
+func (p Pager) prefetch(ctx context.Context, url string, pages chan<- prefetchedPage) {
+	for url != "" {
+		page, err := p.fetchNextPage(ctx, url)
+		if err == nil {
+			var empty bool
+			empty, err = page.IsEmpty()
+			if err == nil && empty {
+				return
+			}
+		}
+		if err == nil {
+			url, err = page.NextPageURL(p.client.ServiceURL())
+		}
+
+		select {
+		case pages <- prefetchedPage{page: page, err: err}:
+		case <-ctx.Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2/pagination"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+func TestCursorMarker(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	pager := createMarkerPaged(t, fakeServer)
+
+	// stop after the first page and save the position
+	var cursor pagination.Cursor
+	err := pager.EachPage(context.TODO(), func(_ context.Context, page pagination.Page) (bool, error) {
+		var err error
+		cursor, err = pager.CursorAfter(page)
+		return false, err
+	})
+	th.AssertNoErr(t, err)
+
+	b, err := json.Marshal(cursor)
+	th.AssertNoErr(t, err)
+	var restored pagination.Cursor
+	th.AssertNoErr(t, json.Unmarshal(b, &restored))
+
+	page, err := pager.FromCursor(restored).AllPages(context.TODO())
+	th.AssertNoErr(t, err)
+	actual, err := ExtractMarkerStrings(page)
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, []string{"ddd", "eee", "fff", "ggg", "hhh", "iii"}, actual)
+}
+
+func TestCursorLinked(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	pager := createLinked(fakeServer)
+
+	var cursors []pagination.Cursor
+	err := pager.EachPage(context.TODO(), func(_ context.Context, page pagination.Page) (bool, error) {
+		cursor, err := pager.CursorAfter(page)
+		cursors = append(cursors, cursor)
+		return true, err
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, len(cursors))
+	th.AssertEquals(t, pagination.Cursor(""), cursors[2])
+
+	var actual []int
+	for i, err := range pagination.Items(context.TODO(), pager.FromCursor(cursors[1]), ExtractLinkedInts) {
+		th.AssertNoErr(t, err)
+		actual = append(actual, i)
+	}
+	th.CheckDeepEquals(t, []int{7, 8, 9}, actual)
+
+	// the empty cursor marks the end of the collection
+	exhausted := pager.FromCursor(cursors[2])
+	err = exhausted.EachPage(context.TODO(), func(_ context.Context, page pagination.Page) (bool, error) {
+		t.Fatal("unexpected page")
+		return false, nil
+	})
+	th.AssertNoErr(t, err)
+	page, err := exhausted.AllPages(context.TODO())
+	th.AssertNoErr(t, err)
+	isEmpty, err := page.IsEmpty()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, isEmpty)
+	ints, err := ExtractLinkedInts(page)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 0, len(ints))
+}
@@ -0,0 +1,125 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/pagination"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/testhelper/client"
+)
+
+func TestItemsLinked(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	pager := createLinked(fakeServer)
+
+	var actual []int
+	for i, err := range pagination.Items(context.TODO(), pager, ExtractLinkedInts) {
+		th.AssertNoErr(t, err)
+		actual = append(actual, i)
+	}
+	th.CheckDeepEquals(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, actual)
+}
+
+func TestItemsMarker(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	pager := createMarkerPaged(t, fakeServer)
+
+	var actual []string
+	for s, err := range pagination.Items(context.TODO(), pager, ExtractMarkerStrings) {
+		th.AssertNoErr(t, err)
+		actual = append(actual, s)
+	}
+	th.CheckDeepEquals(t, []string{"aaa", "bbb", "ccc", "ddd", "eee", "fff", "ggg", "hhh", "iii"}, actual)
+}
+
+func TestItemsSingle(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	pager := setupSinglePaged(fakeServer)
+
+	var actual []int
+	for i, err := range pagination.Items(context.TODO(), pager, ExtractSingleInts) {
+		th.AssertNoErr(t, err)
+		actual = append(actual, i)
+	}
+	th.CheckDeepEquals(t, []int{1, 2, 3}, actual)
+}
+
+func TestItemsStopsEarly(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var requests int
+	fakeServer.Mux.HandleFunc("/page1", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprintf(w, `{ "ints": [1, 2, 3], "links": { "next": "%s/page2" } }`, fakeServer.Server.URL)
+	})
+	fakeServer.Mux.HandleFunc("/page2", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, `{ "ints": [4, 5, 6], "links": { "next": null } }`)
+	})
+
+	createPage := func(r pagination.PageResult) pagination.Page {
+		return LinkedPageResult{pagination.LinkedPageBase{PageResult: r}}
+	}
+	pager := pagination.NewPager(client.ServiceClient(fakeServer), fakeServer.Server.URL+"/page1", createPage)
+
+	var actual []int
+	for i, err := range pagination.Items(context.TODO(), pager, ExtractLinkedInts) {
+		th.AssertNoErr(t, err)
+		actual = append(actual, i)
+		if i == 2 {
+			break
+		}
+	}
+	th.CheckDeepEquals(t, []int{1, 2}, actual)
+	th.CheckEquals(t, 1, requests)
+}
+
+func TestItemsError(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	fakeServer.Mux.HandleFunc("/page1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprintf(w, `{ "ints": [1, 2, 3], "links": { "next": "%s/page2" } }`, fakeServer.Server.URL)
+	})
+	fakeServer.Mux.HandleFunc("/page2", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	createPage := func(r pagination.PageResult) pagination.Page {
+		return LinkedPageResult{pagination.LinkedPageBase{PageResult: r}}
+	}
+	pager := pagination.NewPager(client.ServiceClient(fakeServer), fakeServer.Server.URL+"/page1", createPage)
+
+	var actual []int
+	var errs []error
+	for i, err := range pagination.Items(context.TODO(), pager, ExtractLinkedInts) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		actual = append(actual, i)
+	}
+	th.CheckDeepEquals(t, []int{1, 2, 3}, actual)
+	th.AssertEquals(t, 1, len(errs))
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(errs[0], http.StatusInternalServerError))
+
+	pager.Err = errors.New("invalid options")
+	for _, err := range pager.Pages(context.TODO()) {
+		th.AssertEquals(t, pager.Err, err)
+	}
+}
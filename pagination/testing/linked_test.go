@@ -114,3 +114,45 @@ func TestAllPagesLinked(t *testing.T) {
 	th.AssertNoErr(t, err)
 	th.CheckDeepEquals(t, expected, actual)
 }
+
+func TestWithPageCreator(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	var requests int
+	fakeServer.Mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		th.TestHeader(t, r, "X-Test", "value")
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, `{ "ints": [1, 2, 3], "links": { "next": null } }`)
+	})
+
+	pager := pagination.NewPager(client.ServiceClient(fakeServer), fakeServer.Server.URL+"/page", func(r pagination.PageResult) pagination.Page {
+		return nil
+	})
+	pager.Headers = map[string]string{"X-Test": "value"}
+	pager.Prefetch = 2
+
+	createPage := func(r pagination.PageResult) pagination.Page {
+		return LinkedPageResult{pagination.LinkedPageBase{PageResult: r}}
+	}
+	delegated := pager.WithPageCreator(createPage)
+	th.AssertEquals(t, 2, delegated.Prefetch)
+
+	var actual []int
+	for i, err := range pagination.Items(context.TODO(), delegated, ExtractLinkedInts) {
+		th.AssertNoErr(t, err)
+		actual = append(actual, i)
+	}
+	th.CheckDeepEquals(t, []int{1, 2, 3}, actual)
+	th.AssertEquals(t, 1, requests)
+
+	// a pager at the end of the collection stays there
+	exhausted := pager.FromCursor("").WithPageCreator(createPage)
+	err := exhausted.EachPage(context.TODO(), func(_ context.Context, page pagination.Page) (bool, error) {
+		t.Fatal("unexpected page")
+		return false, nil
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, requests)
+}
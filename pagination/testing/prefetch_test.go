@@ -0,0 +1,166 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/pagination"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/testhelper/client"
+)
+
+// createCountedLinked returns a pager over n linked pages of one item each,
+// and a counter of the requests received.
+func createCountedLinked(fakeServer th.FakeServer, n int) (pagination.Pager, *atomic.Int32) {
+	var requests atomic.Int32
+	for i := 1; i <= n; i++ {
+		fakeServer.Mux.HandleFunc(fmt.Sprintf("/page%d", i), func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.Header().Add("Content-Type", "application/json")
+			next := "null"
+			if i < n {
+				next = fmt.Sprintf(`"%s/page%d"`, fakeServer.Server.URL, i+1)
+			}
+			fmt.Fprintf(w, `{ "ints": [%d], "links": { "next": %s } }`, i, next)
+		})
+	}
+
+	createPage := func(r pagination.PageResult) pagination.Page {
+		return LinkedPageResult{pagination.LinkedPageBase{PageResult: r}}
+	}
+	return pagination.NewPager(client.ServiceClient(fakeServer), fakeServer.Server.URL+"/page1", createPage), &requests
+}
+
+func TestPrefetchLinked(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	pager, requests := createCountedLinked(fakeServer, 5)
+	pager.Prefetch = 2
+
+	var actual []int
+	err := pager.EachPage(context.TODO(), func(_ context.Context, page pagination.Page) (bool, error) {
+		if len(actual) == 0 {
+			// the next pages are fetched while the first one is handled
+			deadline := time.Now().Add(5 * time.Second)
+			for requests.Load() < 3 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			th.AssertEquals(t, int32(3), requests.Load())
+		}
+		ints, err := ExtractLinkedInts(page)
+		actual = append(actual, ints...)
+		return true, err
+	})
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, []int{1, 2, 3, 4, 5}, actual)
+	th.CheckEquals(t, int32(5), requests.Load())
+}
+
+func TestPrefetchMarker(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	pager := createMarkerPaged(t, fakeServer)
+	pager.Prefetch = 1
+
+	var actual []string
+	for s, err := range pagination.Items(context.TODO(), pager, ExtractMarkerStrings) {
+		th.AssertNoErr(t, err)
+		actual = append(actual, s)
+	}
+	th.CheckDeepEquals(t, []string{"aaa", "bbb", "ccc", "ddd", "eee", "fff", "ggg", "hhh", "iii"}, actual)
+}
+
+func TestPrefetchSingle(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	pager := setupSinglePaged(fakeServer)
+	pager.Prefetch = 4
+
+	page, err := pager.AllPages(context.TODO())
+	th.AssertNoErr(t, err)
+	actual, err := ExtractSingleInts(page)
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, []int{1, 2, 3}, actual)
+}
+
+func TestPrefetchStopsEarly(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	pager, requests := createCountedLinked(fakeServer, 10)
+	pager.Prefetch = 1
+
+	var actual []int
+	err := pager.EachPage(context.TODO(), func(_ context.Context, page pagination.Page) (bool, error) {
+		ints, err := ExtractLinkedInts(page)
+		actual = append(actual, ints...)
+		return len(actual) < 2, err
+	})
+	th.AssertNoErr(t, err)
+	th.CheckDeepEquals(t, []int{1, 2}, actual)
+
+	// at most one page is fetched ahead of the handler
+	time.Sleep(10 * time.Millisecond)
+	if count := requests.Load(); count > 3 {
+		t.Fatalf("expected at most 3 requests, got %d", count)
+	}
+}
+
+func TestPrefetchError(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	fakeServer.Mux.HandleFunc("/page1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprintf(w, `{ "ints": [1], "links": { "next": "%s/page2" } }`, fakeServer.Server.URL)
+	})
+	fakeServer.Mux.HandleFunc("/page2", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	createPage := func(r pagination.PageResult) pagination.Page {
+		return LinkedPageResult{pagination.LinkedPageBase{PageResult: r}}
+	}
+	pager := pagination.NewPager(client.ServiceClient(fakeServer), fakeServer.Server.URL+"/page1", createPage)
+	pager.Prefetch = 3
+
+	var handled int
+	err := pager.EachPage(context.TODO(), func(_ context.Context, page pagination.Page) (bool, error) {
+		handled++
+		return true, nil
+	})
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusInternalServerError))
+	th.AssertEquals(t, 1, handled)
+}
+
+func TestPrefetchCancel(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+
+	pager, _ := createCountedLinked(fakeServer, 10)
+	pager.Prefetch = 2
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	var handled int
+	err := pager.EachPage(ctx, func(_ context.Context, page pagination.Page) (bool, error) {
+		handled++
+		if handled == 2 {
+			cancel()
+		}
+		return true, nil
+	})
+	th.AssertEquals(t, true, errors.Is(err, context.Canceled))
+	// prefetched pages are not handled after cancellation
+	th.AssertEquals(t, 2, handled)
+}
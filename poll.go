@@ -0,0 +1,203 @@
+package gophercloud
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+const (
+	// DefaultPollInterval is the delay between two polls of a Poller.
+	DefaultPollInterval = 1 * time.Second
+	// DefaultPollMaxInterval is the maximum delay between two polls of a
+	// Poller whose interval grows.
+	DefaultPollMaxInterval = 1 * time.Minute
+)
+
+// Poller polls a resource until it reaches a desired state. The zero value
+// is a valid Poller which polls once per second, until the context is done.
+//
+// The WaitFor function and the WaitFor helpers of the resource packages use
+// the Poller set in their context with WithPoller, so that they can be
+// configured without changing their signature:
+//
+//	ctx = gophercloud.WithPoller(ctx, &gophercloud.Poller{
+//		InitialInterval: 2 * time.Second,
+//		Multiplier:      1.5,
+//		MaxInterval:     30 * time.Second,
+//		Timeout:         10 * time.Minute,
+//		Progress: func(p gophercloud.PollProgress) {
+//			log.Printf("attempt %d: status %q", p.Attempt, p.Status)
+//		},
+//	})
+//	err := servers.WaitForStatus(ctx, client, id, "ACTIVE")
+type Poller struct {
+	// InitialInterval is the delay between the first and the second poll.
+	// Defaults to DefaultPollInterval.
+	InitialInterval time.Duration
+
+	// Multiplier is the factor by which the delay grows after each poll.
+	// Defaults to 1, i.e. a fixed interval.
+	Multiplier float64
+
+	// MaxInterval caps the delay between two polls when Multiplier is
+	// greater than 1. Defaults to DefaultPollMaxInterval.
+	MaxInterval time.Duration
+
+	// Jitter is the fraction by which each delay is randomized: a delay d is
+	// turned into a random delay between d*(1-Jitter) and d*(1+Jitter).
+	// Defaults to 0, i.e. no jitter.
+	Jitter float64
+
+	// Timeout, if set, limits the total time spent polling, in addition to
+	// the deadline of the context.
+	Timeout time.Duration
+
+	// Progress, if set, is called after each poll which did not end the
+	// polling.
+	Progress func(PollProgress)
+}
+
+// PollProgress describes the progress of a Poller. It is passed to the
+// Progress callback of a Poller.
+type PollProgress struct {
+	// Attempt is the number of polls done so far, starting at 1.
+	Attempt int
+
+	// Elapsed is the time elapsed since the first poll.
+	Elapsed time.Duration
+
+	// Status is the status of the resource reported by the last poll, if
+	// any.
+	Status string
+
+	// NextInterval is the delay before the next poll.
+	NextInterval time.Duration
+}
+
+// PollFunc checks the state of a polled resource. It returns true when the
+// resource reached the desired state, and the current status of the
+// resource, if it has one, for progress reporting. Polling stops as soon as
+// it returns an error.
+type PollFunc func(ctx context.Context) (done bool, status string, err error)
+
+type pollerKey struct{}
+
+// WithPoller returns a copy of ctx which carries the given Poller, to be used
+// by WaitFor and by the WaitFor helpers of the resource packages.
+func WithPoller(ctx context.Context, poller *Poller) context.Context {
+	return context.WithValue(ctx, pollerKey{}, poller)
+}
+
+// PollerFromContext returns the Poller set in ctx with WithPoller, or a
+// zero Poller if there is none.
+func PollerFromContext(ctx context.Context) *Poller {
+	if poller, ok := ctx.Value(pollerKey{}).(*Poller); ok && poller != nil {
+		return poller
+	}
+	return &Poller{}
+}
+
+// Poll calls check until it reports that the resource reached the desired
+// state, returns an error, or until ctx is done or the Timeout of the Poller
+// expires. In the latter cases, the error of the context is returned.
+func (p *Poller) Poll(ctx context.Context, check PollFunc) error {
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	interval := p.InitialInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	for attempt := 1; ; attempt++ {
+		done, status, err := check(ctx)
+		if done || err != nil {
+			return err
+		}
+
+		delay := p.jitter(interval)
+		if p.Progress != nil {
+			p.Progress(PollProgress{
+				Attempt:      attempt,
+				Elapsed:      time.Since(start),
+				Status:       status,
+				NextInterval: delay,
+			})
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+
+		interval = p.nextInterval(interval)
+	}
+}
+
+// nextInterval returns the interval which follows the given one.
+func (p *Poller) nextInterval(interval time.Duration) time.Duration {
+	if p.Multiplier <= 1 {
+		return interval
+	}
+	maxInterval := p.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultPollMaxInterval
+	}
+	next := time.Duration(float64(interval) * p.Multiplier)
+	if next > maxInterval {
+		return maxInterval
+	}
+	return next
+}
+
+// jitter randomizes the given delay.
+func (p *Poller) jitter(delay time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return delay
+	}
+	return time.Duration(float64(delay) * (1 + p.Jitter*(2*rand.Float64()-1)))
+}
+
+// ResourceStatus is the status of a polled resource, as returned by the get
+// function passed to WaitForStatus.
+type ResourceStatus struct {
+	// Status is the current status of the resource.
+	Status string
+
+	// Fault is the error message reported by the resource, like the fault
+	// of a server or the last_error of a node, if any.
+	Fault string
+}
+
+// WaitForStatus polls a resource with get, using the Poller set in ctx,
+// until its status is target. It returns an ErrResourceFailed error as soon
+// as the status of the resource is one of failureStatuses, since the
+// resource will then never reach the target status.
+func WaitForStatus(ctx context.Context, id, target string, failureStatuses []string, get func(context.Context) (ResourceStatus, error)) error {
+	return PollerFromContext(ctx).Poll(ctx, func(ctx context.Context) (bool, string, error) {
+		current, err := get(ctx)
+		if err != nil {
+			return false, "", err
+		}
+		if current.Status == target {
+			return true, current.Status, nil
+		}
+		if slices.Contains(failureStatuses, current.Status) {
+			return false, current.Status, ErrResourceFailed{
+				ID:     id,
+				Status: current.Status,
+				Fault:  current.Fault,
+			}
+		}
+		return false, current.Status, nil
+	})
+}
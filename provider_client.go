@@ -6,10 +6,12 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"log/slog"
 	"net/http"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 )
 
 // DefaultUserAgent is the default User-Agent string set in the request header.
@@ -25,6 +27,10 @@ type UserAgent struct {
 	prepend []string
 }
 
+// RetryBackoffFunc is called when a request is rate limited, with a 429 or
+// 498 response. If it returns nil, the request will be retried. It is not
+// called for a request whose RawBody cannot be rewound, which fails with the
+// rate limiting error instead.
 type RetryBackoffFunc func(context.Context, *ErrUnexpectedResponseCode, error, uint) error
 
 // RetryFunc is a catch-all function for retrying failed API requests.
@@ -98,6 +104,26 @@ type ProviderClient struct {
 	// to abort when an error is encountered.
 	RetryFunc RetryFunc
 
+	// Middleware is the chain of middleware that every request sent through
+	// this client passes through, in order. The first middleware is the
+	// outermost one. A ServiceClient may override it with its own chain.
+	Middleware []Middleware
+
+	// Logger, if set, is used to log the requests sent by this client, their
+	// responses, retries and reauthentication events. Requests and responses
+	// are logged at the Debug level, with credentials redacted from headers
+	// and bodies. Retries and reauthentication events are logged at the Info
+	// level.
+	Logger *slog.Logger
+
+	// TokenRenewalWindow, if set, makes the client renew its token with
+	// ReauthFunc before sending a request when the token expires within this
+	// window, instead of waiting for a 401 response. This avoids failing
+	// requests with a body which cannot be sent again, such as uploads from a
+	// pipe. The expiry of the token is read from the AuthResult, which must
+	// implement ExpiringAuthResult. See also StartTokenRenewal.
+	TokenRenewalWindow time.Duration
+
 	// mut is a mutex for the client. It protects read and write access to client attributes such as getting
 	// and setting the TokenID.
 	mut *sync.RWMutex
@@ -107,6 +133,9 @@ type ProviderClient struct {
 	reauthmut *reauthlock
 
 	authResult AuthResult
+
+	// tokenExpiresAt is the expiry of the current token, if known.
+	tokenExpiresAt time.Time
 }
 
 // reauthlock represents a set of attributes used to help in the reauthentication process.
@@ -204,6 +233,7 @@ func (client *ProviderClient) SetToken(t string) {
 	}
 	client.TokenID = t
 	client.authResult = nil
+	client.tokenExpiresAt = time.Time{}
 }
 
 // SetTokenAndAuthResult safely sets the value of the auth token in the
@@ -211,12 +241,18 @@ func (client *ProviderClient) SetToken(t string) {
 // token creation request. Applications may call this in a custom ReauthFunc.
 func (client *ProviderClient) SetTokenAndAuthResult(r AuthResult) error {
 	tokenID := ""
+	var expiresAt time.Time
 	var err error
 	if r != nil {
 		tokenID, err = r.ExtractTokenID()
 		if err != nil {
 			return err
 		}
+		if er, ok := r.(ExpiringAuthResult); ok {
+			// the expiry is only used to renew the token in advance, so a
+			// result which does not report it is not an error
+			expiresAt, _ = er.ExtractExpiresAt()
+		}
 	}
 
 	if client.mut != nil {
@@ -225,6 +261,7 @@ func (client *ProviderClient) SetTokenAndAuthResult(r AuthResult) error {
 	}
 	client.TokenID = tokenID
 	client.authResult = r
+	client.tokenExpiresAt = expiresAt
 	return nil
 }
 
@@ -241,6 +278,7 @@ func (client *ProviderClient) CopyTokenFrom(other *ProviderClient) {
 	}
 	client.TokenID = other.TokenID
 	client.authResult = other.authResult
+	client.tokenExpiresAt = other.tokenExpiresAt
 }
 
 // IsThrowaway safely reads the value of the client Throwaway field.
@@ -331,6 +369,9 @@ type RequestOpts struct {
 	// KeepResponseBody specifies whether to keep the HTTP response body. Usually used, when the HTTP
 	// response body is considered for further use. Valid when JSONResponse is nil.
 	KeepResponseBody bool
+	// Microversion, if provided, overrides the microversion of the ServiceClient and any
+	// microversion set in the context with WithMicroversion for this request only.
+	Microversion string
 }
 
 // requestState contains temporary state for a single ProviderClient.Request() call.
@@ -342,20 +383,31 @@ type requestState struct {
 	hasReauthenticated bool
 	// Retry-After backoff counter, increments during each backoff call
 	retries uint
+	// info is shared with the middleware chain and reflects the state of the request.
+	info *RequestInfo
+}
+
+// retried increments the retry counter of the request.
+func (state *requestState) retried() uint {
+	state.retries = state.retries + 1
+	if state.info != nil {
+		state.info.Retries = state.retries
+	}
+	return state.retries
 }
 
 var applicationJSON = "application/json"
 
 // Request performs an HTTP request using the ProviderClient's
 // current HTTPClient. An authentication header will automatically be provided.
+// The request passes through the client's Middleware, if any.
 func (client *ProviderClient) Request(ctx context.Context, method, url string, options *RequestOpts) (*http.Response, error) {
-	return client.doRequest(ctx, method, url, options, &requestState{
-		hasReauthenticated: false,
-	})
+	return client.requestWithMiddleware(ctx, method, url, options, &RequestInfo{}, client.Middleware)
 }
 
 func (client *ProviderClient) doRequest(ctx context.Context, method, url string, options *RequestOpts, state *requestState) (*http.Response, error) {
 	var body io.Reader
+	var rendered []byte
 	var contentType *string
 
 	// Derive the content body by either encoding an arbitrary object as JSON, or by taking a provided
@@ -365,7 +417,8 @@ func (client *ProviderClient) doRequest(ctx context.Context, method, url string,
 			return nil, errors.New("please provide only one of JSONBody or RawBody to gophercloud.Request()")
 		}
 
-		rendered, err := json.Marshal(options.JSONBody)
+		var err error
+		rendered, err = json.Marshal(options.JSONBody)
 		if err != nil {
 			return nil, err
 		}
@@ -405,10 +458,16 @@ func (client *ProviderClient) doRequest(ctx context.Context, method, url string,
 		}
 	}
 
+	if id := GlobalRequestIDFromContext(ctx); id != "" {
+		req.Header.Set(GlobalRequestIDHeader, id)
+	}
+
 	for _, v := range options.OmitHeaders {
 		req.Header.Del(v)
 	}
 
+	client.renewTokenIfExpiring(ctx)
+
 	// get latest token from client
 	for k, v := range client.AuthenticatedHeaders() {
 		req.Header.Set(k, v)
@@ -416,17 +475,21 @@ func (client *ProviderClient) doRequest(ctx context.Context, method, url string,
 
 	prereqtok := req.Header.Get("X-Auth-Token")
 
+	client.logRequest(ctx, req, rendered)
+
 	// Issue the request.
+	start := time.Now()
 	resp, err := client.HTTPClient.Do(req)
 	if err != nil {
+		client.logRequestError(ctx, req, start, state, err)
 		if client.RetryFunc != nil {
 			var e error
-			state.retries = state.retries + 1
-			e = client.RetryFunc(ctx, method, url, options, err, state.retries)
+			e = client.RetryFunc(ctx, method, url, options, err, state.retried())
 			if e != nil {
 				return nil, e
 			}
 
+			client.logEvent(ctx, "retrying request", method, url, slog.Uint64("retries", uint64(state.retries)), slog.String("error", err.Error()))
 			return client.doRequest(ctx, method, url, options, state)
 		}
 		return nil, err
@@ -442,6 +505,7 @@ func (client *ProviderClient) doRequest(ctx context.Context, method, url string,
 	if !slices.Contains(okc, resp.StatusCode) {
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
+		client.logResponse(ctx, req, resp, start, state, body)
 		respErr := ErrUnexpectedResponseCode{
 			URL:            url,
 			Method:         method,
@@ -449,11 +513,18 @@ func (client *ProviderClient) doRequest(ctx context.Context, method, url string,
 			Actual:         resp.StatusCode,
 			Body:           body,
 			ResponseHeader: resp.Header,
+			RequestID:      RequestIDFromHeader(resp.Header),
+		}
+		if state.info != nil {
+			respErr.Fault = ParseFault(state.info.ServiceType, body)
+		} else {
+			respErr.Fault = ParseFault("", body)
 		}
 
 		switch resp.StatusCode {
 		case http.StatusUnauthorized:
 			if client.ReauthFunc != nil && !state.hasReauthenticated {
+				client.logEvent(ctx, "reauthenticating", method, url)
 				err = client.Reauthenticate(ctx, prereqtok)
 				if err != nil {
 					e := &ErrUnableToReauthenticate{}
@@ -469,6 +540,9 @@ func (client *ProviderClient) doRequest(ctx context.Context, method, url string,
 					}
 				}
 				state.hasReauthenticated = true
+				if state.info != nil {
+					state.info.Reauthenticated = true
+				}
 				resp, err = client.doRequest(ctx, method, url, options, state)
 				if err != nil {
 					switch e := err.(type) {
@@ -491,15 +565,22 @@ func (client *ProviderClient) doRequest(ctx context.Context, method, url string,
 			}
 
 			if f := client.RetryBackoffFunc; f != nil && state.retries < maxTries {
+				// The request is sent again with the same body, so a
+				// raw body which cannot be rewound is not retried.
+				if !options.RewindBody() {
+					return resp, respErr
+				}
+
 				var e error
 
-				state.retries = state.retries + 1
-				e = f(ctx, &respErr, err, state.retries)
+				e = f(ctx, &respErr, err, state.retried())
 
 				if e != nil {
 					return resp, e
 				}
 
+				client.logEvent(ctx, "retrying request after backoff", method, url, slog.Uint64("retries", uint64(state.retries)), slog.Int("status", resp.StatusCode))
+
 				return client.doRequest(ctx, method, url, options, state)
 			}
 		}
@@ -510,12 +591,12 @@ func (client *ProviderClient) doRequest(ctx context.Context, method, url string,
 
 		if err != nil && client.RetryFunc != nil {
 			var e error
-			state.retries = state.retries + 1
-			e = client.RetryFunc(ctx, method, url, options, err, state.retries)
+			e = client.RetryFunc(ctx, method, url, options, err, state.retried())
 			if e != nil {
 				return resp, e
 			}
 
+			client.logEvent(ctx, "retrying request", method, url, slog.Uint64("retries", uint64(state.retries)), slog.String("error", err.Error()))
 			return client.doRequest(ctx, method, url, options, state)
 		}
 
@@ -527,15 +608,25 @@ func (client *ProviderClient) doRequest(ctx context.Context, method, url string,
 		defer resp.Body.Close()
 		// Don't decode JSON when there is no content
 		if resp.StatusCode == http.StatusNoContent {
+			client.logResponse(ctx, req, resp, start, state, nil)
 			// read till EOF, otherwise the connection will be closed and cannot be reused
 			_, err = io.Copy(io.Discard, resp.Body)
 			return resp, err
 		}
-		if err := json.NewDecoder(resp.Body).Decode(options.JSONResponse); err != nil {
+		var respBody io.Reader = resp.Body
+		var logged *bytes.Buffer
+		if client.logEnabled(ctx, slog.LevelDebug) {
+			logged = new(bytes.Buffer)
+			respBody = io.TeeReader(resp.Body, logged)
+		}
+		err := json.NewDecoder(respBody).Decode(options.JSONResponse)
+		if logged != nil {
+			client.logResponse(ctx, req, resp, start, state, logged.Bytes())
+		}
+		if err != nil {
 			if client.RetryFunc != nil {
 				var e error
-				state.retries = state.retries + 1
-				e = client.RetryFunc(ctx, method, url, options, err, state.retries)
+				e = client.RetryFunc(ctx, method, url, options, err, state.retried())
 				if e != nil {
 					return resp, e
 				}
@@ -546,6 +637,10 @@ func (client *ProviderClient) doRequest(ctx context.Context, method, url string,
 		}
 	}
 
+	if options.JSONResponse == nil {
+		client.logResponse(ctx, req, resp, start, state, nil)
+	}
+
 	// Close unused body to allow the HTTP connection to be reused
 	if !options.KeepResponseBody && options.JSONResponse == nil {
 		defer resp.Body.Close()
@@ -558,6 +653,21 @@ func (client *ProviderClient) doRequest(ctx context.Context, method, url string,
 	return resp, nil
 }
 
+// RewindBody rewinds the RawBody of the options, if any, so that the request
+// can be sent again. It returns false if RawBody is not an io.Seeker or
+// cannot be rewound.
+func (opts *RequestOpts) RewindBody() bool {
+	if opts == nil || opts.RawBody == nil {
+		return true
+	}
+	seeker, ok := opts.RawBody.(io.Seeker)
+	if !ok {
+		return false
+	}
+	_, err := seeker.Seek(0, io.SeekStart)
+	return err == nil
+}
+
 func defaultOkCodes(method string) []int {
 	switch method {
 	case "GET", "HEAD":
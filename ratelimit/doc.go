@@ -0,0 +1,25 @@
+/*
+Package ratelimit provides a client-side token bucket rate limiter for
+requests sent by a gophercloud.ProviderClient.
+
+Limits are configured per service type (e.g. compute or network) or per
+endpoint host. The Limiter is installed as a gophercloud.Middleware, so it
+applies to every request sent through the client, including the requests
+issued by pagination.Pager and by the WaitFor polling helpers. A request which
+exceeds the limit blocks until a token is available or until its context is
+cancelled. The retries sent by the client within a request, after a rate
+limited response or a reauthentication, are not throttled again.
+
+Example of limiting Nova to 5 requests per second, Neutron to 10 requests per
+second with bursts of 20, and every other endpoint to 20 requests per second:
+
+	limiter := &ratelimit.Limiter{
+		ByServiceType: map[string]ratelimit.Limit{
+			"compute": {Rate: 5},
+			"network": {Rate: 10, Burst: 20},
+		},
+		Default: &ratelimit.Limit{Rate: 20},
+	}
+	limiter.Apply(provider)
+*/
+package ratelimit
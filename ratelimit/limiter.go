@@ -0,0 +1,213 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// Limit describes the rate at which requests may be sent.
+type Limit struct {
+	// Rate is the number of requests per second that may be sent. A zero or
+	// negative rate means no limit.
+	Rate float64
+
+	// Burst is the maximum number of requests that may be sent at once,
+	// before the rate applies. Defaults to 1.
+	Burst int
+}
+
+// Limiter limits the rate of the requests sent by a ProviderClient. The limit
+// applied to a request is looked up by the host of the request URL in
+// ByHost, then by the type of the service client which issued it in
+// ByServiceType, and finally falls back to Default. Requests which match no
+// limit are not throttled.
+//
+// A Limiter must not be copied after first use. Its limits must not be
+// modified once it is in use.
+type Limiter struct {
+	// ByHost maps endpoint hosts (as in url.URL.Host, e.g.
+	// "compute.example.com:8774") to their limit.
+	ByHost map[string]Limit
+
+	// ByServiceType maps service types (e.g. compute, network) to their
+	// limit. All the endpoints of a service type share the same bucket,
+	// and so do the aliases of a service type in
+	// gophercloud.ServiceTypeAliases, like volumev3 and block-storage.
+	ByServiceType map[string]Limit
+
+	// Default is the limit applied to the requests which match no other
+	// limit. Each endpoint host gets its own bucket.
+	Default *Limit
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// Apply installs the limiter as the outermost middleware of the client.
+// Service clients which override the provider's Middleware must include
+// l.Middleware in their own chain to be throttled.
+//
+// The limiter throttles each call of ProviderClient.Request once: the
+// retries the client sends within the call, after a rate limited (429 or
+// 498) response or after reauthenticating, are not throttled again. The
+// RetryBackoffFunc of the client paces the rate limited retries.
+func (l *Limiter) Apply(client *gophercloud.ProviderClient) {
+	client.Middleware = append([]gophercloud.Middleware{l.Middleware}, client.Middleware...)
+}
+
+// Middleware implements gophercloud.Middleware. It blocks each request until
+// it is allowed by the limit, or until its context is cancelled.
+func (l *Limiter) Middleware(next gophercloud.RequestHandler) gophercloud.RequestHandler {
+	return func(ctx context.Context, method, rawURL string, options *gophercloud.RequestOpts) (*http.Response, error) {
+		var serviceType string
+		if info := gophercloud.RequestInfoFromContext(ctx); info != nil {
+			serviceType = info.ServiceType
+		}
+		var host string
+		if u, err := url.Parse(rawURL); err == nil {
+			host = u.Host
+		}
+
+		if err := l.Wait(ctx, serviceType, host); err != nil {
+			return nil, err
+		}
+		return next(ctx, method, rawURL, options)
+	}
+}
+
+// Wait blocks until a request to the given service type and endpoint host is
+// allowed, or until ctx is cancelled, in which case it returns the context
+// error.
+func (l *Limiter) Wait(ctx context.Context, serviceType, host string) error {
+	b := l.bucketFor(serviceType, host)
+	if b == nil {
+		return nil
+	}
+
+	delay := b.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
+
+// bucketFor returns the bucket that applies to the given service type and
+// endpoint host, or nil if no limit applies.
+func (l *Limiter) bucketFor(serviceType, host string) *bucket {
+	var key string
+	var limit Limit
+	if lim, ok := l.ByHost[host]; ok && host != "" {
+		key, limit = "host:"+host, lim
+	} else if t, lim, ok := l.serviceTypeLimit(serviceType); ok {
+		key, limit = "type:"+t, lim
+	} else if l.Default != nil {
+		key, limit = "default:"+host, *l.Default
+	} else {
+		return nil
+	}
+
+	if limit.Rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.buckets == nil {
+		l.buckets = make(map[string]*bucket)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = newBucket(limit)
+		l.buckets[key] = b
+	}
+	return b
+}
+
+// serviceTypeLimit returns the official type of the given service type and
+// the limit configured for it, under its own name or under any of its
+// aliases.
+func (l *Limiter) serviceTypeLimit(serviceType string) (string, Limit, bool) {
+	if serviceType == "" {
+		return "", Limit{}, false
+	}
+	official := gophercloud.OfficialServiceType(serviceType)
+	if lim, ok := l.ByServiceType[serviceType]; ok {
+		return official, lim, true
+	}
+	if lim, ok := l.ByServiceType[official]; ok {
+		return official, lim, true
+	}
+	for _, alias := range gophercloud.ServiceTypeAliases[official] {
+		if lim, ok := l.ByServiceType[alias]; ok {
+			return official, lim, true
+		}
+	}
+	return "", Limit{}, false
+}
+
+// bucket is a token bucket. Tokens may go negative, in which case the
+// caller which took the last token must wait for it to be refilled.
+type bucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(limit Limit) *bucket {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &bucket{
+		rate:   limit.Rate,
+		burst:  burst,
+		tokens: burst,
+	}
+}
+
+// reserve takes a token from the bucket and returns how long the caller must
+// wait before using it.
+func (b *bucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel gives back a token which was reserved but not used.
+func (b *bucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
@@ -0,0 +1,110 @@
+package testing
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/ratelimit"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+func TestLimiterNoLimit(t *testing.T) {
+	l := &ratelimit.Limiter{}
+	start := time.Now()
+	for range 100 {
+		th.AssertNoErr(t, l.Wait(context.TODO(), "compute", "example.com"))
+	}
+	th.AssertEquals(t, true, time.Since(start) < 100*time.Millisecond)
+}
+
+func TestLimiterBurst(t *testing.T) {
+	l := &ratelimit.Limiter{
+		ByServiceType: map[string]ratelimit.Limit{
+			"compute": {Rate: 20, Burst: 5},
+		},
+	}
+
+	start := time.Now()
+	for range 5 {
+		th.AssertNoErr(t, l.Wait(context.TODO(), "compute", "example.com"))
+	}
+	th.AssertEquals(t, true, time.Since(start) < 25*time.Millisecond)
+
+	// the sixth request must wait for a token to be refilled
+	th.AssertNoErr(t, l.Wait(context.TODO(), "compute", "example.com"))
+	th.AssertEquals(t, true, time.Since(start) >= 40*time.Millisecond)
+
+	// other services are not limited
+	start = time.Now()
+	for range 10 {
+		th.AssertNoErr(t, l.Wait(context.TODO(), "network", "example.com"))
+	}
+	th.AssertEquals(t, true, time.Since(start) < 25*time.Millisecond)
+}
+
+func TestLimiterByHost(t *testing.T) {
+	l := &ratelimit.Limiter{
+		ByHost: map[string]ratelimit.Limit{
+			"slow.example.com": {Rate: 1},
+		},
+		ByServiceType: map[string]ratelimit.Limit{
+			"compute": {Rate: 1000, Burst: 1000},
+		},
+	}
+
+	th.AssertNoErr(t, l.Wait(context.TODO(), "compute", "slow.example.com"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := l.Wait(ctx, "compute", "slow.example.com")
+	th.AssertErrIs(t, err, context.DeadlineExceeded)
+
+	th.AssertNoErr(t, l.Wait(context.TODO(), "compute", "fast.example.com"))
+}
+
+func TestLimiterServiceTypeAliases(t *testing.T) {
+	l := &ratelimit.Limiter{
+		ByServiceType: map[string]ratelimit.Limit{
+			"volumev3": {Rate: 1},
+		},
+	}
+	th.AssertNoErr(t, l.Wait(context.TODO(), "block-storage", "a.example.com"))
+
+	// the aliases of the service type share its bucket
+	for _, serviceType := range []string{"volumev3", "block-store"} {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		err := l.Wait(ctx, serviceType, "b.example.com")
+		cancel()
+		th.AssertErrIs(t, err, context.DeadlineExceeded)
+	}
+}
+
+func TestLimiterMiddleware(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	var calls atomic.Int32
+	fakeServer.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	p := &gophercloud.ProviderClient{}
+	l := &ratelimit.Limiter{
+		Default: &ratelimit.Limit{Rate: 1},
+	}
+	l.Apply(p)
+	sc := &gophercloud.ServiceClient{ProviderClient: p, Type: "compute"}
+
+	_, err := sc.Get(context.TODO(), fakeServer.Endpoint()+"route", nil, nil)
+	th.AssertNoErr(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = sc.Get(ctx, fakeServer.Endpoint()+"route", nil, nil)
+	th.AssertErrIs(t, err, context.DeadlineExceeded)
+	th.AssertEquals(t, int32(1), calls.Load())
+}
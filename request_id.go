@@ -0,0 +1,63 @@
+package gophercloud
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gophercloud/gophercloud/v2/internal/uuid"
+)
+
+// requestIDHeaders lists the response headers in which OpenStack services
+// report the ID they assigned to a request, in order of preference.
+var requestIDHeaders = []string{
+	"X-Openstack-Request-Id",
+	"X-Compute-Request-Id",
+}
+
+// GlobalRequestIDHeader is the request header used to propagate a global
+// request ID to OpenStack services.
+const GlobalRequestIDHeader = "X-OpenStack-Request-ID"
+
+// RequestIDFromHeader returns the ID assigned to a request by an OpenStack
+// service, as reported in the headers of its response. It returns an empty
+// string if the service did not report one.
+func RequestIDFromHeader(header http.Header) string {
+	for _, name := range requestIDHeaders {
+		if id := header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+type globalRequestIDKey struct{}
+
+// WithGlobalRequestID returns a copy of ctx which causes every request sent
+// with it to carry the given global request ID. OpenStack services log the
+// global request ID alongside their own request ID, which allows a single
+// logical operation to be traced across services.
+//
+// OpenStack services only accept global request IDs of the form
+// "req-<UUID>"; use NewGlobalRequestID to generate one.
+//
+// Example:
+//
+//	ctx := gophercloud.WithGlobalRequestID(context.TODO(), gophercloud.NewGlobalRequestID())
+//	server, err := servers.Create(ctx, computeClient, createOpts, nil).Extract()
+//	volume, err := volumes.Create(ctx, blockStorageClient, volumeOpts, nil).Extract()
+func WithGlobalRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, globalRequestIDKey{}, id)
+}
+
+// GlobalRequestIDFromContext returns the global request ID set on ctx with
+// WithGlobalRequestID, or an empty string if none was set.
+func GlobalRequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(globalRequestIDKey{}).(string)
+	return id
+}
+
+// NewGlobalRequestID generates a random global request ID in the format
+// expected by OpenStack services.
+func NewGlobalRequestID() string {
+	return "req-" + uuid.New()
+}
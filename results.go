@@ -3,6 +3,7 @@ package gophercloud
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -43,6 +44,21 @@ type Result struct {
 	Err error
 }
 
+// RequestID returns the ID assigned to the request by the OpenStack service,
+// as reported in the X-Openstack-Request-Id or X-Compute-Request-Id response
+// header. If the request failed, the ID is taken from the error. It returns an
+// empty string if the service did not report one.
+func (r Result) RequestID() string {
+	if id := RequestIDFromHeader(r.Header); id != "" {
+		return id
+	}
+	var codeError ErrUnexpectedResponseCode
+	if errors.As(r.Err, &codeError) {
+		return codeError.RequestID
+	}
+	return ""
+}
+
 // ExtractInto allows users to provide an object into which `Extract` will extract
 // the `Result.Body`. This would be useful for OpenStack providers that have
 // different fields in the response object than OpenStack proper.
@@ -240,6 +256,45 @@ func (r Result) ExtractIntoSlicePtr(to any, label string) error {
 	}
 }
 
+// ExtractAs unmarshals the body of a Result into a new value of type T and
+// returns it. If provided, label is the key of the body under which the value
+// is nested, like "server" in {"server": {...}}. It is the generic equivalent
+// of ExtractIntoStructPtr, with the same decoding rules, so that T can use the
+// time types of this package and embed extension structs.
+//
+// It can be used to extract the result of requests sent directly with a
+// ServiceClient, for APIs which are not supported by Gophercloud:
+//
+//	var r gophercloud.Result
+//	resp, err := client.Get(ctx, client.ServiceURL("things", id), &r.Body, nil)
+//	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+//	thing, err := gophercloud.ExtractAs[Thing](r, "thing")
+func ExtractAs[T any](r Result, label string) (*T, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	var v T
+	if err := r.extractIntoPtr(&v, label); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ExtractSliceAs unmarshals the body of a Result into a new slice of T and
+// returns it. If provided, label is the key of the body under which the
+// slice is nested, like "servers" in {"servers": [...]}. It is the generic
+// equivalent of ExtractIntoSlicePtr.
+func ExtractSliceAs[T any](r Result, label string) ([]T, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	var v []T
+	if err := r.extractIntoPtr(&v, label); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
 // PrettyPrintJSON creates a string containing the full response body as
 // pretty-printed JSON. It's useful for capturing test fixtures and for
 // debugging extraction bugs. If you include its output in an issue related to
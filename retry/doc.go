@@ -0,0 +1,34 @@
+/*
+Package retry provides configurable retry policies that plug into the
+RetryFunc and RetryBackoffFunc hooks of a gophercloud.ProviderClient.
+
+A Policy retries failed requests with an exponential backoff and jitter, up to
+a maximum number of retries or a maximum elapsed time. Server errors (500,
+502, 503 and 504) and connection resets are only retried for idempotent HTTP
+methods, so that a POST which may have been processed by the server is never
+sent twice. Rate limited requests (429) are retried for every method, since
+the server did not process them. When the server sends a Retry-After header,
+either in its delay-seconds or HTTP-date form, it takes precedence over the
+computed backoff.
+
+Example of applying the default policy to a client:
+
+	provider, err := openstack.AuthenticatedClient(ctx, opts)
+	if err != nil {
+		panic(err)
+	}
+	policy := &retry.Policy{}
+	policy.Apply(provider)
+
+Example of a custom policy:
+
+	policy := &retry.Policy{
+		InitialInterval: 200 * time.Millisecond,
+		MaxInterval:     10 * time.Second,
+		MaxElapsedTime:  time.Minute,
+		MaxRetries:      10,
+	}
+	provider.RetryFunc = policy.RetryFunc
+	provider.RetryBackoffFunc = policy.RetryBackoffFunc
+*/
+package retry
@@ -0,0 +1,266 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+const (
+	// DefaultInitialInterval is the delay before the first retry.
+	DefaultInitialInterval = 1 * time.Second
+	// DefaultMaxInterval is the maximum delay between two retries.
+	DefaultMaxInterval = 30 * time.Second
+	// DefaultMultiplier is the factor by which the delay grows after each retry.
+	DefaultMultiplier = 2.0
+	// DefaultJitter is the fraction by which each delay is randomized.
+	DefaultJitter = 0.2
+	// DefaultMaxElapsedTime is the maximum time spent on a request, retries included.
+	DefaultMaxElapsedTime = 5 * time.Minute
+	// DefaultMaxRetries is the maximum number of retries of a request.
+	DefaultMaxRetries = 10
+)
+
+// DefaultIdempotentMethods are the HTTP methods which are retried after a
+// server error or a connection reset.
+var DefaultIdempotentMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodPut,
+	http.MethodDelete,
+}
+
+// DefaultRetryableStatusCodes are the HTTP response codes which are retried
+// for idempotent methods.
+var DefaultRetryableStatusCodes = []int{
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// Policy is a retry policy with exponential backoff and jitter. The zero
+// value is a valid policy which uses the defaults documented on each field.
+type Policy struct {
+	// InitialInterval is the delay before the first retry. Defaults to
+	// DefaultInitialInterval.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the delay between two retries. It does not apply to
+	// delays requested by the server with a Retry-After header. Defaults to
+	// DefaultMaxInterval.
+	MaxInterval time.Duration
+
+	// Multiplier is the factor by which the delay grows after each retry.
+	// Defaults to DefaultMultiplier.
+	Multiplier float64
+
+	// Jitter is the fraction by which each delay is randomized: a delay d is
+	// turned into a random delay between d*(1-Jitter) and d*(1+Jitter).
+	// Defaults to DefaultJitter. Set it to a negative value to disable
+	// jitter.
+	Jitter float64
+
+	// MaxElapsedTime is the maximum time spent on a request, retries
+	// included. A retry which would end after this time is not attempted.
+	// Defaults to DefaultMaxElapsedTime.
+	MaxElapsedTime time.Duration
+
+	// MaxRetries is the maximum number of retries of a request. Defaults to
+	// DefaultMaxRetries.
+	MaxRetries uint
+
+	// IdempotentMethods are the HTTP methods which are retried after a
+	// server error or a connection reset. Defaults to
+	// DefaultIdempotentMethods.
+	IdempotentMethods []string
+
+	// RetryableStatusCodes are the HTTP response codes which are retried for
+	// idempotent methods. Defaults to DefaultRetryableStatusCodes.
+	RetryableStatusCodes []int
+}
+
+// Apply sets the policy as the RetryFunc and RetryBackoffFunc of the client.
+func (p *Policy) Apply(client *gophercloud.ProviderClient) {
+	client.RetryFunc = p.RetryFunc
+	client.RetryBackoffFunc = p.RetryBackoffFunc
+}
+
+// RetryFunc implements gophercloud.RetryFunc. It waits for the backoff delay
+// and returns nil if the request should be retried, or returns err
+// otherwise.
+func (p *Policy) RetryFunc(ctx context.Context, method, url string, options *gophercloud.RequestOpts, err error, failCount uint) error {
+	if !p.shouldRetry(method, err) || !options.RewindBody() {
+		return err
+	}
+	return p.wait(ctx, err, failCount)
+}
+
+// RetryBackoffFunc implements gophercloud.RetryBackoffFunc, which is called
+// when a request is rate limited. It waits for the delay requested by the
+// server, or for the backoff delay, and returns nil if the request should be
+// retried. The client does not call it for a request whose raw body cannot
+// be rewound.
+func (p *Policy) RetryBackoffFunc(ctx context.Context, respErr *gophercloud.ErrUnexpectedResponseCode, err error, failCount uint) error {
+	if respErr == nil {
+		return err
+	}
+	return p.wait(ctx, *respErr, failCount)
+}
+
+// Backoff returns the delay before the given retry, starting at 1, without
+// jitter.
+func (p *Policy) Backoff(retry uint) time.Duration {
+	initial := p.InitialInterval
+	if initial == 0 {
+		initial = DefaultInitialInterval
+	}
+	maxInterval := p.MaxInterval
+	if maxInterval == 0 {
+		maxInterval = DefaultMaxInterval
+	}
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = DefaultMultiplier
+	}
+
+	if retry == 0 {
+		retry = 1
+	}
+	delay := float64(initial) * math.Pow(multiplier, float64(retry-1))
+	if delay > float64(maxInterval) {
+		return maxInterval
+	}
+	return time.Duration(delay)
+}
+
+// jitter randomizes the given delay.
+func (p *Policy) jitter(delay time.Duration) time.Duration {
+	jitter := p.Jitter
+	if jitter == 0 {
+		jitter = DefaultJitter
+	}
+	if jitter < 0 {
+		return delay
+	}
+	return time.Duration(float64(delay) * (1 + jitter*(2*rand.Float64()-1)))
+}
+
+// wait sleeps before a retry, or returns err if the request should not be
+// retried anymore.
+func (p *Policy) wait(ctx context.Context, err error, failCount uint) error {
+	maxRetries := p.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = DefaultMaxRetries
+	}
+	if failCount > maxRetries {
+		return err
+	}
+
+	var delay time.Duration
+	var codeError gophercloud.ErrUnexpectedResponseCode
+	if errors.As(err, &codeError) {
+		delay, _ = ParseRetryAfter(codeError.ResponseHeader.Get("Retry-After"), time.Now())
+	}
+	if delay == 0 {
+		delay = p.jitter(p.Backoff(failCount))
+	}
+
+	maxElapsed := p.MaxElapsedTime
+	if maxElapsed == 0 {
+		maxElapsed = DefaultMaxElapsedTime
+	}
+	if info := gophercloud.RequestInfoFromContext(ctx); info != nil && !info.StartTime.IsZero() {
+		if time.Since(info.StartTime)+delay > maxElapsed {
+			return err
+		}
+	} else if delay > maxElapsed {
+		return err
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// shouldRetry reports whether a request with the given method which failed
+// with err may be retried.
+func (p *Policy) shouldRetry(method string, err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	idempotent := p.IdempotentMethods
+	if idempotent == nil {
+		idempotent = DefaultIdempotentMethods
+	}
+	isIdempotent := slices.Contains(idempotent, strings.ToUpper(method))
+
+	var codeError gophercloud.ErrUnexpectedResponseCode
+	if errors.As(err, &codeError) {
+		// The server did not process a rate limited request, so it can
+		// be retried whatever the method.
+		if codeError.Actual == http.StatusTooManyRequests {
+			return true
+		}
+		codes := p.RetryableStatusCodes
+		if codes == nil {
+			codes = DefaultRetryableStatusCodes
+		}
+		return isIdempotent && slices.Contains(codes, codeError.Actual)
+	}
+
+	return isIdempotent && isConnectionReset(err)
+}
+
+// isConnectionReset reports whether err is caused by the connection being
+// closed by the server.
+func isConnectionReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, either in its
+// delay-seconds or HTTP-date form, and returns the delay it requests
+// relative to now. It returns false if the value cannot be parsed.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	delay := date.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+	return delay, true
+}
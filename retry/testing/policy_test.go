@@ -0,0 +1,220 @@
+package testing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/retry"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+func testPolicy() *retry.Policy {
+	return &retry.Policy{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     10 * time.Millisecond,
+		MaxRetries:      3,
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	delay, ok := retry.ParseRetryAfter("120", now)
+	th.AssertEquals(t, true, ok)
+	th.AssertEquals(t, 120*time.Second, delay)
+
+	delay, ok = retry.ParseRetryAfter("Mon, 01 Jan 2024 12:00:30 GMT", now)
+	th.AssertEquals(t, true, ok)
+	th.AssertEquals(t, 30*time.Second, delay)
+
+	delay, ok = retry.ParseRetryAfter("Mon, 01 Jan 2024 11:00:00 GMT", now)
+	th.AssertEquals(t, true, ok)
+	th.AssertEquals(t, time.Duration(0), delay)
+
+	_, ok = retry.ParseRetryAfter("soon", now)
+	th.AssertEquals(t, false, ok)
+
+	_, ok = retry.ParseRetryAfter("-1", now)
+	th.AssertEquals(t, false, ok)
+
+	_, ok = retry.ParseRetryAfter("", now)
+	th.AssertEquals(t, false, ok)
+}
+
+func TestBackoff(t *testing.T) {
+	p := &retry.Policy{
+		InitialInterval: 100 * time.Millisecond,
+		MaxInterval:     time.Second,
+	}
+	th.AssertEquals(t, 100*time.Millisecond, p.Backoff(1))
+	th.AssertEquals(t, 200*time.Millisecond, p.Backoff(2))
+	th.AssertEquals(t, 400*time.Millisecond, p.Backoff(3))
+	th.AssertEquals(t, 800*time.Millisecond, p.Backoff(4))
+	th.AssertEquals(t, time.Second, p.Backoff(5))
+	th.AssertEquals(t, time.Second, p.Backoff(50))
+}
+
+func setupFlakyServer(t *testing.T, failures int32, status int, header map[string]string) (th.FakeServer, *atomic.Int32) {
+	fakeServer := th.SetupHTTP()
+	var calls atomic.Int32
+	fakeServer.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			for k, v := range header {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+	})
+	return fakeServer, &calls
+}
+
+func TestRetryIdempotentServerError(t *testing.T) {
+	fakeServer, calls := setupFlakyServer(t, 2, http.StatusServiceUnavailable, nil)
+	defer fakeServer.Teardown()
+
+	p := &gophercloud.ProviderClient{}
+	testPolicy().Apply(p)
+
+	_, err := p.Request(context.TODO(), "GET", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, int32(3), calls.Load())
+}
+
+func TestNoRetryNonIdempotentServerError(t *testing.T) {
+	fakeServer, calls := setupFlakyServer(t, 2, http.StatusBadGateway, nil)
+	defer fakeServer.Teardown()
+
+	p := &gophercloud.ProviderClient{}
+	testPolicy().Apply(p)
+
+	_, err := p.Request(context.TODO(), "POST", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{
+		JSONBody: map[string]string{"foo": "bar"},
+		OkCodes:  []int{200},
+	})
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusBadGateway))
+	th.AssertEquals(t, int32(1), calls.Load())
+}
+
+func TestRetryRateLimitedPost(t *testing.T) {
+	fakeServer, calls := setupFlakyServer(t, 2, http.StatusTooManyRequests, map[string]string{"Retry-After": "0"})
+	defer fakeServer.Teardown()
+
+	p := &gophercloud.ProviderClient{}
+	testPolicy().Apply(p)
+
+	_, err := p.Request(context.TODO(), "POST", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{
+		JSONBody: map[string]string{"foo": "bar"},
+		OkCodes:  []int{200},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, int32(3), calls.Load())
+}
+
+func TestRetryMaxRetries(t *testing.T) {
+	fakeServer, calls := setupFlakyServer(t, 100, http.StatusInternalServerError, nil)
+	defer fakeServer.Teardown()
+
+	p := &gophercloud.ProviderClient{}
+	testPolicy().Apply(p)
+
+	_, err := p.Request(context.TODO(), "GET", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{})
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusInternalServerError))
+	th.AssertEquals(t, int32(4), calls.Load())
+}
+
+func TestRetryMaxElapsedTime(t *testing.T) {
+	fakeServer, calls := setupFlakyServer(t, 100, http.StatusServiceUnavailable, map[string]string{"Retry-After": "60"})
+	defer fakeServer.Teardown()
+
+	p := &gophercloud.ProviderClient{}
+	policy := testPolicy()
+	policy.MaxElapsedTime = time.Second
+	policy.Apply(p)
+
+	start := time.Now()
+	_, err := p.Request(context.TODO(), "GET", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{})
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusServiceUnavailable))
+	th.AssertEquals(t, int32(1), calls.Load())
+	th.AssertEquals(t, true, time.Since(start) < time.Second)
+}
+
+func TestRetryRespectsContext(t *testing.T) {
+	fakeServer, _ := setupFlakyServer(t, 100, http.StatusServiceUnavailable, map[string]string{"Retry-After": "60"})
+	defer fakeServer.Teardown()
+
+	p := &gophercloud.ProviderClient{}
+	testPolicy().Apply(p)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := p.Request(ctx, "GET", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{})
+	th.AssertErrIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNoRetryUnseekableBody(t *testing.T) {
+	fakeServer, calls := setupFlakyServer(t, 2, http.StatusServiceUnavailable, nil)
+	defer fakeServer.Teardown()
+
+	p := &gophercloud.ProviderClient{}
+	testPolicy().Apply(p)
+
+	body := io.MultiReader(strings.NewReader("data"))
+	_, err := p.Request(context.TODO(), "PUT", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{
+		RawBody: body,
+		OkCodes: []int{200},
+	})
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusServiceUnavailable))
+	th.AssertEquals(t, int32(1), calls.Load())
+
+	calls.Store(0)
+	_, err = p.Request(context.TODO(), "PUT", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{
+		RawBody: strings.NewReader("data"),
+		OkCodes: []int{200},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, int32(3), calls.Load())
+}
+
+func TestRetryRateLimitedRawBody(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	var bodies []string
+	fakeServer.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		th.AssertNoErr(t, err)
+		bodies = append(bodies, string(b))
+		if len(bodies) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	p := &gophercloud.ProviderClient{}
+	testPolicy().Apply(p)
+
+	_, err := p.Request(context.TODO(), "POST", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{
+		RawBody: strings.NewReader("data"),
+		OkCodes: []int{200},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, []string{"data", "data"}, bodies)
+
+	bodies = nil
+	_, err = p.Request(context.TODO(), "POST", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{
+		RawBody: io.MultiReader(strings.NewReader("data")),
+		OkCodes: []int{200},
+	})
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusTooManyRequests))
+	th.AssertDeepEquals(t, []string{"data"}, bodies)
+}
@@ -28,11 +28,18 @@ type ServiceClient struct {
 	Type string
 
 	// The microversion of the service to use. Set this to use a particular microversion.
+	// It can be overridden for a single request with WithMicroversion or
+	// RequestOpts.Microversion.
 	Microversion string
 
 	// MoreHeaders allows users (or Gophercloud) to set service-wide headers on requests. Put another way,
 	// values set in this field will be set on all the HTTP requests the service client sends.
 	MoreHeaders map[string]string
+
+	// Middleware, if not nil, replaces the ProviderClient's Middleware for the
+	// requests sent through this service client. Set it to an empty slice to
+	// disable the provider's middleware for this service.
+	Middleware []Middleware
 }
 
 // ResourceBaseURL returns the base URL of any resources used by this service. It MUST end with a /.
@@ -114,29 +121,29 @@ func (client *ServiceClient) Head(ctx context.Context, url string, opts *Request
 	return client.Request(ctx, "HEAD", url, opts)
 }
 
-func (client *ServiceClient) setMicroversionHeader(opts *RequestOpts) {
+func (client *ServiceClient) setMicroversionHeader(opts *RequestOpts, microversion string) {
 	serviceType := client.Type
 
 	switch client.Type {
 	case "compute":
-		opts.MoreHeaders["X-OpenStack-Nova-API-Version"] = client.Microversion
+		opts.MoreHeaders["X-OpenStack-Nova-API-Version"] = microversion
 	case "shared-file-system", "sharev2", "share":
-		opts.MoreHeaders["X-OpenStack-Manila-API-Version"] = client.Microversion
+		opts.MoreHeaders["X-OpenStack-Manila-API-Version"] = microversion
 	case "block-storage", "block-store", "volume", "volumev3":
-		opts.MoreHeaders["X-OpenStack-Volume-API-Version"] = client.Microversion
+		opts.MoreHeaders["X-OpenStack-Volume-API-Version"] = microversion
 		// cinder should accept block-storage but (as of Dalmatian) does not
 		serviceType = "volume"
 	case "baremetal":
-		opts.MoreHeaders["X-OpenStack-Ironic-API-Version"] = client.Microversion
+		opts.MoreHeaders["X-OpenStack-Ironic-API-Version"] = microversion
 	case "baremetal-introspection":
-		opts.MoreHeaders["X-OpenStack-Ironic-Inspector-API-Version"] = client.Microversion
+		opts.MoreHeaders["X-OpenStack-Ironic-Inspector-API-Version"] = microversion
 	case "container-infrastructure-management", "container-infrastructure", "container-infra":
 		// magnum should accept container-infrastructure-management but (as of Epoxy) does not
 		serviceType = "container-infra"
 	}
 
 	if client.Type != "" {
-		opts.MoreHeaders["OpenStack-API-Version"] = serviceType + " " + client.Microversion
+		opts.MoreHeaders["OpenStack-API-Version"] = serviceType + " " + microversion
 	}
 }
 
@@ -146,8 +153,9 @@ func (client *ServiceClient) Request(ctx context.Context, method, url string, op
 		options.MoreHeaders = make(map[string]string)
 	}
 
-	if client.Microversion != "" {
-		client.setMicroversionHeader(options)
+	microversion := client.microversion(ctx, options)
+	if microversion != "" {
+		client.setMicroversionHeader(options, microversion)
 	}
 
 	if len(client.MoreHeaders) > 0 {
@@ -159,7 +167,17 @@ func (client *ServiceClient) Request(ctx context.Context, method, url string, op
 			options.MoreHeaders[k] = v
 		}
 	}
-	return client.ProviderClient.Request(ctx, method, url, options)
+
+	middleware := client.ProviderClient.Middleware
+	if client.Middleware != nil {
+		middleware = client.Middleware
+	}
+
+	info := &RequestInfo{
+		ServiceType:  client.Type,
+		Microversion: microversion,
+	}
+	return client.ProviderClient.requestWithMiddleware(ctx, method, url, options, info, middleware)
 }
 
 // ParseResponse is a helper function to parse http.Response to constituents.
@@ -0,0 +1,304 @@
+/*
+Package cassette records the HTTP traffic of a ProviderClient to a file, a
+cassette, and replays it later without a cloud.
+
+In record mode, a Recorder forwards the requests to the cloud and appends each
+interaction to the cassette, after scrubbing the tokens, passwords and other
+secrets it contains. In replay mode, it answers each request with the response
+of the first interaction of the cassette which matches it and has not been
+replayed yet, so that repeated requests, such as the polling of a resource
+status, are replayed in the order they were recorded.
+
+Since the authentication requests go through the Recorder too, it must be set
+on the ProviderClient before authenticating:
+
+	recorder, err := cassette.New("testdata/servers.json", cassette.Options{
+		Mode: cassette.ModeReplay,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	provider, err := openstack.NewClient(opts.IdentityEndpoint)
+	if err != nil {
+		panic(err)
+	}
+	provider.HTTPClient = http.Client{Transport: recorder}
+	err = openstack.Authenticate(ctx, provider, opts)
+
+The acceptance tests record or replay a cassette when the OS_CASSETTE
+environment variable is set; see internal/acceptance/README.md.
+*/
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+const (
+	// ModeReplay serves the requests from the interactions of the cassette.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends the requests to the cloud and records the
+	// interactions to the cassette, replacing its previous content.
+	ModeRecord
+)
+
+// Request is a recorded HTTP request.
+type Request struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+// Interaction is a recorded HTTP request and the response to it.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is a sequence of recorded interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Load reads a cassette from a file.
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("unable to parse cassette %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Save writes a cassette to a file.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Options configures a Recorder.
+type Options struct {
+	// Mode is the mode of the Recorder. It defaults to ModeReplay.
+	Mode Mode
+
+	// Transport sends the requests in record mode. It defaults to
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// Matcher reports whether a request matches a recorded one in replay
+	// mode. It defaults to MatchMethodAndURL.
+	Matcher MatcherFunc
+
+	// Scrubbers are applied to each interaction before it is recorded, after
+	// the tokens and secrets have been scrubbed with Scrub.
+	Scrubbers []ScrubberFunc
+}
+
+// ErrInteractionNotFound is returned in replay mode for a request which does
+// not match any remaining interaction of the cassette.
+type ErrInteractionNotFound struct {
+	Method string
+	URL    string
+}
+
+func (e ErrInteractionNotFound) Error() string {
+	return fmt.Sprintf("cassette: no interaction found for request %s %s", e.Method, e.URL)
+}
+
+// Recorder is an http.RoundTripper which records the interactions to a
+// cassette or replays them from it.
+type Recorder struct {
+	path string
+	opts Options
+
+	mu       sync.Mutex
+	cassette *Cassette
+	replayed []bool
+}
+
+// New returns a Recorder for the cassette at path. In replay mode the
+// cassette is loaded from path; in record mode it is created or truncated.
+func New(path string, opts Options) (*Recorder, error) {
+	if opts.Transport == nil {
+		opts.Transport = http.DefaultTransport
+	}
+	if opts.Matcher == nil {
+		opts.Matcher = MatchMethodAndURL
+	}
+
+	r := &Recorder{path: path, opts: opts}
+	switch opts.Mode {
+	case ModeReplay:
+		c, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = c
+		r.replayed = make([]bool, len(c.Interactions))
+	case ModeRecord:
+		r.cassette = &Cassette{Interactions: []Interaction{}}
+		if err := r.cassette.Save(path); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("cassette: unknown mode %d", opts.Mode)
+	}
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.opts.Mode == ModeRecord {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+// Unplayed returns the interactions of the cassette which have not been
+// replayed yet.
+func (r *Recorder) Unplayed() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unplayed []Interaction
+	for i, interaction := range r.cassette.Interactions {
+		if !r.replayed[i] {
+			unplayed = append(unplayed, interaction)
+		}
+	}
+	return unplayed
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.opts.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: req.Header.Clone(),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+		},
+	}
+	interaction.Request.Body, interaction.Request.BodyEncoding = encodeBody(body)
+	interaction.Response.Body, interaction.Response.BodyEncoding = encodeBody(respBody)
+	Scrub(&interaction)
+	for _, scrub := range r.opts.Scrubbers {
+		scrub(&interaction)
+	}
+
+	// the cassette is saved after each interaction so that it is complete
+	// even if the test using it exits early
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	if err := r.cassette.Save(r.path); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.replayed[i] || !r.opts.Matcher(req, body, interaction.Request) {
+			continue
+		}
+		r.replayed[i] = true
+
+		respBody, err := decodeBody(interaction.Response.Body, interaction.Response.BodyEncoding)
+		if err != nil {
+			return nil, err
+		}
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(respBody)),
+			ContentLength: int64(len(respBody)),
+			Request:       req,
+		}, nil
+	}
+	return nil, ErrInteractionNotFound{Method: req.Method, URL: req.URL.String()}
+}
+
+// encodeBody returns the representation of a body in a cassette: the body
+// itself if it is valid UTF-8, its base64 encoding otherwise.
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+func decodeBody(body, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		return []byte(body), nil
+	case "base64":
+		return base64.StdEncoding.DecodeString(body)
+	default:
+		return nil, fmt.Errorf("cassette: unknown body encoding %q", encoding)
+	}
+}
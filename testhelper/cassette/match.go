@@ -0,0 +1,62 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+)
+
+// MatcherFunc reports whether the request r, whose body is body, matches the
+// recorded request.
+type MatcherFunc func(r *http.Request, body []byte, recorded Request) bool
+
+// MatchMethodAndURL matches the requests with the same method and URL,
+// regardless of the order of the query parameters. The sensitive query
+// parameters, which are scrubbed from the recorded URLs, match any value. It
+// ignores the bodies, which often contain random resource names in acceptance
+// tests.
+func MatchMethodAndURL(r *http.Request, _ []byte, recorded Request) bool {
+	if r.Method != recorded.Method {
+		return false
+	}
+	u, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+	return r.URL.Scheme == u.Scheme &&
+		r.URL.Host == u.Host &&
+		r.URL.Path == u.Path &&
+		reflect.DeepEqual(scrubQuery(r.URL.Query()), u.Query())
+}
+
+// MatchMethodURLAndBody matches the requests like MatchMethodAndURL, and
+// additionally requires their bodies to be equal. JSON bodies are compared
+// semantically. Since the secrets of the recorded bodies are scrubbed, it
+// should not be used for the authentication requests.
+func MatchMethodURLAndBody(r *http.Request, body []byte, recorded Request) bool {
+	if !MatchMethodAndURL(r, body, recorded) {
+		return false
+	}
+	recordedBody, err := decodeBody(recorded.Body, recorded.BodyEncoding)
+	if err != nil {
+		return false
+	}
+	if bytes.Equal(body, recordedBody) {
+		return true
+	}
+
+	var actual, expected any
+	if json.Unmarshal(body, &actual) != nil || json.Unmarshal(recordedBody, &expected) != nil {
+		return false
+	}
+	return reflect.DeepEqual(actual, expected)
+}
+
+// scrubQuery returns query with its sensitive parameters scrubbed, as in the
+// recorded URLs.
+func scrubQuery(query url.Values) url.Values {
+	query, _ = scrubValues(query)
+	return query
+}
@@ -0,0 +1,203 @@
+package cassette
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// Redacted replaces the scrubbed values.
+const Redacted = gophercloud.RedactedValue
+
+// sensitiveParams lists the form and query parameters which hold credentials
+// besides the fields of gophercloud.IsSensitiveField: the OAuth 2.0
+// authorization codes and PKCE verifiers, the SAML2 responses of the
+// HTTP-POST binding and the signatures of the Object Storage temporary URLs.
+// Parameter names are compared case-insensitively.
+var sensitiveParams = map[string]bool{
+	"code":          true,
+	"code_verifier": true,
+	"samlresponse":  true,
+	"temp_url_sig":  true,
+}
+
+// samlAssertionStart matches the start tag of a SAML2 assertion, whose
+// content is a bearer credential.
+var samlAssertionStart = regexp.MustCompile(`<([\w.-]+:)?(Assertion|EncryptedAssertion)[\s>]`)
+
+// ScrubberFunc removes sensitive data from an interaction before it is
+// recorded.
+type ScrubberFunc func(i *Interaction)
+
+// Scrub replaces the tokens, passwords and other secrets of an interaction
+// with Redacted. It scrubs the headers and the fields of the JSON bodies which
+// the logging of the ProviderClient redacts, see gophercloud.RedactHeaders and
+// gophercloud.RedactJSONBody, the IDs of the tokens of the Identity API, the
+// same fields of the form-encoded bodies and of the query of the request URL,
+// and the content of the SAML2 assertions of XML bodies. A Recorder applies
+// it to each interaction it records.
+func Scrub(i *Interaction) {
+	i.Request.URL = scrubURL(i.Request.URL)
+	if i.Request.BodyEncoding == "" {
+		i.Request.Body = scrubBody(i.Request.Header, i.Request.Body)
+	}
+	if i.Response.BodyEncoding == "" {
+		i.Response.Body = scrubBody(i.Response.Header, i.Response.Body)
+	}
+	i.Request.Header = gophercloud.RedactHeaders(i.Request.Header)
+	i.Response.Header = gophercloud.RedactHeaders(i.Response.Header)
+}
+
+// isSensitiveParam reports whether the form or query parameter with the
+// given name holds credentials.
+func isSensitiveParam(name string) bool {
+	return gophercloud.IsSensitiveField(name) || sensitiveParams[strings.ToLower(name)]
+}
+
+// scrubBody returns body, whose headers are header, with its secrets
+// scrubbed.
+func scrubBody(header http.Header, body string) string {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		return scrubForm(body)
+	case strings.HasPrefix(strings.TrimSpace(body), "<"):
+		return scrubSAML(body)
+	default:
+		return scrubJSON(body)
+	}
+}
+
+// scrubURL returns rawURL with the sensitive parameters of its query
+// scrubbed.
+func scrubURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return rawURL
+	}
+	query, changed := scrubValues(u.Query())
+	if !changed {
+		return rawURL
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// scrubForm returns a form-encoded body with its sensitive parameters
+// scrubbed.
+func scrubForm(body string) string {
+	values, err := url.ParseQuery(body)
+	if err != nil {
+		return body
+	}
+	values, changed := scrubValues(values)
+	if !changed {
+		return body
+	}
+	return values.Encode()
+}
+
+// scrubValues scrubs the sensitive parameters of values, and reports whether
+// it changed anything.
+func scrubValues(values url.Values) (url.Values, bool) {
+	changed := false
+	for k, vs := range values {
+		if !isSensitiveParam(k) {
+			continue
+		}
+		for i := range vs {
+			if vs[i] != Redacted {
+				vs[i] = Redacted
+				changed = true
+			}
+		}
+	}
+	return values, changed
+}
+
+// scrubSAML returns an XML body with the content of its SAML2 assertions
+// scrubbed. The elements themselves are kept, so that the structure of the
+// ECP messages is preserved.
+func scrubSAML(body string) string {
+	var b strings.Builder
+	for {
+		loc := samlAssertionStart.FindStringSubmatchIndex(body)
+		if loc == nil {
+			b.WriteString(body)
+			return b.String()
+		}
+		startEnd := strings.IndexByte(body[loc[0]:], '>') + loc[0] + 1
+		if body[startEnd-2] == '/' {
+			// an empty assertion has no content to scrub
+			b.WriteString(body[:startEnd])
+			body = body[startEnd:]
+			continue
+		}
+		name := body[loc[0]+1 : loc[5]]
+		end := strings.Index(body[startEnd:], "</"+name+">")
+		if end < 0 {
+			// an unterminated assertion cannot be scrubbed precisely
+			b.WriteString(body[:loc[0]])
+			b.WriteString(Redacted)
+			return b.String()
+		}
+		b.WriteString(body[:startEnd])
+		b.WriteString(Redacted)
+		body = body[startEnd+end:]
+		// skip the end tag, so that it is not matched again
+		closeLen := len("</" + name + ">")
+		b.WriteString(body[:closeLen])
+		body = body[closeLen:]
+	}
+}
+
+// scrubJSON returns body with its sensitive fields scrubbed, or body
+// unchanged if it is not a JSON object.
+func scrubJSON(body string) string {
+	if !strings.HasPrefix(strings.TrimSpace(body), "{") {
+		return body
+	}
+	var data any
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		return body
+	}
+	if !scrubValue("", data) {
+		return body
+	}
+	scrubbed, err := json.Marshal(data)
+	if err != nil {
+		return body
+	}
+	return string(scrubbed)
+}
+
+// scrubValue scrubs the sensitive fields of v, the value of the field named
+// key, and reports whether it changed anything.
+func scrubValue(key string, v any) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]any:
+		for k, field := range v {
+			// the ID of a token is the token itself, both in the token
+			// authentication method and in the Identity v2 responses
+			if (key == "token" && k == "id") || gophercloud.IsSensitiveField(k) {
+				if field != Redacted {
+					v[k] = Redacted
+					changed = true
+				}
+				continue
+			}
+			changed = scrubValue(k, field) || changed
+		}
+	case []any:
+		for _, item := range v {
+			changed = scrubValue(key, item) || changed
+		}
+	}
+	return changed
+}
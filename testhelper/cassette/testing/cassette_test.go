@@ -0,0 +1,123 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack"
+	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/keypairs"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/testhelper/cassette"
+	"github.com/gophercloud/gophercloud/v2/testhelper/fakecloud"
+)
+
+// endpoint is the endpoint of the interactions of the replay tests, which do
+// not need a server.
+const endpoint = "http://cloud.example.com/"
+
+func newComputeClient(t *testing.T, recorder *cassette.Recorder, opts gophercloud.AuthOptions) *gophercloud.ServiceClient {
+	provider, err := openstack.NewClient(opts.IdentityEndpoint)
+	th.AssertNoErr(t, err)
+	provider.HTTPClient = http.Client{Transport: recorder}
+	th.AssertNoErr(t, openstack.Authenticate(context.TODO(), provider, opts))
+
+	client, err := openstack.NewComputeV2(context.TODO(), provider, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+	return client
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cloud := fakecloud.New(fakecloud.Options{})
+	opts := cloud.AuthOptions()
+
+	recorder, err := cassette.New(path, cassette.Options{Mode: cassette.ModeRecord})
+	th.AssertNoErr(t, err)
+	client := newComputeClient(t, recorder, opts)
+
+	recorded, err := keypairs.Create(context.TODO(), client, keypairs.CreateOpts{Name: "deployer"}).Extract()
+	th.AssertNoErr(t, err)
+	flavor, err := flavors.Get(context.TODO(), client, "1").Extract()
+	th.AssertNoErr(t, err)
+	cloud.Close()
+
+	// the secrets are scrubbed from the cassette
+	c, err := cassette.Load(path)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, len(c.Interactions))
+	auth := c.Interactions[0]
+	th.AssertEquals(t, false, strings.Contains(auth.Request.Body, opts.Password))
+	th.AssertEquals(t, cassette.Redacted, auth.Response.Header.Get("X-Subject-Token"))
+	th.AssertEquals(t, cassette.Redacted, c.Interactions[1].Request.Header.Get("X-Auth-Token"))
+	th.AssertEquals(t, false, strings.Contains(c.Interactions[1].Response.Body, recorded.PrivateKey))
+	th.AssertEquals(t, true, strings.Contains(c.Interactions[1].Response.Body, strings.TrimSpace(recorded.PublicKey)))
+
+	// the interactions are replayed without the cloud
+	recorder, err = cassette.New(path, cassette.Options{})
+	th.AssertNoErr(t, err)
+	client = newComputeClient(t, recorder, opts)
+
+	replayed, err := keypairs.Create(context.TODO(), client, keypairs.CreateOpts{Name: "deployer"}).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, recorded.Fingerprint, replayed.Fingerprint)
+	th.AssertEquals(t, cassette.Redacted, replayed.PrivateKey)
+	actual, err := flavors.Get(context.TODO(), client, "1").Extract()
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, flavor, actual)
+	th.AssertEquals(t, 0, len(recorder.Unplayed()))
+
+	// each interaction is replayed once
+	_, err = flavors.Get(context.TODO(), client, "1").Extract()
+	var notFound cassette.ErrInteractionNotFound
+	th.AssertEquals(t, true, errors.As(err, &notFound))
+	th.AssertEquals(t, http.MethodGet, notFound.Method)
+}
+
+func TestReplayInOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	err := (&cassette.Cassette{Interactions: []cassette.Interaction{
+		{
+			Request:  cassette.Request{Method: "GET", URL: endpoint + "status?b=2&a=1"},
+			Response: cassette.Response{StatusCode: http.StatusOK, Body: "first"},
+		},
+		{
+			Request:  cassette.Request{Method: "GET", URL: endpoint + "status?a=1&b=2"},
+			Response: cassette.Response{StatusCode: http.StatusOK, Body: "second"},
+		},
+		{
+			Request:  cassette.Request{Method: "PUT", URL: endpoint + "status", Body: `{"a": 1, "b": 2}`},
+			Response: cassette.Response{StatusCode: http.StatusNoContent},
+		},
+	}}).Save(path)
+	th.AssertNoErr(t, err)
+
+	recorder, err := cassette.New(path, cassette.Options{Matcher: cassette.MatchMethodURLAndBody})
+	th.AssertNoErr(t, err)
+	client := http.Client{Transport: recorder}
+
+	for _, expected := range []string{"first", "second"} {
+		resp, err := client.Get(endpoint + "status?a=1&b=2")
+		th.AssertNoErr(t, err)
+		body, err := io.ReadAll(resp.Body)
+		th.AssertNoErr(t, err)
+		th.AssertEquals(t, expected, string(body))
+	}
+
+	req, err := http.NewRequest("PUT", endpoint+"status", strings.NewReader(`{"b":2,"a":2}`))
+	th.AssertNoErr(t, err)
+	_, err = client.Do(req)
+	th.AssertEquals(t, true, errors.As(err, new(cassette.ErrInteractionNotFound)))
+
+	req, err = http.NewRequest("PUT", endpoint+"status", strings.NewReader(`{"b":2,"a":1}`))
+	th.AssertNoErr(t, err)
+	resp, err := client.Do(req)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, http.StatusNoContent, resp.StatusCode)
+}
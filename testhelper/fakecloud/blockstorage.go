@@ -0,0 +1,155 @@
+package fakecloud
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2/internal/fakestore"
+)
+
+// cinderTimeFormat is the format of the timestamps of the Block Storage API.
+const cinderTimeFormat = "2006-01-02T15:04:05.000000"
+
+// deletableVolumeStatuses are the statuses in which a volume can be deleted.
+var deletableVolumeStatuses = []string{"available", "error", "error_restoring", "error_extending", "error_managing"}
+
+func (c *Cloud) registerBlockStorage(mux *http.ServeMux) {
+	const prefix = "/volume/v3/{project}"
+
+	c.handle(mux, "GET "+prefix+"/volumes", true, c.listVolumes(false))
+	c.handle(mux, "GET "+prefix+"/volumes/detail", true, c.listVolumes(true))
+	c.handle(mux, "POST "+prefix+"/volumes", true, c.createVolume)
+	c.handle(mux, "GET "+prefix+"/volumes/{id}", true, c.getVolume)
+	c.handle(mux, "PUT "+prefix+"/volumes/{id}", true, c.updateVolume)
+	c.handle(mux, "DELETE "+prefix+"/volumes/{id}", true, c.deleteVolume)
+}
+
+func (c *Cloud) listVolumes(detail bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		items := filter(c.volumes.list(), r.URL.Query())
+		items, next := paginate(c.URL, r, items)
+		views := make([]resource, 0, len(items))
+		for _, v := range items {
+			if detail {
+				views = append(views, v)
+			} else {
+				views = append(views, resource{"id": v["id"], "name": v["name"], "links": v["links"]})
+			}
+		}
+		writeList(w, "volumes", views, next)
+	}
+}
+
+func (c *Cloud) getVolume(w http.ResponseWriter, r *http.Request) {
+	volume, ok := c.volumes.get(r.PathValue("id"))
+	if !ok {
+		writeFault(w, http.StatusNotFound, "Volume %s could not be found.", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, resource{"volume": volume})
+}
+
+func (c *Cloud) createVolume(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Volume resource `json:"volume"`
+	}
+	if err := decode(r, &body); err != nil || body.Volume == nil {
+		writeFault(w, http.StatusBadRequest, "Malformed request body")
+		return
+	}
+	req := body.Volume
+
+	size, _ := req["size"].(float64)
+	if size <= 0 {
+		writeFault(w, http.StatusBadRequest, "Invalid input received: 'size' parameter must be greater than 0.")
+		return
+	}
+	bootable := "false"
+	if imageRef := stringField(req, "imageRef"); imageRef != "" {
+		if img, ok := c.images.peek(imageRef); !ok || img["status"] != "active" {
+			writeFault(w, http.StatusBadRequest, "Invalid image identifier or unable to access requested image.")
+			return
+		}
+		bootable = "true"
+	}
+	if sourceID := stringField(req, "source_volid"); sourceID != "" {
+		source, ok := c.volumes.peek(sourceID)
+		if !ok {
+			writeFault(w, http.StatusNotFound, "Volume %s could not be found.", sourceID)
+			return
+		}
+		bootable = stringField(source, "bootable")
+	}
+
+	id := fakestore.NewID()
+	now := time.Now().UTC().Format(cinderTimeFormat)
+	volume := resource{
+		"id":                  id,
+		"name":                nil,
+		"description":         nil,
+		"status":              "creating",
+		"size":                int(size),
+		"availability_zone":   "nova",
+		"created_at":          now,
+		"updated_at":          now,
+		"attachments":         []resource{},
+		"volume_type":         "lvmdriver-1",
+		"snapshot_id":         nil,
+		"source_volid":        nil,
+		"metadata":            resource{},
+		"user_id":             c.userID,
+		"bootable":            bootable,
+		"encrypted":           false,
+		"multiattach":         false,
+		"replication_status":  nil,
+		"consistencygroup_id": nil,
+		"links": []resource{
+			{"rel": "self", "href": c.URL + "/volume/v3/" + c.projectID + "/volumes/" + id},
+		},
+		"os-vol-tenant-attr:tenant_id": c.projectID,
+	}
+	copyFields(volume, req, "name", "description", "availability_zone", "volume_type", "source_volid", "metadata", "multiattach")
+	c.volumes.put(volume)
+	c.volumes.transition(id, c.opts.PendingReads, func(v resource) {
+		v["status"] = "available"
+	})
+
+	writeJSON(w, http.StatusAccepted, resource{"volume": volume})
+}
+
+func (c *Cloud) updateVolume(w http.ResponseWriter, r *http.Request) {
+	volume, ok := c.volumes.peek(r.PathValue("id"))
+	if !ok {
+		writeFault(w, http.StatusNotFound, "Volume %s could not be found.", r.PathValue("id"))
+		return
+	}
+	var body struct {
+		Volume resource `json:"volume"`
+	}
+	if err := decode(r, &body); err != nil || body.Volume == nil {
+		writeFault(w, http.StatusBadRequest, "Malformed request body")
+		return
+	}
+	copyFields(volume, body.Volume, "name", "description")
+	if metadata, ok := body.Volume["metadata"].(map[string]any); ok {
+		volume["metadata"] = maps.Clone(metadata)
+	}
+	volume["updated_at"] = time.Now().UTC().Format(cinderTimeFormat)
+	writeJSON(w, http.StatusOK, resource{"volume": volume})
+}
+
+func (c *Cloud) deleteVolume(w http.ResponseWriter, r *http.Request) {
+	volume, ok := c.volumes.peek(r.PathValue("id"))
+	if !ok {
+		writeFault(w, http.StatusNotFound, "Volume %s could not be found.", r.PathValue("id"))
+		return
+	}
+	if !slices.Contains(deletableVolumeStatuses, stringField(volume, "status")) {
+		writeFault(w, http.StatusBadRequest, "Invalid volume: Volume status must be available or error or error_restoring or error_extending or error_managing and must not be migrating, attached, belong to a group, have snapshots, awaiting a transfer, or be disassociated from snapshots after volume transfer.")
+		return
+	}
+	c.volumes.delete(r.PathValue("id"))
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,603 @@
+package fakecloud
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"maps"
+	"net/http"
+	"net/netip"
+	"regexp"
+	"slices"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/gophercloud/gophercloud/v2/internal/fakestore"
+)
+
+// novaTimeFormat is the format of the timestamps of the Compute API.
+const novaTimeFormat = "2006-01-02T15:04:05Z"
+
+// novaMaxMicroversion is the maximum microversion reported by the Compute
+// API of the cloud. The cloud does not change its behaviour with the
+// requested microversion.
+const novaMaxMicroversion = "2.96"
+
+func (c *Cloud) registerCompute(mux *http.ServeMux) {
+	const prefix = "/compute/v2.1"
+
+	c.handle(mux, "GET "+prefix+"/{$}", false, c.computeVersion)
+	c.handle(mux, "GET "+prefix+"/servers", true, c.listServers(false))
+	c.handle(mux, "GET "+prefix+"/servers/detail", true, c.listServers(true))
+	c.handle(mux, "POST "+prefix+"/servers", true, c.createServer)
+	c.handle(mux, "GET "+prefix+"/servers/{id}", true, c.getServer)
+	c.handle(mux, "PUT "+prefix+"/servers/{id}", true, c.updateServer)
+	c.handle(mux, "DELETE "+prefix+"/servers/{id}", true, c.deleteServer)
+	c.handle(mux, "POST "+prefix+"/servers/{id}/action", true, c.serverAction)
+	c.handle(mux, "GET "+prefix+"/flavors", true, c.listFlavors(false))
+	c.handle(mux, "GET "+prefix+"/flavors/detail", true, c.listFlavors(true))
+	c.handle(mux, "POST "+prefix+"/flavors", true, c.createFlavor)
+	c.handle(mux, "GET "+prefix+"/flavors/{id}", true, c.getFlavor)
+	c.handle(mux, "DELETE "+prefix+"/flavors/{id}", true, c.deleteFlavor)
+	c.handle(mux, "GET "+prefix+"/os-keypairs", true, c.listKeyPairs)
+	c.handle(mux, "POST "+prefix+"/os-keypairs", true, c.createKeyPair)
+	c.handle(mux, "GET "+prefix+"/os-keypairs/{name}", true, c.getKeyPair)
+	c.handle(mux, "DELETE "+prefix+"/os-keypairs/{name}", true, c.deleteKeyPair)
+}
+
+func (c *Cloud) computeVersion(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, resource{
+		"version": resource{
+			"id":          "v2.1",
+			"status":      "CURRENT",
+			"version":     novaMaxMicroversion,
+			"min_version": "2.1",
+			"updated":     "2013-07-23T11:33:21Z",
+			"links": []resource{
+				{"rel": "self", "href": c.URL + "/compute/v2.1/"},
+			},
+		},
+	})
+}
+
+// seedCompute creates the flavors every cloud starts with.
+func (c *Cloud) seedCompute() {
+	flavors := []struct {
+		id, name         string
+		ram, vcpus, disk int
+	}{
+		{"1", "m1.tiny", 512, 1, 1},
+		{"2", "m1.small", 2048, 1, 20},
+		{"3", "m1.medium", 4096, 2, 40},
+		{"4", "m1.large", 8192, 4, 80},
+	}
+	for _, f := range flavors {
+		c.flavors.put(c.newFlavor(resource{
+			"id":    f.id,
+			"name":  f.name,
+			"ram":   f.ram,
+			"vcpus": f.vcpus,
+			"disk":  f.disk,
+		}))
+	}
+}
+
+// computeLinks returns the links of a resource of the Compute API.
+func (c *Cloud) computeLinks(collection, id string) []resource {
+	return []resource{
+		{"rel": "self", "href": c.URL + "/compute/v2.1/" + collection + "/" + id},
+		{"rel": "bookmark", "href": c.URL + "/compute/" + collection + "/" + id},
+	}
+}
+
+func (c *Cloud) listServers(detail bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		items := filter(c.servers.list(), query, "name")
+		if name := query.Get("name"); name != "" {
+			re, err := regexp.Compile(name)
+			if err != nil {
+				writeFault(w, http.StatusBadRequest, "Invalid regular expression %s", name)
+				return
+			}
+			items = slices.DeleteFunc(items, func(s resource) bool { return !re.MatchString(stringField(s, "name")) })
+		}
+		items, next := paginate(c.URL, r, items)
+
+		views := make([]resource, 0, len(items))
+		for _, s := range items {
+			if detail {
+				views = append(views, c.serverView(s))
+			} else {
+				views = append(views, resource{"id": s["id"], "name": s["name"], "links": s["links"]})
+			}
+		}
+		writeList(w, "servers", views, next)
+	}
+}
+
+func (c *Cloud) getServer(w http.ResponseWriter, r *http.Request) {
+	server, ok := c.servers.get(r.PathValue("id"))
+	if !ok {
+		writeFault(w, http.StatusNotFound, "Instance %s could not be found.", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, resource{"server": c.serverView(server)})
+}
+
+// serverView returns the representation of a server, which includes the
+// addresses of its ports.
+func (c *Cloud) serverView(server resource) resource {
+	view := maps.Clone(server)
+	addresses := resource{}
+	for _, port := range c.ports.find(func(p resource) bool { return p["device_id"] == server["id"] }) {
+		network, _ := c.networks.peek(stringField(port, "network_id"))
+		name := stringField(network, "name")
+		list, _ := addresses[name].([]resource)
+		for _, ip := range port["fixed_ips"].([]resource) {
+			version := 4
+			if addr, err := netip.ParseAddr(stringField(ip, "ip_address")); err == nil && addr.Is6() {
+				version = 6
+			}
+			list = append(list, resource{
+				"addr":                    ip["ip_address"],
+				"version":                 version,
+				"OS-EXT-IPS:type":         "fixed",
+				"OS-EXT-IPS-MAC:mac_addr": port["mac_address"],
+			})
+		}
+		addresses[name] = list
+	}
+	view["addresses"] = addresses
+	return view
+}
+
+func (c *Cloud) createServer(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Server resource `json:"server"`
+	}
+	if err := decode(r, &body); err != nil || body.Server == nil {
+		writeFault(w, http.StatusBadRequest, "Malformed request body")
+		return
+	}
+	req := body.Server
+
+	name := stringField(req, "name")
+	if name == "" {
+		writeFault(w, http.StatusBadRequest, "Invalid input for field/attribute name.")
+		return
+	}
+	flavorRef := stringField(req, "flavorRef")
+	flavor, ok := c.flavors.peek(flavorRef)
+	if !ok {
+		writeFault(w, http.StatusBadRequest, "Flavor %s could not be found.", flavorRef)
+		return
+	}
+	var image any = ""
+	if imageRef := stringField(req, "imageRef"); imageRef != "" {
+		if img, ok := c.images.peek(imageRef); !ok || img["status"] != "active" {
+			writeFault(w, http.StatusBadRequest, "Image %s could not be found.", imageRef)
+			return
+		}
+		image = resource{"id": imageRef, "links": c.computeLinks("images", imageRef)}
+	}
+	if keyName := stringField(req, "key_name"); keyName != "" {
+		if _, ok := c.keypairs.peek(keyName); !ok {
+			writeFault(w, http.StatusBadRequest, "Invalid key_name provided.")
+			return
+		}
+	}
+
+	// the security groups are resolved before any port is created
+	var securityGroups []any
+	var securityGroupNames []resource
+	for _, group := range resources(req["security_groups"]) {
+		name := stringField(group, "name")
+		found := c.securityGroups.find(func(sg resource) bool { return sg["name"] == name || sg["id"] == name })
+		if len(found) == 0 {
+			writeFault(w, http.StatusBadRequest, "Security group %s not found for project %s.", name, c.projectID)
+			return
+		}
+		securityGroups = append(securityGroups, found[0]["id"])
+		securityGroupNames = append(securityGroupNames, resource{"name": found[0]["name"]})
+	}
+	if securityGroupNames == nil {
+		securityGroupNames = []resource{{"name": "default"}}
+	}
+
+	id := fakestore.NewID()
+	if err := c.attachNetworks(id, req["networks"], securityGroups); err != nil {
+		writeFault(w, err.status, "%s", err.message)
+		return
+	}
+
+	now := time.Now().UTC().Format(novaTimeFormat)
+	server := resource{
+		"id":                                   id,
+		"name":                                 name,
+		"status":                               "BUILD",
+		"tenant_id":                            c.projectID,
+		"user_id":                              c.userID,
+		"created":                              now,
+		"updated":                              now,
+		"hostId":                               "",
+		"progress":                             0,
+		"image":                                image,
+		"flavor":                               resource{"id": flavor["id"], "links": c.computeLinks("flavors", flavor["id"].(string))},
+		"metadata":                             resource{},
+		"key_name":                             nil,
+		"accessIPv4":                           "",
+		"accessIPv6":                           "",
+		"links":                                c.computeLinks("servers", id),
+		"security_groups":                      securityGroupNames,
+		"config_drive":                         "",
+		"OS-DCF:diskConfig":                    "MANUAL",
+		"OS-EXT-AZ:availability_zone":          "nova",
+		"OS-EXT-STS:vm_state":                  "building",
+		"OS-EXT-STS:task_state":                "scheduling",
+		"OS-EXT-STS:power_state":               0,
+		"os-extended-volumes:volumes_attached": []resource{},
+	}
+	copyFields(server, req, "metadata", "key_name", "accessIPv4", "accessIPv6")
+	if tags, ok := req["tags"]; ok {
+		server["tags"] = tags
+	}
+	c.servers.put(server)
+	c.servers.transition(id, c.opts.PendingReads, func(s resource) {
+		s["status"] = "ACTIVE"
+		s["hostId"] = newHexID()
+		s["progress"] = 100
+		s["OS-EXT-STS:vm_state"] = "active"
+		s["OS-EXT-STS:task_state"] = nil
+		s["OS-EXT-STS:power_state"] = 1
+		s["OS-SRV-USG:launched_at"] = time.Now().UTC().Format("2006-01-02T15:04:05.000000")
+	})
+
+	adminPass := stringField(req, "adminPass")
+	if adminPass == "" {
+		adminPass = newHexID()[:12]
+	}
+	writeJSON(w, http.StatusAccepted, resource{
+		"server": resource{
+			"id":                id,
+			"links":             server["links"],
+			"adminPass":         adminPass,
+			"security_groups":   securityGroupNames,
+			"OS-DCF:diskConfig": "MANUAL",
+		},
+	})
+}
+
+// attachNetworks creates or binds the ports of a new server, as requested by
+// the networks field of a server creation request. When the field is missing
+// or "auto", the server is attached to the first network which is not
+// external.
+func (c *Cloud) attachNetworks(serverID string, networks any, securityGroups []any) *apiError {
+	var requested []resource
+	switch v := networks.(type) {
+	case nil, string:
+		if v == "none" {
+			return nil
+		}
+		for _, network := range c.networks.list() {
+			if network["router:external"] != true {
+				requested = append(requested, resource{"uuid": network["id"]})
+				break
+			}
+		}
+	default:
+		requested = resources(v)
+	}
+
+	// ports are validated before any of them is bound or created
+	for _, network := range requested {
+		if portID := stringField(network, "port"); portID != "" {
+			port, ok := c.ports.peek(portID)
+			if !ok {
+				return newAPIError(http.StatusBadRequest, "", "Port id %s could not be found.", portID)
+			}
+			if stringField(port, "device_id") != "" {
+				return newAPIError(http.StatusConflict, "", "Port %s is still in use.", portID)
+			}
+		} else if _, ok := c.networks.peek(stringField(network, "uuid")); !ok {
+			return newAPIError(http.StatusBadRequest, "", "Network %s could not be found.", stringField(network, "uuid"))
+		}
+	}
+
+	for _, network := range requested {
+		if portID := stringField(network, "port"); portID != "" {
+			port, _ := c.ports.peek(portID)
+			port["device_id"] = serverID
+			port["device_owner"] = "compute:nova"
+			setPortStatus(port)
+			continue
+		}
+		req := resource{
+			"network_id":   network["uuid"],
+			"device_id":    serverID,
+			"device_owner": "compute:nova",
+		}
+		if fixedIP := stringField(network, "fixed_ip"); fixedIP != "" {
+			req["fixed_ips"] = []any{map[string]any{"ip_address": fixedIP}}
+		}
+		if securityGroups != nil {
+			req["security_groups"] = securityGroups
+		}
+		port, err := c.createPort(req)
+		if err != nil {
+			return newAPIError(http.StatusBadRequest, "", "%s", err.message)
+		}
+		c.serverPorts[port["id"].(string)] = true
+	}
+	return nil
+}
+
+func (c *Cloud) updateServer(w http.ResponseWriter, r *http.Request) {
+	server, ok := c.servers.peek(r.PathValue("id"))
+	if !ok {
+		writeFault(w, http.StatusNotFound, "Instance %s could not be found.", r.PathValue("id"))
+		return
+	}
+	var body struct {
+		Server resource `json:"server"`
+	}
+	if err := decode(r, &body); err != nil || body.Server == nil {
+		writeFault(w, http.StatusBadRequest, "Malformed request body")
+		return
+	}
+	copyFields(server, body.Server, "name", "accessIPv4", "accessIPv6")
+	if hostname, ok := body.Server["hostname"]; ok {
+		server["OS-EXT-SRV-ATTR:hostname"] = hostname
+	}
+	server["updated"] = time.Now().UTC().Format(novaTimeFormat)
+	writeJSON(w, http.StatusOK, resource{"server": c.serverView(server)})
+}
+
+func (c *Cloud) deleteServer(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !c.servers.delete(id) {
+		writeFault(w, http.StatusNotFound, "Instance %s could not be found.", id)
+		return
+	}
+	for _, port := range c.ports.find(func(p resource) bool { return p["device_id"] == id }) {
+		portID := port["id"].(string)
+		if c.serverPorts[portID] {
+			c.ports.delete(portID)
+			delete(c.serverPorts, portID)
+			continue
+		}
+		port["device_id"] = ""
+		port["device_owner"] = ""
+		setPortStatus(port)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serverActions are the server actions supported by the cloud, with the
+// statuses from which they can be performed, the transitional status, if
+// any, and the final status.
+var serverActions = map[string]struct {
+	from                []string
+	pending, status, vm string
+	powerState          int
+}{
+	"os-stop":  {from: []string{"ACTIVE"}, status: "SHUTOFF", vm: "stopped", powerState: 4},
+	"os-start": {from: []string{"SHUTOFF"}, status: "ACTIVE", vm: "active", powerState: 1},
+	"reboot":   {from: []string{"ACTIVE", "SHUTOFF"}, pending: "REBOOT", status: "ACTIVE", vm: "active", powerState: 1},
+	"pause":    {from: []string{"ACTIVE"}, status: "PAUSED", vm: "paused", powerState: 3},
+	"unpause":  {from: []string{"PAUSED"}, status: "ACTIVE", vm: "active", powerState: 1},
+}
+
+func (c *Cloud) serverAction(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	server, ok := c.servers.get(id)
+	if !ok {
+		writeFault(w, http.StatusNotFound, "Instance %s could not be found.", id)
+		return
+	}
+	var body map[string]any
+	if err := decode(r, &body); err != nil || len(body) != 1 {
+		writeFault(w, http.StatusBadRequest, "Malformed request body")
+		return
+	}
+
+	for name := range body {
+		action, ok := serverActions[name]
+		if !ok {
+			writeFault(w, http.StatusBadRequest, "Action %s is not supported.", name)
+			return
+		}
+		if !slices.Contains(action.from, stringField(server, "status")) {
+			writeFault(w, http.StatusConflict, "Cannot '%s' instance %s while it is in vm_state %s", name, id, server["OS-EXT-STS:vm_state"])
+			return
+		}
+
+		done := func(s resource) {
+			s["status"] = action.status
+			s["OS-EXT-STS:vm_state"] = action.vm
+			s["OS-EXT-STS:power_state"] = action.powerState
+			s["OS-EXT-STS:task_state"] = nil
+			s["updated"] = time.Now().UTC().Format(novaTimeFormat)
+		}
+		if action.pending == "" {
+			done(server)
+		} else {
+			server["status"] = action.pending
+			c.servers.transition(id, c.opts.PendingReads, done)
+		}
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// newFlavor returns a flavor from a flavor creation request.
+func (c *Cloud) newFlavor(req resource) resource {
+	id := stringField(req, "id")
+	if id == "" {
+		id = fakestore.NewID()
+	}
+	flavor := resource{
+		"id":                         id,
+		"name":                       "",
+		"ram":                        0,
+		"vcpus":                      0,
+		"disk":                       0,
+		"swap":                       "",
+		"rxtx_factor":                1.0,
+		"OS-FLV-EXT-DATA:ephemeral":  0,
+		"OS-FLV-DISABLED:disabled":   false,
+		"os-flavor-access:is_public": true,
+		"description":                nil,
+		"extra_specs":                resource{},
+		"links":                      c.computeLinks("flavors", id),
+	}
+	copyFields(flavor, req, "name", "ram", "vcpus", "disk", "swap", "rxtx_factor", "OS-FLV-EXT-DATA:ephemeral", "os-flavor-access:is_public", "description")
+	return flavor
+}
+
+func (c *Cloud) listFlavors(detail bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		items, next := paginate(c.URL, r, c.flavors.list())
+		views := make([]resource, 0, len(items))
+		for _, f := range items {
+			if detail {
+				views = append(views, f)
+			} else {
+				views = append(views, resource{"id": f["id"], "name": f["name"], "links": f["links"]})
+			}
+		}
+		writeList(w, "flavors", views, next)
+	}
+}
+
+func (c *Cloud) getFlavor(w http.ResponseWriter, r *http.Request) {
+	flavor, ok := c.flavors.get(r.PathValue("id"))
+	if !ok {
+		writeFault(w, http.StatusNotFound, "Flavor %s could not be found.", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, resource{"flavor": flavor})
+}
+
+func (c *Cloud) createFlavor(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Flavor resource `json:"flavor"`
+	}
+	if err := decode(r, &body); err != nil || stringField(body.Flavor, "name") == "" {
+		writeFault(w, http.StatusBadRequest, "Invalid input for field/attribute name.")
+		return
+	}
+	flavor := c.newFlavor(body.Flavor)
+	if _, ok := c.flavors.peek(flavor["id"].(string)); ok || len(c.flavors.find(func(f resource) bool { return f["name"] == flavor["name"] })) > 0 {
+		writeFault(w, http.StatusConflict, "Flavor with name %s already exists.", flavor["name"])
+		return
+	}
+	c.flavors.put(flavor)
+	writeJSON(w, http.StatusOK, resource{"flavor": flavor})
+}
+
+func (c *Cloud) deleteFlavor(w http.ResponseWriter, r *http.Request) {
+	if !c.flavors.delete(r.PathValue("id")) {
+		writeFault(w, http.StatusNotFound, "Flavor %s could not be found.", r.PathValue("id"))
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// keyPairView returns the representation of a key pair. Key pairs are
+// stored with their name as ID.
+func keyPairView(keypair resource) resource {
+	view := maps.Clone(keypair)
+	delete(view, "id")
+	return view
+}
+
+func (c *Cloud) listKeyPairs(w http.ResponseWriter, r *http.Request) {
+	items := c.keypairs.list()
+	views := make([]resource, 0, len(items))
+	for _, keypair := range items {
+		view := keyPairView(keypair)
+		delete(view, "user_id")
+		delete(view, "created_at")
+		views = append(views, resource{"keypair": view})
+	}
+	writeJSON(w, http.StatusOK, resource{"keypairs": views})
+}
+
+func (c *Cloud) getKeyPair(w http.ResponseWriter, r *http.Request) {
+	keypair, ok := c.keypairs.get(r.PathValue("name"))
+	if !ok {
+		writeFault(w, http.StatusNotFound, "Keypair %s not found for user %s", r.PathValue("name"), c.userID)
+		return
+	}
+	writeJSON(w, http.StatusOK, resource{"keypair": keyPairView(keypair)})
+}
+
+func (c *Cloud) createKeyPair(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		KeyPair resource `json:"keypair"`
+	}
+	if err := decode(r, &body); err != nil || stringField(body.KeyPair, "name") == "" {
+		writeFault(w, http.StatusBadRequest, "Invalid input for field/attribute name.")
+		return
+	}
+	name := stringField(body.KeyPair, "name")
+	if _, ok := c.keypairs.peek(name); ok {
+		writeFault(w, http.StatusConflict, "Key pair '%s' already exists.", name)
+		return
+	}
+
+	var privateKey string
+	publicKey := stringField(body.KeyPair, "public_key")
+	if publicKey == "" {
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			writeFault(w, http.StatusInternalServerError, "%s", err)
+			return
+		}
+		sshPublic, err := ssh.NewPublicKey(public)
+		if err != nil {
+			writeFault(w, http.StatusInternalServerError, "%s", err)
+			return
+		}
+		block, err := ssh.MarshalPrivateKey(private, "")
+		if err != nil {
+			writeFault(w, http.StatusInternalServerError, "%s", err)
+			return
+		}
+		publicKey = string(ssh.MarshalAuthorizedKey(sshPublic))
+		privateKey = string(pem.EncodeToMemory(block))
+	}
+	parsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		writeFault(w, http.StatusBadRequest, "Keypair data is invalid: failed to generate fingerprint")
+		return
+	}
+
+	keypair := resource{
+		"id":          name,
+		"name":        name,
+		"public_key":  publicKey,
+		"fingerprint": ssh.FingerprintLegacyMD5(parsed),
+		"type":        "ssh",
+		"user_id":     c.userID,
+		"created_at":  time.Now().UTC().Format("2006-01-02T15:04:05.000000"),
+		"deleted":     false,
+	}
+	copyFields(keypair, body.KeyPair, "type")
+	c.keypairs.put(keypair)
+
+	view := keyPairView(keypair)
+	if privateKey != "" {
+		view["private_key"] = privateKey
+	}
+	writeJSON(w, http.StatusOK, resource{"keypair": view})
+}
+
+func (c *Cloud) deleteKeyPair(w http.ResponseWriter, r *http.Request) {
+	if !c.keypairs.delete(r.PathValue("name")) {
+		writeFault(w, http.StatusNotFound, "Keypair %s not found for user %s", r.PathValue("name"), c.userID)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,510 @@
+/*
+Package fakecloud implements a stateful in-memory OpenStack cloud, to test code
+which uses Gophercloud end to end without a real cloud.
+
+Unlike the handlers registered on a testhelper.FakeServer, which return canned
+responses, a Cloud keeps track of the resources created through its API. It
+serves:
+
+  - the Identity v3 API: token issuance with a service catalog, validation
+    and revocation
+  - the Compute v2.1 API: servers, server actions, flavors and keypairs
+  - the Networking v2.0 API: networks, subnets, ports, security groups and
+    security group rules
+  - the Block Storage v3 API: volumes
+  - the Image v2 API: images and image data
+
+Servers, volumes and images go through a transitional status (BUILD, creating,
+saving) for a configurable number of reads before reaching their final status,
+so that the polling code of an application is exercised too.
+
+Example:
+
+	cloud := fakecloud.New(fakecloud.Options{})
+	defer cloud.Close()
+
+	provider, err := openstack.AuthenticatedClient(ctx, cloud.AuthOptions())
+	if err != nil {
+		panic(err)
+	}
+	compute, err := openstack.NewComputeV2(ctx, provider, gophercloud.EndpointOpts{})
+	if err != nil {
+		panic(err)
+	}
+	server, err := servers.Create(ctx, compute, servers.CreateOpts{
+		Name:      "web",
+		ImageRef:  fakecloud.CirrosImageID,
+		FlavorRef: "1",
+	}, nil).Extract()
+	if err != nil {
+		panic(err)
+	}
+	err = servers.WaitForStatus(ctx, compute, server.ID, "ACTIVE")
+*/
+package fakecloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/internal/fakestore"
+)
+
+const (
+	// DefaultUsername is the name of the user of a Cloud whose Options
+	// do not set one.
+	DefaultUsername = "admin"
+	// DefaultPassword is the password of the user of a Cloud whose Options
+	// do not set one.
+	DefaultPassword = "secret"
+	// DefaultProjectName is the name of the project of a Cloud whose Options
+	// do not set one.
+	DefaultProjectName = "admin"
+	// DefaultDomainName is the name of the domain of the user and the
+	// project of a Cloud, when the Options do not set one.
+	DefaultDomainName = "Default"
+	// DefaultRegion is the region of the endpoints of a Cloud whose Options
+	// do not set one.
+	DefaultRegion = "RegionOne"
+	// DefaultTokenTTL is the lifetime of the tokens issued by a Cloud whose
+	// Options do not set one.
+	DefaultTokenTTL = time.Hour
+	// DefaultPendingReads is the number of reads during which a new resource
+	// keeps its transitional status, when the Options do not set it.
+	DefaultPendingReads = 1
+
+	// CirrosImageID is the ID of the active image every Cloud starts with.
+	CirrosImageID = "4b2d9b0e-6c7b-4a76-9cba-0fb0c8ed2ab4"
+)
+
+// Options configures a Cloud. The zero value is a valid configuration.
+type Options struct {
+	// Username and Password are the credentials of the only user of the
+	// cloud. They default to DefaultUsername and DefaultPassword.
+	Username string
+	Password string
+
+	// ProjectName is the name of the only project of the cloud. Defaults to
+	// DefaultProjectName.
+	ProjectName string
+
+	// DomainName is the name of the domain of the user and the project.
+	// Defaults to DefaultDomainName.
+	DomainName string
+
+	// Region is the region of the endpoints of the catalog. Defaults to
+	// DefaultRegion.
+	Region string
+
+	// TokenTTL is the lifetime of the issued tokens. Defaults to
+	// DefaultTokenTTL.
+	TokenTTL time.Duration
+
+	// PendingReads is the number of times a new server, volume or image is
+	// read in its transitional status before it reaches its final status.
+	// Defaults to DefaultPendingReads; a negative value makes new resources
+	// ready at once.
+	PendingReads int
+}
+
+// Cloud is a stateful in-memory OpenStack cloud, served over HTTP. It is safe
+// for concurrent use.
+type Cloud struct {
+	// URL is the base URL of the cloud, which serves the Identity API under
+	// /identity, the Compute API under /compute, the Networking API under
+	// /network, the Block Storage API under /volume and the Image API under
+	// /image.
+	URL string
+
+	opts   Options
+	server *httptest.Server
+
+	mu        sync.Mutex
+	domainID  string
+	projectID string
+	userID    string
+	tokens    map[string]*token
+
+	servers            *collection
+	flavors            *collection
+	keypairs           *collection
+	networks           *collection
+	subnets            *collection
+	ports              *collection
+	securityGroups     *collection
+	securityGroupRules *collection
+	volumes            *collection
+	images             *collection
+	imageData          map[string][]byte
+	nextIP             map[string]netip.Addr
+	// serverPorts are the ports created for servers, which are deleted
+	// with them.
+	serverPorts map[string]bool
+}
+
+// New starts a Cloud configured with opts. It must be stopped with Close.
+func New(opts Options) *Cloud {
+	if opts.Username == "" {
+		opts.Username = DefaultUsername
+	}
+	if opts.Password == "" {
+		opts.Password = DefaultPassword
+	}
+	if opts.ProjectName == "" {
+		opts.ProjectName = DefaultProjectName
+	}
+	if opts.DomainName == "" {
+		opts.DomainName = DefaultDomainName
+	}
+	if opts.Region == "" {
+		opts.Region = DefaultRegion
+	}
+	if opts.TokenTTL <= 0 {
+		opts.TokenTTL = DefaultTokenTTL
+	}
+	if opts.PendingReads == 0 {
+		opts.PendingReads = DefaultPendingReads
+	}
+
+	c := &Cloud{
+		opts:               opts,
+		domainID:           "default",
+		projectID:          newHexID(),
+		userID:             newHexID(),
+		tokens:             make(map[string]*token),
+		servers:            newCollection(),
+		flavors:            newCollection(),
+		keypairs:           newCollection(),
+		networks:           newCollection(),
+		subnets:            newCollection(),
+		ports:              newCollection(),
+		securityGroups:     newCollection(),
+		securityGroupRules: newCollection(),
+		volumes:            newCollection(),
+		images:             newCollection(),
+		imageData:          make(map[string][]byte),
+		nextIP:             make(map[string]netip.Addr),
+		serverPorts:        make(map[string]bool),
+	}
+	if opts.DomainName != DefaultDomainName {
+		c.domainID = newHexID()
+	}
+
+	mux := http.NewServeMux()
+	c.registerIdentity(mux)
+	c.registerCompute(mux)
+	c.registerNetwork(mux)
+	c.registerBlockStorage(mux)
+	c.registerImage(mux)
+	c.server = httptest.NewServer(mux)
+	c.URL = c.server.URL
+
+	c.seedCompute()
+	c.seedNetwork()
+	c.seedImage()
+
+	return c
+}
+
+// Close stops the cloud.
+func (c *Cloud) Close() {
+	c.server.Close()
+}
+
+// IdentityEndpoint returns the URL of the Identity v3 API of the cloud.
+func (c *Cloud) IdentityEndpoint() string {
+	return c.URL + "/identity/v3/"
+}
+
+// AuthOptions returns the options to authenticate against the cloud with the
+// credentials of its user, scoped to its project. Reauthentication is
+// allowed, so that clients survive ExpireTokens.
+func (c *Cloud) AuthOptions() gophercloud.AuthOptions {
+	return gophercloud.AuthOptions{
+		IdentityEndpoint: c.IdentityEndpoint(),
+		Username:         c.opts.Username,
+		Password:         c.opts.Password,
+		DomainName:       c.opts.DomainName,
+		TenantName:       c.opts.ProjectName,
+		AllowReauth:      true,
+	}
+}
+
+// ProjectID returns the ID of the project of the cloud.
+func (c *Cloud) ProjectID() string {
+	return c.projectID
+}
+
+// ExpireTokens makes all the tokens issued so far invalid, so that the next
+// request of a client fails with a 401 error and triggers its
+// reauthentication.
+func (c *Cloud) ExpireTokens() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.tokens)
+}
+
+// handle registers handler for pattern on mux. The handler is called with
+// the lock of the cloud held, and only if the request carries a valid token
+// when authenticated is true.
+func (c *Cloud) handle(mux *http.ServeMux, pattern string, authenticated bool, handler http.HandlerFunc) {
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if authenticated && !c.validToken(r.Header.Get("X-Auth-Token")) {
+			writeIdentityError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+			return
+		}
+		handler(w, r)
+	})
+}
+
+// resource is the JSON representation of a resource, as returned by the
+// APIs.
+type resource = map[string]any
+
+// transition is the pending change of the status of a resource.
+type transition struct {
+	reads int
+	done  func(resource)
+}
+
+// collection holds resources indexed by ID, in the order they were created.
+// It is protected by the lock of the Cloud.
+type collection struct {
+	items       map[string]resource
+	order       []string
+	transitions map[string]*transition
+}
+
+func newCollection() *collection {
+	return &collection{
+		items:       make(map[string]resource),
+		transitions: make(map[string]*transition),
+	}
+}
+
+// put adds or replaces a resource, identified by its "id" field.
+func (c *collection) put(r resource) {
+	id := r["id"].(string)
+	if _, ok := c.items[id]; !ok {
+		c.order = append(c.order, id)
+	}
+	c.items[id] = r
+}
+
+// get returns the resource with the given ID, after advancing its
+// transition.
+func (c *collection) get(id string) (resource, bool) {
+	r, ok := c.items[id]
+	if ok {
+		c.advance(id)
+	}
+	return r, ok
+}
+
+// peek returns the resource with the given ID, without advancing its
+// transition.
+func (c *collection) peek(id string) (resource, bool) {
+	r, ok := c.items[id]
+	return r, ok
+}
+
+// list returns all the resources, after advancing their transitions.
+func (c *collection) list() []resource {
+	items := make([]resource, 0, len(c.order))
+	for _, id := range c.order {
+		c.advance(id)
+		items = append(items, c.items[id])
+	}
+	return items
+}
+
+// find returns the resources for which match returns true, without
+// advancing their transitions.
+func (c *collection) find(match func(resource) bool) []resource {
+	var items []resource
+	for _, id := range c.order {
+		if match(c.items[id]) {
+			items = append(items, c.items[id])
+		}
+	}
+	return items
+}
+
+// delete removes the resource with the given ID.
+func (c *collection) delete(id string) bool {
+	if _, ok := c.items[id]; !ok {
+		return false
+	}
+	delete(c.items, id)
+	delete(c.transitions, id)
+	c.order = slices.DeleteFunc(c.order, func(v string) bool { return v == id })
+	return true
+}
+
+// transition schedules done to be applied to the resource with the given ID
+// after it has been read the given number of times. A negative number of
+// reads applies it at once.
+func (c *collection) transition(id string, reads int, done func(resource)) {
+	if reads < 0 {
+		done(c.items[id])
+		delete(c.transitions, id)
+		return
+	}
+	c.transitions[id] = &transition{reads: reads, done: done}
+}
+
+// advance counts a read of the resource with the given ID, applying its
+// pending transition when it is due.
+func (c *collection) advance(id string) {
+	t, ok := c.transitions[id]
+	if !ok {
+		return
+	}
+	if t.reads > 0 {
+		t.reads--
+		return
+	}
+	t.done(c.items[id])
+	delete(c.transitions, id)
+}
+
+// paginationParams are the query parameters which are not filters.
+var paginationParams = []string{"limit", "marker", "sort", "sort_key", "sort_dir", "fields", "page_reverse", "all_tenants"}
+
+// filter returns the resources whose scalar fields match the query
+// parameters of the request. Query parameters which do not correspond to a
+// scalar field are ignored.
+func filter(items []resource, query url.Values, ignored ...string) []resource {
+	var matching []resource
+	for _, item := range items {
+		if matches(item, query, ignored) {
+			matching = append(matching, item)
+		}
+	}
+	return matching
+}
+
+func matches(item resource, query url.Values, ignored []string) bool {
+	for key, values := range query {
+		if slices.Contains(paginationParams, key) || slices.Contains(ignored, key) {
+			continue
+		}
+		var actual string
+		switch v := item[key].(type) {
+		case string:
+			actual = v
+		case bool:
+			actual = strconv.FormatBool(v)
+		case float64:
+			actual = strconv.FormatFloat(v, 'f', -1, 64)
+		case int:
+			actual = strconv.Itoa(v)
+		default:
+			continue
+		}
+		if !slices.Contains(values, actual) {
+			return false
+		}
+	}
+	return true
+}
+
+// paginate returns the page of items selected by the limit and marker query
+// parameters of r, and the URL of the next page, if any.
+func paginate(baseURL string, r *http.Request, items []resource) ([]resource, string) {
+	query := r.URL.Query()
+	if marker := query.Get("marker"); marker != "" {
+		for i, item := range items {
+			if item["id"] == marker {
+				items = items[i+1:]
+				break
+			}
+		}
+	}
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 || limit >= len(items) {
+		return items, ""
+	}
+	items = items[:limit]
+	query.Set("marker", items[limit-1]["id"].(string))
+	return items, baseURL + r.URL.Path + "?" + query.Encode()
+}
+
+// nextLinks returns the links to the next page in the format of the
+// Compute, Networking and Block Storage APIs.
+func nextLinks(next string) []resource {
+	if next == "" {
+		return []resource{}
+	}
+	return []resource{{"href": next, "rel": "next"}}
+}
+
+// decode decodes the JSON body of r into v.
+func decode(r *http.Request, v any) error {
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// writeJSON writes v as the JSON body of a response with the given status
+// code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// faultNames are the names the Compute and Block Storage APIs give to the
+// errors they report.
+var faultNames = map[int]string{
+	http.StatusBadRequest: "badRequest",
+	http.StatusForbidden:  "forbidden",
+	http.StatusNotFound:   "itemNotFound",
+	http.StatusConflict:   "conflictingRequest",
+
+	http.StatusInternalServerError: "computeFault",
+}
+
+// writeFault writes an error in the format of the Compute and Block Storage
+// APIs.
+func writeFault(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, resource{
+		faultNames[status]: resource{
+			"code":    status,
+			"message": fmt.Sprintf(format, args...),
+		},
+	})
+}
+
+// newHexID returns a random ID in the format used by Keystone.
+func newHexID() string {
+	return strings.ReplaceAll(fakestore.NewID(), "-", "")
+}
+
+// copyFields copies the given fields of src to dst, when they are present.
+func copyFields(dst, src resource, fields ...string) {
+	for _, field := range fields {
+		if v, ok := src[field]; ok {
+			dst[field] = v
+		}
+	}
+}
+
+// stringField returns the field of r as a string, or "" if it is not a
+// string.
+func stringField(r resource, field string) string {
+	s, _ := r[field].(string)
+	return s
+}
@@ -0,0 +1,271 @@
+package fakecloud
+
+import (
+	"net/http"
+	"slices"
+	"time"
+)
+
+// keystoneTimeFormat is the format of the timestamps of the Identity API.
+const keystoneTimeFormat = "2006-01-02T15:04:05.000000Z"
+
+// token is a token issued by the Identity API.
+type token struct {
+	methods   []string
+	issuedAt  time.Time
+	expiresAt time.Time
+	// scope is "project", "domain" or "" for an unscoped token.
+	scope string
+}
+
+// validToken reports whether id is a token which is still valid.
+func (c *Cloud) validToken(id string) bool {
+	t, ok := c.tokens[id]
+	return ok && time.Now().Before(t.expiresAt)
+}
+
+func (c *Cloud) registerIdentity(mux *http.ServeMux) {
+	c.handle(mux, "GET /identity/{$}", false, c.identityVersions)
+	c.handle(mux, "GET /identity/v3/{$}", false, c.identityVersion)
+	c.handle(mux, "POST /identity/v3/auth/tokens", false, c.createToken)
+	c.handle(mux, "GET /identity/v3/auth/tokens", true, c.getToken)
+	c.handle(mux, "HEAD /identity/v3/auth/tokens", true, c.checkToken)
+	c.handle(mux, "DELETE /identity/v3/auth/tokens", true, c.revokeToken)
+}
+
+func (c *Cloud) identityVersionBody() resource {
+	return resource{
+		"id":      "v3.14",
+		"status":  "stable",
+		"updated": "2020-04-07T00:00:00Z",
+		"links": []resource{
+			{"rel": "self", "href": c.IdentityEndpoint()},
+		},
+		"media-types": []resource{
+			{"base": "application/json", "type": "application/vnd.openstack.identity-v3+json"},
+		},
+	}
+}
+
+func (c *Cloud) identityVersions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusMultipleChoices, resource{
+		"versions": resource{
+			"values": []resource{c.identityVersionBody()},
+		},
+	})
+}
+
+func (c *Cloud) identityVersion(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, resource{"version": c.identityVersionBody()})
+}
+
+// authRequest is the body of a token creation request, as far as the fake
+// cloud understands it.
+type authRequest struct {
+	Auth struct {
+		Identity struct {
+			Methods  []string `json:"methods"`
+			Password struct {
+				User struct {
+					namedItem
+					Password string    `json:"password"`
+					Domain   namedItem `json:"domain"`
+				} `json:"user"`
+			} `json:"password"`
+			Token struct {
+				ID string `json:"id"`
+			} `json:"token"`
+		} `json:"identity"`
+		Scope *struct {
+			Project *struct {
+				namedItem
+				Domain namedItem `json:"domain"`
+			} `json:"project"`
+			Domain *namedItem `json:"domain"`
+		} `json:"scope"`
+	} `json:"auth"`
+}
+
+// namedItem is a reference to an item by ID or by name.
+type namedItem struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// is reports whether the reference designates the item with the given ID
+// and name. An empty reference designates any item.
+func (n namedItem) is(id, name string) bool {
+	return (n.ID == "" || n.ID == id) && (n.Name == "" || n.Name == name)
+}
+
+// designates reports whether ref designates the user or project with the
+// given ID and name. Like Keystone, it requires the domain of a reference by
+// name.
+func (c *Cloud) designates(ref, domain namedItem, id, name string) bool {
+	switch {
+	case ref.ID != "":
+		return ref.ID == id
+	case ref.Name != "":
+		return ref.Name == name && (domain.ID != "" || domain.Name != "") && domain.is(c.domainID, c.opts.DomainName)
+	default:
+		return false
+	}
+}
+
+func (c *Cloud) createToken(w http.ResponseWriter, r *http.Request) {
+	var req authRequest
+	if err := decode(r, &req); err != nil {
+		writeIdentityError(w, http.StatusBadRequest, "Malformed request body: "+err.Error())
+		return
+	}
+
+	identity := req.Auth.Identity
+	authenticated := false
+	switch {
+	case slices.Contains(identity.Methods, "password"):
+		user := identity.Password.User
+		authenticated = c.designates(user.namedItem, user.Domain, c.userID, c.opts.Username) &&
+			user.Password == c.opts.Password
+	case slices.Contains(identity.Methods, "token"):
+		authenticated = c.validToken(identity.Token.ID)
+	}
+	if !authenticated {
+		writeIdentityError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+		return
+	}
+
+	t := &token{
+		methods:  identity.Methods,
+		issuedAt: time.Now().UTC(),
+	}
+	t.expiresAt = t.issuedAt.Add(c.opts.TokenTTL)
+	if scope := req.Auth.Scope; scope != nil {
+		switch {
+		case scope.Project != nil:
+			if !c.designates(scope.Project.namedItem, scope.Project.Domain, c.projectID, c.opts.ProjectName) {
+				writeIdentityError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+				return
+			}
+			t.scope = "project"
+		case scope.Domain != nil:
+			if (scope.Domain.ID == "" && scope.Domain.Name == "") || !scope.Domain.is(c.domainID, c.opts.DomainName) {
+				writeIdentityError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+				return
+			}
+			t.scope = "domain"
+		}
+	}
+
+	id := newHexID()
+	c.tokens[id] = t
+	w.Header().Set("X-Subject-Token", id)
+	writeJSON(w, http.StatusCreated, resource{"token": c.tokenBody(t)})
+}
+
+func (c *Cloud) getToken(w http.ResponseWriter, r *http.Request) {
+	subject := r.Header.Get("X-Subject-Token")
+	if !c.validToken(subject) {
+		writeIdentityError(w, http.StatusNotFound, "Could not find token: "+subject+".")
+		return
+	}
+	w.Header().Set("X-Subject-Token", subject)
+	writeJSON(w, http.StatusOK, resource{"token": c.tokenBody(c.tokens[subject])})
+}
+
+func (c *Cloud) checkToken(w http.ResponseWriter, r *http.Request) {
+	if !c.validToken(r.Header.Get("X-Subject-Token")) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *Cloud) revokeToken(w http.ResponseWriter, r *http.Request) {
+	subject := r.Header.Get("X-Subject-Token")
+	if !c.validToken(subject) {
+		writeIdentityError(w, http.StatusNotFound, "Could not find token: "+subject+".")
+		return
+	}
+	delete(c.tokens, subject)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// tokenBody returns the representation of a token.
+func (c *Cloud) tokenBody(t *token) resource {
+	domain := resource{"id": c.domainID, "name": c.opts.DomainName}
+	body := resource{
+		"methods":    t.methods,
+		"issued_at":  t.issuedAt.Format(keystoneTimeFormat),
+		"expires_at": t.expiresAt.Format(keystoneTimeFormat),
+		"audit_ids":  []string{newHexID()[:22]},
+		"user": resource{
+			"id":     c.userID,
+			"name":   c.opts.Username,
+			"domain": domain,
+		},
+	}
+
+	switch t.scope {
+	case "project":
+		body["project"] = resource{
+			"id":     c.projectID,
+			"name":   c.opts.ProjectName,
+			"domain": domain,
+		}
+	case "domain":
+		body["domain"] = domain
+	default:
+		return body
+	}
+	body["roles"] = []resource{
+		{"id": "5d3cc8ff4d8a4ce8b6bbbc1ee7bc2bbb", "name": "admin"},
+		{"id": "9fe2ff9ee4384b1894a90878d3e92bab", "name": "member"},
+	}
+	body["catalog"] = c.catalog()
+	return body
+}
+
+// catalog returns the service catalog of the cloud.
+func (c *Cloud) catalog() []resource {
+	services := []struct {
+		typ, name, path string
+	}{
+		{"identity", "keystone", "/identity/v3/"},
+		{"compute", "nova", "/compute/v2.1/"},
+		{"network", "neutron", "/network/"},
+		{"block-storage", "cinder", "/volume/v3/" + c.projectID + "/"},
+		{"image", "glance", "/image/"},
+	}
+
+	catalog := make([]resource, 0, len(services))
+	for _, service := range services {
+		var endpoints []resource
+		for _, iface := range []string{"public", "internal"} {
+			endpoints = append(endpoints, resource{
+				"id":        newHexID(),
+				"interface": iface,
+				"region":    c.opts.Region,
+				"region_id": c.opts.Region,
+				"url":       c.URL + service.path,
+			})
+		}
+		catalog = append(catalog, resource{
+			"id":        newHexID(),
+			"type":      service.typ,
+			"name":      service.name,
+			"endpoints": endpoints,
+		})
+	}
+	return catalog
+}
+
+// writeIdentityError writes an error in the format of the Identity API.
+func writeIdentityError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, resource{
+		"error": resource{
+			"code":    status,
+			"message": message,
+			"title":   http.StatusText(status),
+		},
+	})
+}
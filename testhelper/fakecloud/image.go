@@ -0,0 +1,260 @@
+package fakecloud
+
+import (
+	"crypto/md5"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2/internal/fakestore"
+)
+
+// glanceTimeFormat is the format of the timestamps of the Image API.
+const glanceTimeFormat = "2006-01-02T15:04:05Z"
+
+// readOnlyImageProperties are the properties of an image which cannot be
+// set by a creation or an update request.
+var readOnlyImageProperties = []string{
+	"status", "created_at", "updated_at", "checksum", "os_hash_algo", "os_hash_value",
+	"size", "virtual_size", "owner", "self", "file", "schema", "direct_url", "locations",
+}
+
+func (c *Cloud) registerImage(mux *http.ServeMux) {
+	const prefix = "/image/v2"
+
+	c.handle(mux, "GET /image/{$}", false, c.imageVersions)
+	c.handle(mux, "GET "+prefix+"/images", true, c.listImages)
+	c.handle(mux, "POST "+prefix+"/images", true, c.createImage)
+	c.handle(mux, "GET "+prefix+"/images/{id}", true, c.getImage)
+	c.handle(mux, "PATCH "+prefix+"/images/{id}", true, c.updateImage)
+	c.handle(mux, "DELETE "+prefix+"/images/{id}", true, c.deleteImage)
+	c.handle(mux, "PUT "+prefix+"/images/{id}/file", true, c.uploadImage)
+	c.handle(mux, "GET "+prefix+"/images/{id}/file", true, c.downloadImage)
+}
+
+func (c *Cloud) imageVersions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusMultipleChoices, resource{
+		"versions": []resource{{
+			"id":     "v2.17",
+			"status": "CURRENT",
+			"links": []resource{
+				{"rel": "self", "href": c.URL + "/image/v2/"},
+			},
+		}},
+	})
+}
+
+// seedImage creates the image every cloud starts with.
+func (c *Cloud) seedImage() {
+	image := c.newImage(resource{
+		"id":               CirrosImageID,
+		"name":             "cirros-0.6.2-x86_64-disk",
+		"container_format": "bare",
+		"disk_format":      "qcow2",
+		"visibility":       "public",
+	})
+	image["status"] = "active"
+	image["size"] = 21430272
+	c.images.put(image)
+}
+
+// newImage returns an image from an image creation request.
+func (c *Cloud) newImage(req resource) resource {
+	id := stringField(req, "id")
+	if id == "" {
+		id = fakestore.NewID()
+	}
+	now := time.Now().UTC().Format(glanceTimeFormat)
+	image := resource{
+		"id":               id,
+		"name":             nil,
+		"status":           "queued",
+		"visibility":       "shared",
+		"protected":        false,
+		"os_hidden":        false,
+		"checksum":         nil,
+		"os_hash_algo":     nil,
+		"os_hash_value":    nil,
+		"size":             nil,
+		"virtual_size":     nil,
+		"container_format": nil,
+		"disk_format":      nil,
+		"min_disk":         0,
+		"min_ram":          0,
+		"owner":            c.projectID,
+		"tags":             []any{},
+		"created_at":       now,
+		"updated_at":       now,
+		"self":             "/v2/images/" + id,
+		"file":             "/v2/images/" + id + "/file",
+		"schema":           "/v2/schemas/image",
+	}
+	for k, v := range req {
+		if k != "id" && !slices.Contains(readOnlyImageProperties, k) {
+			image[k] = v
+		}
+	}
+	return image
+}
+
+// writeGlanceError writes an error in the format of the Image API, which
+// reports errors as plain text.
+func writeGlanceError(w http.ResponseWriter, status int, format string, args ...any) {
+	http.Error(w, fmt.Sprintf("%d %s\n\n%s", status, http.StatusText(status), fmt.Sprintf(format, args...)), status)
+}
+
+func (c *Cloud) listImages(w http.ResponseWriter, r *http.Request) {
+	items := filter(c.images.list(), r.URL.Query())
+	items, next := paginate("", r, items)
+	body := resource{
+		"images": items,
+		"schema": "/v2/schemas/images",
+		"first":  "/v2/images",
+	}
+	if next != "" {
+		// the links of the Image API are relative to its root
+		body["next"] = strings.TrimPrefix(next, "/image")
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+func (c *Cloud) getImage(w http.ResponseWriter, r *http.Request) {
+	image, ok := c.images.get(r.PathValue("id"))
+	if !ok {
+		writeGlanceError(w, http.StatusNotFound, "No image found with ID %s", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, image)
+}
+
+func (c *Cloud) createImage(w http.ResponseWriter, r *http.Request) {
+	var req resource
+	if err := decode(r, &req); err != nil {
+		writeGlanceError(w, http.StatusBadRequest, "Malformed request body")
+		return
+	}
+	for _, k := range readOnlyImageProperties {
+		if _, ok := req[k]; ok {
+			writeGlanceError(w, http.StatusForbidden, "Attribute '%s' is read-only.", k)
+			return
+		}
+	}
+	image := c.newImage(req)
+	if _, ok := c.images.peek(image["id"].(string)); ok {
+		writeGlanceError(w, http.StatusConflict, "Image with identifier %s already exists!", image["id"])
+		return
+	}
+	c.images.put(image)
+	w.Header().Set("Location", c.URL+"/image/v2/images/"+image["id"].(string))
+	writeJSON(w, http.StatusCreated, image)
+}
+
+func (c *Cloud) updateImage(w http.ResponseWriter, r *http.Request) {
+	image, ok := c.images.peek(r.PathValue("id"))
+	if !ok {
+		writeGlanceError(w, http.StatusNotFound, "No image found with ID %s", r.PathValue("id"))
+		return
+	}
+	var patch []struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}
+	if err := decode(r, &patch); err != nil {
+		writeGlanceError(w, http.StatusBadRequest, "Malformed request body")
+		return
+	}
+
+	// the patch is validated before it is applied, so that it is atomic
+	for _, op := range patch {
+		key := strings.TrimPrefix(op.Path, "/")
+		if key == "id" || slices.Contains(readOnlyImageProperties, key) {
+			writeGlanceError(w, http.StatusForbidden, "Attribute '%s' is read-only.", key)
+			return
+		}
+		if op.Op != "add" && op.Op != "replace" && op.Op != "remove" {
+			writeGlanceError(w, http.StatusBadRequest, "Unable to find '%s' in JSON Schema change", op.Op)
+			return
+		}
+	}
+	for _, op := range patch {
+		key := strings.TrimPrefix(op.Path, "/")
+		if op.Op == "remove" {
+			delete(image, key)
+		} else {
+			image[key] = op.Value
+		}
+	}
+	image["updated_at"] = time.Now().UTC().Format(glanceTimeFormat)
+	writeJSON(w, http.StatusOK, image)
+}
+
+func (c *Cloud) deleteImage(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	image, ok := c.images.peek(id)
+	if !ok {
+		writeGlanceError(w, http.StatusNotFound, "No image found with ID %s", id)
+		return
+	}
+	if image["protected"] == true {
+		writeGlanceError(w, http.StatusForbidden, "Image %s is protected and cannot be deleted.", id)
+		return
+	}
+	c.images.delete(id)
+	delete(c.imageData, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Cloud) uploadImage(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	image, ok := c.images.peek(id)
+	if !ok {
+		writeGlanceError(w, http.StatusNotFound, "No image found with ID %s", id)
+		return
+	}
+	if image["status"] != "queued" {
+		writeGlanceError(w, http.StatusConflict, "Image status transition from %s to saving is not allowed", image["status"])
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeGlanceError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	checksum := md5.Sum(data)
+	hash := sha512.Sum512(data)
+	c.imageData[id] = data
+	image["status"] = "saving"
+	image["size"] = len(data)
+	image["checksum"] = hex.EncodeToString(checksum[:])
+	image["os_hash_algo"] = "sha512"
+	image["os_hash_value"] = hex.EncodeToString(hash[:])
+	image["updated_at"] = time.Now().UTC().Format(glanceTimeFormat)
+	c.images.transition(id, c.opts.PendingReads, func(i resource) {
+		i["status"] = "active"
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Cloud) downloadImage(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	image, ok := c.images.peek(id)
+	if !ok {
+		writeGlanceError(w, http.StatusNotFound, "No image found with ID %s", id)
+		return
+	}
+	data, ok := c.imageData[id]
+	if !ok || image["status"] != "active" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Md5", stringField(image, "checksum"))
+	_, _ = w.Write(data)
+}
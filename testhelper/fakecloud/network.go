@@ -0,0 +1,601 @@
+package fakecloud
+
+import (
+	"crypto/rand"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2/internal/fakestore"
+)
+
+// neutronTimeFormat is the format of the timestamps of the Networking API.
+const neutronTimeFormat = "2006-01-02T15:04:05Z"
+
+// apiError is an error reported by an API of the cloud, to be written in the
+// format of the API which reports it.
+type apiError struct {
+	status  int
+	typ     string
+	message string
+}
+
+func newAPIError(status int, typ, format string, args ...any) *apiError {
+	return &apiError{status: status, typ: typ, message: fmt.Sprintf(format, args...)}
+}
+
+// writeNeutronError writes an error in the format of the Networking API.
+func writeNeutronError(w http.ResponseWriter, err *apiError) {
+	writeJSON(w, err.status, resource{
+		"NeutronError": resource{
+			"type":    err.typ,
+			"message": err.message,
+			"detail":  "",
+		},
+	})
+}
+
+func (c *Cloud) registerNetwork(mux *http.ServeMux) {
+	const prefix = "/network/v2.0/"
+
+	c.handle(mux, "GET /network/{$}", false, c.networkVersions)
+	c.registerNeutronResource(mux, prefix+"networks", "network", "Network", c.networks, nil, c.createNetwork, c.updateNetwork, c.deleteNetwork)
+	c.registerNeutronResource(mux, prefix+"subnets", "subnet", "Subnet", c.subnets, nil, c.createSubnet, c.updateSubnet, c.deleteSubnet)
+	c.registerNeutronResource(mux, prefix+"ports", "port", "Port", c.ports, nil, c.createPort, c.updatePort, c.deletePort)
+	c.registerNeutronResource(mux, prefix+"security-groups", "security_group", "SecurityGroup", c.securityGroups, c.securityGroupView, c.createSecurityGroup, c.updateSecurityGroup, c.deleteSecurityGroup)
+	c.registerNeutronResource(mux, prefix+"security-group-rules", "security_group_rule", "SecurityGroupRule", c.securityGroupRules, nil, c.createSecurityGroupRule, nil, c.deleteSecurityGroupRule)
+}
+
+func (c *Cloud) networkVersions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, resource{
+		"versions": []resource{{
+			"id":     "v2.0",
+			"status": "CURRENT",
+			"links": []resource{
+				{"rel": "self", "href": c.URL + "/network/v2.0/"},
+			},
+		}},
+	})
+}
+
+// registerNeutronResource registers the handlers of a resource of the
+// Networking API on mux. view, if set, returns the representation of a
+// resource; update may be nil for resources which cannot be updated. The
+// create, update and delete functions are called with the lock of the cloud
+// held.
+func (c *Cloud) registerNeutronResource(
+	mux *http.ServeMux, path, singular, typeName string, coll *collection,
+	view func(resource) resource,
+	create func(req resource) (resource, *apiError),
+	update func(current, req resource) *apiError,
+	remove func(current resource) *apiError,
+) {
+	if view == nil {
+		view = func(r resource) resource { return r }
+	}
+	plural := path[strings.LastIndex(path, "/")+1:]
+	plural = strings.ReplaceAll(plural, "-", "_")
+	notFound := func(id string) *apiError {
+		return newAPIError(http.StatusNotFound, typeName+"NotFound", "%s %s could not be found.", typeName, id)
+	}
+	decodeRequest := func(w http.ResponseWriter, r *http.Request) (resource, bool) {
+		var body map[string]resource
+		if err := decode(r, &body); err != nil || body[singular] == nil {
+			writeNeutronError(w, newAPIError(http.StatusBadRequest, "BadRequest", "Bad %s request: Resource body required.", plural))
+			return nil, false
+		}
+		return body[singular], true
+	}
+
+	c.handle(mux, "GET "+path, true, func(w http.ResponseWriter, r *http.Request) {
+		items := filter(coll.list(), r.URL.Query())
+		items, next := paginate(c.URL, r, items)
+		views := make([]resource, 0, len(items))
+		for _, item := range items {
+			views = append(views, view(item))
+		}
+		writeList(w, plural, views, next)
+	})
+	c.handle(mux, "GET "+path+"/{id}", true, func(w http.ResponseWriter, r *http.Request) {
+		item, ok := coll.get(r.PathValue("id"))
+		if !ok {
+			writeNeutronError(w, notFound(r.PathValue("id")))
+			return
+		}
+		writeJSON(w, http.StatusOK, resource{singular: view(item)})
+	})
+	c.handle(mux, "POST "+path, true, func(w http.ResponseWriter, r *http.Request) {
+		req, ok := decodeRequest(w, r)
+		if !ok {
+			return
+		}
+		item, err := create(req)
+		if err != nil {
+			writeNeutronError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, resource{singular: view(item)})
+	})
+	if update != nil {
+		c.handle(mux, "PUT "+path+"/{id}", true, func(w http.ResponseWriter, r *http.Request) {
+			item, ok := coll.peek(r.PathValue("id"))
+			if !ok {
+				writeNeutronError(w, notFound(r.PathValue("id")))
+				return
+			}
+			req, ok := decodeRequest(w, r)
+			if !ok {
+				return
+			}
+			if err := update(item, req); err != nil {
+				writeNeutronError(w, err)
+				return
+			}
+			item["revision_number"] = item["revision_number"].(int) + 1
+			item["updated_at"] = time.Now().UTC().Format(neutronTimeFormat)
+			writeJSON(w, http.StatusOK, resource{singular: view(item)})
+		})
+	}
+	c.handle(mux, "DELETE "+path+"/{id}", true, func(w http.ResponseWriter, r *http.Request) {
+		item, ok := coll.peek(r.PathValue("id"))
+		if !ok {
+			writeNeutronError(w, notFound(r.PathValue("id")))
+			return
+		}
+		if err := remove(item); err != nil {
+			writeNeutronError(w, err)
+			return
+		}
+		coll.delete(r.PathValue("id"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// writeList writes a list of resources in the format of the Compute,
+// Networking and Block Storage APIs.
+func writeList(w http.ResponseWriter, plural string, items []resource, next string) {
+	body := resource{plural: items}
+	if next != "" {
+		body[plural+"_links"] = nextLinks(next)
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// newNeutronResource returns a resource with the fields shared by all the
+// resources of the Networking API.
+func (c *Cloud) newNeutronResource(req resource) resource {
+	now := time.Now().UTC().Format(neutronTimeFormat)
+	r := resource{
+		"id":              fakestore.NewID(),
+		"name":            "",
+		"description":     "",
+		"tenant_id":       c.projectID,
+		"project_id":      c.projectID,
+		"tags":            []string{},
+		"revision_number": 1,
+		"created_at":      now,
+		"updated_at":      now,
+	}
+	copyFields(r, req, "name", "description")
+	if projectID := stringField(req, "project_id"); projectID != "" {
+		r["tenant_id"], r["project_id"] = projectID, projectID
+	} else if tenantID := stringField(req, "tenant_id"); tenantID != "" {
+		r["tenant_id"], r["project_id"] = tenantID, tenantID
+	}
+	return r
+}
+
+// seedNetwork creates the private network, its subnet and the default
+// security group every cloud starts with.
+func (c *Cloud) seedNetwork() {
+	network, _ := c.createNetwork(resource{"name": "private"})
+	_, _ = c.createSubnet(resource{
+		"name":       "private-subnet",
+		"network_id": network["id"],
+		"cidr":       "10.0.0.0/24",
+	})
+	_, _ = c.createSecurityGroup(resource{
+		"name":        "default",
+		"description": "Default security group",
+	})
+}
+
+func (c *Cloud) createNetwork(req resource) (resource, *apiError) {
+	network := c.newNeutronResource(req)
+	network["admin_state_up"] = true
+	network["status"] = "ACTIVE"
+	network["subnets"] = []string{}
+	network["shared"] = false
+	network["router:external"] = false
+	network["mtu"] = 1500
+	network["port_security_enabled"] = true
+	network["availability_zone_hints"] = []string{}
+	network["availability_zones"] = []string{"nova"}
+	copyFields(network, req, "admin_state_up", "shared", "router:external", "mtu", "port_security_enabled", "availability_zone_hints")
+	c.networks.put(network)
+	return network, nil
+}
+
+func (c *Cloud) updateNetwork(network, req resource) *apiError {
+	copyFields(network, req, "name", "description", "admin_state_up", "shared", "router:external", "mtu", "port_security_enabled")
+	return nil
+}
+
+func (c *Cloud) deleteNetwork(network resource) *apiError {
+	id := network["id"].(string)
+	inUse := c.ports.find(func(p resource) bool {
+		return p["network_id"] == id && !strings.HasPrefix(stringField(p, "device_owner"), "network:")
+	})
+	if len(inUse) > 0 {
+		return newAPIError(http.StatusConflict, "NetworkInUse", "Unable to complete operation on network %s. There are one or more ports still in use on the network.", id)
+	}
+	for _, subnetID := range network["subnets"].([]string) {
+		c.subnets.delete(subnetID)
+	}
+	return nil
+}
+
+func (c *Cloud) createSubnet(req resource) (resource, *apiError) {
+	networkID := stringField(req, "network_id")
+	network, ok := c.networks.peek(networkID)
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, "NetworkNotFound", "Network %s could not be found.", networkID)
+	}
+	prefix, err := netip.ParsePrefix(stringField(req, "cidr"))
+	if err != nil {
+		return nil, newAPIError(http.StatusBadRequest, "HTTPBadRequest", "Invalid input for cidr. Reason: '%s' is not a valid IP subnet.", stringField(req, "cidr"))
+	}
+	prefix = prefix.Masked()
+
+	first := prefix.Addr().Next()
+	last := lastAddr(prefix)
+	ipVersion := 4
+	if prefix.Addr().Is6() {
+		ipVersion = 6
+	}
+
+	subnet := c.newNeutronResource(req)
+	subnet["network_id"] = networkID
+	subnet["cidr"] = prefix.String()
+	subnet["ip_version"] = ipVersion
+	subnet["gateway_ip"] = first.String()
+	subnet["allocation_pools"] = []resource{{"start": first.Next().String(), "end": last.Prev().String()}}
+	subnet["enable_dhcp"] = true
+	subnet["dns_nameservers"] = []string{}
+	subnet["host_routes"] = []resource{}
+	subnet["subnetpool_id"] = nil
+	subnet["ipv6_address_mode"] = nil
+	subnet["ipv6_ra_mode"] = nil
+	copyFields(subnet, req, "gateway_ip", "allocation_pools", "enable_dhcp", "dns_nameservers", "host_routes", "ipv6_address_mode", "ipv6_ra_mode")
+	c.subnets.put(subnet)
+
+	network["subnets"] = append(network["subnets"].([]string), subnet["id"].(string))
+	return subnet, nil
+}
+
+func (c *Cloud) updateSubnet(subnet, req resource) *apiError {
+	copyFields(subnet, req, "name", "description", "gateway_ip", "allocation_pools", "enable_dhcp", "dns_nameservers", "host_routes")
+	return nil
+}
+
+func (c *Cloud) deleteSubnet(subnet resource) *apiError {
+	id := subnet["id"].(string)
+	inUse := c.ports.find(func(p resource) bool {
+		return slices.ContainsFunc(p["fixed_ips"].([]resource), func(ip resource) bool {
+			return ip["subnet_id"] == id
+		})
+	})
+	if len(inUse) > 0 {
+		return newAPIError(http.StatusConflict, "SubnetInUse", "Unable to complete operation on subnet %s: One or more ports have an IP allocation from this subnet.", id)
+	}
+	if network, ok := c.networks.peek(stringField(subnet, "network_id")); ok {
+		network["subnets"] = slices.DeleteFunc(network["subnets"].([]string), func(v string) bool { return v == id })
+	}
+	delete(c.nextIP, id)
+	return nil
+}
+
+func (c *Cloud) createPort(req resource) (resource, *apiError) {
+	networkID := stringField(req, "network_id")
+	network, ok := c.networks.peek(networkID)
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, "NetworkNotFound", "Network %s could not be found.", networkID)
+	}
+
+	port := c.newNeutronResource(req)
+	port["network_id"] = networkID
+	port["admin_state_up"] = true
+	port["mac_address"] = newMACAddress()
+	port["device_id"] = ""
+	port["device_owner"] = ""
+	port["allowed_address_pairs"] = []resource{}
+	port["extra_dhcp_opts"] = []resource{}
+	port["binding:vnic_type"] = "normal"
+	port["port_security_enabled"] = network["port_security_enabled"]
+	copyFields(port, req, "admin_state_up", "mac_address", "device_id", "device_owner", "allowed_address_pairs", "port_security_enabled")
+
+	var requested []any
+	if fixedIPs, ok := req["fixed_ips"].([]any); ok {
+		requested = fixedIPs
+	} else {
+		for _, subnetID := range network["subnets"].([]string) {
+			requested = append(requested, map[string]any{"subnet_id": subnetID})
+		}
+	}
+	fixedIPs, err := c.allocateIPs(network, requested)
+	if err != nil {
+		return nil, err
+	}
+	port["fixed_ips"] = fixedIPs
+
+	securityGroups := []string{}
+	if port["port_security_enabled"] == true && !strings.HasPrefix(stringField(port, "device_owner"), "network:") {
+		if defaults := c.securityGroups.find(func(sg resource) bool { return sg["name"] == "default" }); len(defaults) > 0 {
+			securityGroups = append(securityGroups, defaults[0]["id"].(string))
+		}
+	}
+	port["security_groups"] = securityGroups
+	if err := c.setPortSecurityGroups(port, req); err != nil {
+		return nil, err
+	}
+
+	setPortStatus(port)
+	c.ports.put(port)
+	return port, nil
+}
+
+func (c *Cloud) updatePort(port, req resource) *apiError {
+	if fixedIPs, ok := req["fixed_ips"].([]any); ok {
+		network, _ := c.networks.peek(stringField(port, "network_id"))
+		previous := port["fixed_ips"]
+		// the current addresses of the port can be requested again
+		port["fixed_ips"] = []resource{}
+		allocated, err := c.allocateIPs(network, fixedIPs)
+		if err != nil {
+			port["fixed_ips"] = previous
+			return err
+		}
+		port["fixed_ips"] = allocated
+	}
+	if err := c.setPortSecurityGroups(port, req); err != nil {
+		return err
+	}
+	copyFields(port, req, "name", "description", "admin_state_up", "mac_address", "device_id", "device_owner", "allowed_address_pairs", "port_security_enabled")
+	setPortStatus(port)
+	return nil
+}
+
+func (c *Cloud) deletePort(port resource) *apiError {
+	return nil
+}
+
+// setPortSecurityGroups sets the security groups of a port from a port
+// creation or update request.
+func (c *Cloud) setPortSecurityGroups(port, req resource) *apiError {
+	groups, ok := req["security_groups"].([]any)
+	if !ok {
+		return nil
+	}
+	ids := make([]string, 0, len(groups))
+	for _, group := range groups {
+		id, _ := group.(string)
+		if _, ok := c.securityGroups.peek(id); !ok {
+			return newAPIError(http.StatusNotFound, "SecurityGroupNotFound", "Security group %s does not exist", id)
+		}
+		ids = append(ids, id)
+	}
+	port["security_groups"] = ids
+	return nil
+}
+
+// setPortStatus sets the status of a port from its binding to a device.
+func setPortStatus(port resource) {
+	if stringField(port, "device_id") != "" {
+		port["status"] = "ACTIVE"
+	} else {
+		port["status"] = "DOWN"
+	}
+}
+
+// allocateIPs allocates the fixed IPs requested for a port of the given
+// network. Each requested IP may set a subnet_id, an ip_address or both.
+func (c *Cloud) allocateIPs(network resource, requested []any) ([]resource, *apiError) {
+	var allocated []resource
+	for _, req := range resources(requested) {
+		subnetID := stringField(req, "subnet_id")
+		address := stringField(req, "ip_address")
+
+		var subnet resource
+		for _, id := range network["subnets"].([]string) {
+			candidate, _ := c.subnets.peek(id)
+			prefix := netip.MustParsePrefix(candidate["cidr"].(string))
+			if (subnetID == "" || subnetID == id) && (address == "" || prefixContains(prefix, address)) {
+				subnet = candidate
+				break
+			}
+		}
+		if subnet == nil {
+			return nil, newAPIError(http.StatusBadRequest, "InvalidInput", "Invalid input for operation: No subnet of network %s matches the requested fixed IP.", network["id"])
+		}
+
+		if address == "" {
+			addr, err := c.nextFreeIP(subnet)
+			if err != nil {
+				return nil, err
+			}
+			address = addr.String()
+		} else if c.ipInUse(subnet["id"].(string), address) {
+			return nil, newAPIError(http.StatusConflict, "IpAddressAlreadyAllocated", "IP address %s already allocated in subnet %s", address, subnet["id"])
+		}
+		allocated = append(allocated, resource{"subnet_id": subnet["id"], "ip_address": address})
+	}
+	if allocated == nil {
+		allocated = []resource{}
+	}
+	return allocated, nil
+}
+
+// nextFreeIP returns the first address of the allocation pools of a subnet
+// which is not in use, starting after the last allocated one.
+func (c *Cloud) nextFreeIP(subnet resource) (netip.Addr, *apiError) {
+	id := subnet["id"].(string)
+	pools := resources(subnet["allocation_pools"])
+	if len(pools) == 0 {
+		return netip.Addr{}, newAPIError(http.StatusConflict, "IpAddressGenerationFailure", "No more IP addresses available on network %s.", subnet["network_id"])
+	}
+	start := netip.MustParseAddr(stringField(pools[0], "start"))
+	end := netip.MustParseAddr(stringField(pools[0], "end"))
+
+	addr, ok := c.nextIP[id]
+	if !ok || addr.Less(start) || end.Less(addr) {
+		addr = start
+	}
+	for candidate, tried := addr, 0; tried < 1<<16; tried++ {
+		if !c.ipInUse(id, candidate.String()) {
+			c.nextIP[id] = candidate.Next()
+			return candidate, nil
+		}
+		candidate = candidate.Next()
+		if end.Less(candidate) {
+			candidate = start
+		}
+		if candidate == addr {
+			break
+		}
+	}
+	return netip.Addr{}, newAPIError(http.StatusConflict, "IpAddressGenerationFailure", "No more IP addresses available on network %s.", subnet["network_id"])
+}
+
+// ipInUse reports whether the address is allocated to a port in the subnet.
+func (c *Cloud) ipInUse(subnetID, address string) bool {
+	return len(c.ports.find(func(p resource) bool {
+		return slices.ContainsFunc(p["fixed_ips"].([]resource), func(ip resource) bool {
+			return ip["subnet_id"] == subnetID && ip["ip_address"] == address
+		})
+	})) > 0
+}
+
+func (c *Cloud) createSecurityGroup(req resource) (resource, *apiError) {
+	group := c.newNeutronResource(req)
+	group["stateful"] = true
+	copyFields(group, req, "stateful")
+	c.securityGroups.put(group)
+
+	for _, ethertype := range []string{"IPv4", "IPv6"} {
+		_, _ = c.createSecurityGroupRule(resource{
+			"security_group_id": group["id"],
+			"direction":         "egress",
+			"ethertype":         ethertype,
+		})
+	}
+	return group, nil
+}
+
+// securityGroupView returns the representation of a security group, which
+// includes its rules.
+func (c *Cloud) securityGroupView(group resource) resource {
+	view := maps.Clone(group)
+	rules := c.securityGroupRules.find(func(rule resource) bool {
+		return rule["security_group_id"] == group["id"]
+	})
+	if rules == nil {
+		rules = []resource{}
+	}
+	view["security_group_rules"] = rules
+	return view
+}
+
+func (c *Cloud) updateSecurityGroup(group, req resource) *apiError {
+	copyFields(group, req, "name", "description", "stateful")
+	return nil
+}
+
+func (c *Cloud) deleteSecurityGroup(group resource) *apiError {
+	id := group["id"].(string)
+	inUse := c.ports.find(func(p resource) bool {
+		return slices.Contains(p["security_groups"].([]string), id)
+	})
+	if len(inUse) > 0 {
+		return newAPIError(http.StatusConflict, "SecurityGroupInUse", "Security Group %s in use.", id)
+	}
+	for _, rule := range c.securityGroupRules.find(func(rule resource) bool { return rule["security_group_id"] == id }) {
+		c.securityGroupRules.delete(rule["id"].(string))
+	}
+	return nil
+}
+
+func (c *Cloud) createSecurityGroupRule(req resource) (resource, *apiError) {
+	groupID := stringField(req, "security_group_id")
+	if _, ok := c.securityGroups.peek(groupID); !ok {
+		return nil, newAPIError(http.StatusNotFound, "SecurityGroupNotFound", "Security group %s does not exist", groupID)
+	}
+	direction := stringField(req, "direction")
+	if direction != "ingress" && direction != "egress" {
+		return nil, newAPIError(http.StatusBadRequest, "HTTPBadRequest", "Invalid input for direction. Reason: '%s' is not in ['ingress', 'egress'].", direction)
+	}
+
+	rule := c.newNeutronResource(req)
+	delete(rule, "name")
+	rule["security_group_id"] = groupID
+	rule["direction"] = direction
+	rule["ethertype"] = "IPv4"
+	rule["protocol"] = nil
+	rule["port_range_min"] = nil
+	rule["port_range_max"] = nil
+	rule["remote_ip_prefix"] = nil
+	rule["remote_group_id"] = nil
+	copyFields(rule, req, "ethertype", "protocol", "port_range_min", "port_range_max", "remote_ip_prefix", "remote_group_id")
+	c.securityGroupRules.put(rule)
+	return rule, nil
+}
+
+func (c *Cloud) deleteSecurityGroupRule(rule resource) *apiError {
+	return nil
+}
+
+// resources converts a list of JSON objects, as decoded from a request, into
+// a list of resources.
+func resources(v any) []resource {
+	switch v := v.(type) {
+	case []resource:
+		return v
+	case []any:
+		items := make([]resource, 0, len(v))
+		for _, item := range v {
+			if r, ok := item.(map[string]any); ok {
+				items = append(items, r)
+			}
+		}
+		return items
+	default:
+		return nil
+	}
+}
+
+// newMACAddress returns a random MAC address with the prefix used by
+// Neutron.
+func newMACAddress() string {
+	var b [3]byte
+	_, _ = rand.Read(b[:])
+	return fmt.Sprintf("fa:16:3e:%02x:%02x:%02x", b[0], b[1], b[2])
+}
+
+// lastAddr returns the last address of a prefix.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	b := prefix.Addr().AsSlice()
+	for i := prefix.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 1 << (7 - i%8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
+
+// prefixContains reports whether address is a valid address of prefix.
+func prefixContains(prefix netip.Prefix, address string) bool {
+	addr, err := netip.ParseAddr(address)
+	return err == nil && prefix.Contains(addr)
+}
@@ -0,0 +1,230 @@
+package testing
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack"
+	"github.com/gophercloud/gophercloud/v2/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/keypairs"
+	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/v2/openstack/image/v2/imagedata"
+	"github.com/gophercloud/gophercloud/v2/openstack/image/v2/images"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/ports"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/subnets"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/testhelper/fakecloud"
+)
+
+// newContext returns a context whose Poller polls without delay.
+func newContext() context.Context {
+	return gophercloud.WithPoller(context.TODO(), &gophercloud.Poller{
+		InitialInterval: time.Millisecond,
+		Timeout:         5 * time.Second,
+	})
+}
+
+func newProvider(t *testing.T, cloud *fakecloud.Cloud) *gophercloud.ProviderClient {
+	provider, err := openstack.AuthenticatedClient(context.TODO(), cloud.AuthOptions())
+	th.AssertNoErr(t, err)
+	return provider
+}
+
+func TestAuthentication(t *testing.T) {
+	cloud := fakecloud.New(fakecloud.Options{})
+	defer cloud.Close()
+
+	provider := newProvider(t, cloud)
+	compute, err := openstack.NewComputeV2(context.TODO(), provider, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, cloud.URL+"/compute/v2.1/", compute.Endpoint)
+
+	// the client reauthenticates once its token expires
+	cloud.ExpireTokens()
+	_, err = servers.List(compute, nil).AllPages(context.TODO())
+	th.AssertNoErr(t, err)
+
+	opts := cloud.AuthOptions()
+	opts.Password = "wrong"
+	_, err = openstack.AuthenticatedClient(context.TODO(), opts)
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusUnauthorized))
+}
+
+func TestServerLifecycle(t *testing.T) {
+	cloud := fakecloud.New(fakecloud.Options{PendingReads: 2})
+	defer cloud.Close()
+	ctx := newContext()
+
+	provider := newProvider(t, cloud)
+	compute, err := openstack.NewComputeV2(ctx, provider, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+	network, err := openstack.NewNetworkV2(ctx, provider, gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+
+	net, err := networks.Create(ctx, network, networks.CreateOpts{Name: "app"}).Extract()
+	th.AssertNoErr(t, err)
+	subnet, err := subnets.Create(ctx, network, subnets.CreateOpts{
+		NetworkID: net.ID,
+		CIDR:      "192.168.10.0/24",
+		IPVersion: gophercloud.IPv4,
+	}).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "192.168.10.1", subnet.GatewayIP)
+
+	created, err := servers.Create(ctx, compute, servers.CreateOpts{
+		Name:      "web",
+		ImageRef:  fakecloud.CirrosImageID,
+		FlavorRef: "1",
+		Networks:  []servers.Network{{UUID: net.ID}},
+	}, nil).Extract()
+	th.AssertNoErr(t, err)
+
+	server, err := servers.Get(ctx, compute, created.ID).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "BUILD", server.Status)
+
+	th.AssertNoErr(t, servers.WaitForStatus(ctx, compute, created.ID, "ACTIVE"))
+	server, err = servers.Get(ctx, compute, created.ID).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "ACTIVE", server.Status)
+	th.AssertEquals(t, "192.168.10.2", server.Addresses["app"].([]any)[0].(map[string]any)["addr"])
+
+	pages, err := ports.List(network, ports.ListOpts{DeviceID: created.ID}).AllPages(ctx)
+	th.AssertNoErr(t, err)
+	serverPorts, err := ports.ExtractPorts(pages)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(serverPorts))
+	th.AssertEquals(t, "ACTIVE", serverPorts[0].Status)
+	th.AssertEquals(t, net.ID, serverPorts[0].NetworkID)
+
+	err = networks.Delete(ctx, network, net.ID).ExtractErr()
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusConflict))
+
+	th.AssertNoErr(t, servers.Stop(ctx, compute, created.ID).ExtractErr())
+	server, err = servers.Get(ctx, compute, created.ID).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "SHUTOFF", server.Status)
+
+	th.AssertNoErr(t, servers.Delete(ctx, compute, created.ID).ExtractErr())
+	_, err = servers.Get(ctx, compute, created.ID).Extract()
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusNotFound))
+
+	// the port of the server is deleted with it
+	th.AssertNoErr(t, networks.Delete(ctx, network, net.ID).ExtractErr())
+	_, err = subnets.Get(ctx, network, subnet.ID).Extract()
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusNotFound))
+}
+
+func TestServerValidation(t *testing.T) {
+	cloud := fakecloud.New(fakecloud.Options{})
+	defer cloud.Close()
+	ctx := newContext()
+
+	compute, err := openstack.NewComputeV2(ctx, newProvider(t, cloud), gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+
+	_, err = servers.Create(ctx, compute, servers.CreateOpts{
+		Name:      "web",
+		ImageRef:  fakecloud.CirrosImageID,
+		FlavorRef: "missing",
+	}, nil).Extract()
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusBadRequest))
+
+	keypair, err := keypairs.Create(ctx, compute, keypairs.CreateOpts{Name: "deployer"}).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, strings.HasPrefix(keypair.PublicKey, "ssh-ed25519 "))
+	th.AssertEquals(t, true, strings.Contains(keypair.PrivateKey, "PRIVATE KEY"))
+
+	created, err := servers.Create(ctx, compute, servers.CreateOpts{
+		Name:      "web",
+		ImageRef:  fakecloud.CirrosImageID,
+		FlavorRef: "1",
+		KeyName:   "deployer",
+	}, nil).Extract()
+	th.AssertNoErr(t, err)
+
+	// the server is attached to the private network by default
+	server, err := servers.Get(ctx, compute, created.ID).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "deployer", server.KeyName)
+	th.AssertEquals(t, 1, len(server.Addresses["private"].([]any)))
+}
+
+func TestVolumeLifecycle(t *testing.T) {
+	cloud := fakecloud.New(fakecloud.Options{})
+	defer cloud.Close()
+	ctx := newContext()
+
+	blockStorage, err := openstack.NewBlockStorageV3(ctx, newProvider(t, cloud), gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+
+	created, err := volumes.Create(ctx, blockStorage, volumes.CreateOpts{
+		Name:    "boot",
+		Size:    10,
+		ImageID: fakecloud.CirrosImageID,
+	}, nil).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "creating", created.Status)
+	th.AssertEquals(t, "true", created.Bootable)
+
+	// a volume cannot be deleted while it is being created
+	err = volumes.Delete(ctx, blockStorage, created.ID, nil).ExtractErr()
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusBadRequest))
+
+	th.AssertNoErr(t, volumes.WaitForStatus(ctx, blockStorage, created.ID, "available"))
+
+	pages, err := volumes.List(blockStorage, volumes.ListOpts{Name: "boot"}).AllPages(ctx)
+	th.AssertNoErr(t, err)
+	all, err := volumes.ExtractVolumes(pages)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(all))
+	th.AssertEquals(t, 10, all[0].Size)
+
+	th.AssertNoErr(t, volumes.Delete(ctx, blockStorage, created.ID, nil).ExtractErr())
+	_, err = volumes.Get(ctx, blockStorage, created.ID).Extract()
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusNotFound))
+}
+
+func TestImageLifecycle(t *testing.T) {
+	cloud := fakecloud.New(fakecloud.Options{})
+	defer cloud.Close()
+	ctx := newContext()
+
+	image, err := openstack.NewImageV2(ctx, newProvider(t, cloud), gophercloud.EndpointOpts{})
+	th.AssertNoErr(t, err)
+
+	created, err := images.Create(ctx, image, images.CreateOpts{
+		Name:            "ubuntu",
+		ContainerFormat: "bare",
+		DiskFormat:      "qcow2",
+		Properties:      map[string]string{"os_distro": "ubuntu"},
+	}).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, images.ImageStatusQueued, created.Status)
+	th.AssertEquals(t, "ubuntu", created.Properties["os_distro"])
+
+	th.AssertNoErr(t, imagedata.Upload(ctx, image, created.ID, strings.NewReader("image data")).ExtractErr())
+	th.AssertNoErr(t, images.WaitForStatus(ctx, image, created.ID, images.ImageStatusActive))
+
+	updated, err := images.Update(ctx, image, created.ID, images.UpdateOpts{
+		images.ReplaceImageName{NewName: "ubuntu-24.04"},
+	}).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "ubuntu-24.04", updated.Name)
+	th.AssertEquals(t, int64(10), updated.SizeBytes)
+
+	pages, err := images.List(image, images.ListOpts{Limit: 1}).AllPages(ctx)
+	th.AssertNoErr(t, err)
+	all, err := images.ExtractImages(pages)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(all))
+
+	th.AssertNoErr(t, images.Delete(ctx, image, created.ID).ExtractErr())
+	_, err = images.Get(ctx, image, created.ID).Extract()
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusNotFound))
+}
@@ -0,0 +1,141 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+func TestParseFault(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected *gophercloud.Fault
+	}{
+		{
+			name: "nova",
+			body: `{"badRequest": {"message": "Invalid flavorRef provided.", "code": 400}}`,
+			expected: &gophercloud.Fault{
+				Type:    "badRequest",
+				Message: "Invalid flavorRef provided.",
+				Code:    400,
+			},
+		},
+		{
+			name: "cinder",
+			body: `{"itemNotFound": {"message": "Volume abc could not be found.", "code": 404}}`,
+			expected: &gophercloud.Fault{
+				Type:    "itemNotFound",
+				Message: "Volume abc could not be found.",
+				Code:    404,
+			},
+		},
+		{
+			name: "neutron",
+			body: `{"NeutronError": {"type": "OverQuota", "message": "Quota exceeded for resources: ['port'].", "detail": ""}}`,
+			expected: &gophercloud.Fault{
+				Type:    "OverQuota",
+				Message: "Quota exceeded for resources: ['port'].",
+			},
+		},
+		{
+			name: "octavia",
+			body: `{"faultcode": "Client", "faultstring": "Load Balancer abc is immutable and cannot be updated.", "debuginfo": null}`,
+			expected: &gophercloud.Fault{
+				Type:    "Client",
+				Message: "Load Balancer abc is immutable and cannot be updated.",
+			},
+		},
+		{
+			name: "ironic",
+			body: `{"error_message": "{\"faultstring\": \"Node abc could not be found.\", \"faultcode\": \"Client\", \"debuginfo\": null}"}`,
+			expected: &gophercloud.Fault{
+				Type:    "Client",
+				Message: "Node abc could not be found.",
+			},
+		},
+		{
+			name: "keystone",
+			body: `{"error": {"code": 401, "message": "The request you have made requires authentication.", "title": "Unauthorized"}}`,
+			expected: &gophercloud.Fault{
+				Type:    "Unauthorized",
+				Message: "The request you have made requires authentication.",
+				Code:    401,
+			},
+		},
+		{
+			name: "heat",
+			body: `{"explanation": "The resource could not be found.", "code": 404, "error": {"message": "The Stack (foo) could not be found.", "traceback": null, "type": "EntityNotFound"}, "title": "Not Found"}`,
+			expected: &gophercloud.Fault{
+				Type:    "EntityNotFound",
+				Message: "The Stack (foo) could not be found.",
+				Code:    404,
+			},
+		},
+		{
+			name: "designate",
+			body: `{"code": 409, "type": "duplicate_zone", "message": "Duplicate Zone", "request_id": "req-123"}`,
+			expected: &gophercloud.Fault{
+				Type:    "duplicate_zone",
+				Message: "Duplicate Zone",
+				Code:    409,
+			},
+		},
+		{
+			name: "placement",
+			body: `{"errors": [{"status": 404, "title": "Not Found", "detail": "No resource provider with uuid abc found", "code": "placement.undefined_code", "request_id": "req-123"}]}`,
+			expected: &gophercloud.Fault{
+				Type:    "Not Found",
+				Message: "No resource provider with uuid abc found",
+				Code:    404,
+				Details: "placement.undefined_code",
+			},
+		},
+		{
+			name:     "plain text",
+			body:     `404 Not Found`,
+			expected: nil,
+		},
+		{
+			name:     "unrelated JSON",
+			body:     `{"servers": []}`,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := gophercloud.ParseFault("", []byte(tt.body))
+			th.CheckDeepEquals(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestUnexpectedResponseCodeFault(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	fakeServer.Mux.HandleFunc("/servers", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"forbidden": {"message": "Quota exceeded for instances: Requested 1, but already used 10 of 10 instances", "code": 403}}`)
+	})
+
+	sc := &gophercloud.ServiceClient{
+		ProviderClient: &gophercloud.ProviderClient{},
+		Type:           "compute",
+	}
+	_, err := sc.Post(context.TODO(), fakeServer.Endpoint()+"servers", map[string]any{}, nil, nil)
+	th.AssertEquals(t, true, gophercloud.ResponseCodeIs(err, http.StatusForbidden))
+
+	var fault *gophercloud.Fault
+	th.AssertEquals(t, true, errors.As(err, &fault))
+	th.AssertEquals(t, "compute", fault.ServiceType)
+	th.AssertEquals(t, "forbidden", fault.Type)
+	th.AssertEquals(t, 403, fault.Code)
+	th.AssertEquals(t, "Quota exceeded for instances: Requested 1, but already used 10 of 10 instances", fault.Message)
+}
@@ -0,0 +1,102 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-Auth-Token", "secret-token")
+	h.Set("X-Subject-Token", "another-token")
+	h.Set("Content-Type", "application/json")
+
+	redacted := gophercloud.RedactHeaders(h)
+	th.AssertEquals(t, gophercloud.RedactedValue, redacted.Get("X-Auth-Token"))
+	th.AssertEquals(t, gophercloud.RedactedValue, redacted.Get("X-Subject-Token"))
+	th.AssertEquals(t, "application/json", redacted.Get("Content-Type"))
+	// the original headers must be left untouched
+	th.AssertEquals(t, "secret-token", h.Get("X-Auth-Token"))
+}
+
+func TestRedactJSONBody(t *testing.T) {
+	body := `{
+		"auth": {
+			"identity": {
+				"methods": ["password", "totp", "token", "application_credential"],
+				"password": {"user": {"name": "admin", "password": "s3cr3t"}},
+				"totp": {"user": {"id": "u1", "passcode": "123456"}},
+				"token": {"id": "tok-id"},
+				"application_credential": {"id": "ac-id", "secret": "ac-secret"}
+			}
+		},
+		"server": {"name": "test", "adminPass": "hunter2"}
+	}`
+
+	redacted := string(gophercloud.RedactJSONBody([]byte(body)))
+	for _, secret := range []string{"s3cr3t", "123456", "tok-id", "ac-secret", "hunter2"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("redacted body %s contains %q", redacted, secret)
+		}
+	}
+	for _, kept := range []string{"admin", "u1", "ac-id", "test"} {
+		if !strings.Contains(redacted, kept) {
+			t.Errorf("redacted body %s does not contain %q", redacted, kept)
+		}
+	}
+
+	th.AssertEquals(t, "<non-JSON body of 9 bytes>", string(gophercloud.RedactJSONBody([]byte("password!"))))
+	th.AssertEquals(t, "", string(gophercloud.RedactJSONBody(nil)))
+}
+
+func TestRequestLogging(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	fakeServer.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "fresh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Subject-Token", "issued-token")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"token": {"expires_at": "2030-01-01T00:00:00Z"}}`)
+	})
+
+	var buf bytes.Buffer
+	p := &gophercloud.ProviderClient{
+		Logger: slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+	p.SetToken("stale-token")
+	p.ReauthFunc = func(_ context.Context) error {
+		p.SetToken("fresh-token")
+		return nil
+	}
+
+	var actual map[string]any
+	_, err := p.Request(context.TODO(), "POST", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{
+		JSONBody:     map[string]any{"auth": map[string]any{"password": "s3cr3t"}},
+		JSONResponse: &actual,
+	})
+	th.AssertNoErr(t, err)
+
+	logs := buf.String()
+	for _, secret := range []string{"stale-token", "fresh-token", "issued-token", "s3cr3t"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("logs contain %q:\n%s", secret, logs)
+		}
+	}
+	for _, expected := range []string{`"msg":"sending request"`, `"msg":"reauthenticating"`, `"status":401`, `"status":201`, `"latency"`, `expires_at`} {
+		if !strings.Contains(logs, expected) {
+			t.Errorf("logs do not contain %s:\n%s", expected, logs)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/testhelper/client"
+)
+
+func recordingMiddleware(name string, calls *[]string) gophercloud.Middleware {
+	return func(next gophercloud.RequestHandler) gophercloud.RequestHandler {
+		return func(ctx context.Context, method, url string, opts *gophercloud.RequestOpts) (*http.Response, error) {
+			*calls = append(*calls, "before "+name)
+			resp, err := next(ctx, method, url, opts)
+			*calls = append(*calls, "after "+name)
+			return resp, err
+		}
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	fakeServer.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "X-Injected", "yes")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var calls []string
+	p := &gophercloud.ProviderClient{}
+	p.Middleware = []gophercloud.Middleware{
+		recordingMiddleware("first", &calls),
+		recordingMiddleware("second", &calls),
+		func(next gophercloud.RequestHandler) gophercloud.RequestHandler {
+			return func(ctx context.Context, method, url string, opts *gophercloud.RequestOpts) (*http.Response, error) {
+				if opts.MoreHeaders == nil {
+					opts.MoreHeaders = make(map[string]string)
+				}
+				opts.MoreHeaders["X-Injected"] = "yes"
+				return next(ctx, method, url, opts)
+			}
+		},
+	}
+
+	_, err := p.Request(context.TODO(), "GET", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, []string{"before first", "before second", "after second", "after first"}, calls)
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	injected := errors.New("injected fault")
+	p := &gophercloud.ProviderClient{}
+	p.Middleware = []gophercloud.Middleware{
+		func(next gophercloud.RequestHandler) gophercloud.RequestHandler {
+			return func(ctx context.Context, method, url string, opts *gophercloud.RequestOpts) (*http.Response, error) {
+				return nil, injected
+			}
+		},
+	}
+
+	_, err := p.Request(context.TODO(), "GET", "http://127.0.0.1:0/route", &gophercloud.RequestOpts{})
+	th.AssertErrIs(t, err, injected)
+}
+
+func TestMiddlewareServiceClientOverride(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	fakeServer.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var providerCalls, serviceCalls []string
+	p := &gophercloud.ProviderClient{}
+	p.Middleware = []gophercloud.Middleware{recordingMiddleware("provider", &providerCalls)}
+
+	sc := &gophercloud.ServiceClient{ProviderClient: p}
+	_, err := sc.Get(context.TODO(), fakeServer.Endpoint()+"route", nil, nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(providerCalls))
+
+	sc.Middleware = []gophercloud.Middleware{recordingMiddleware("service", &serviceCalls)}
+	_, err = sc.Get(context.TODO(), fakeServer.Endpoint()+"route", nil, nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(providerCalls))
+	th.AssertDeepEquals(t, []string{"before service", "after service"}, serviceCalls)
+
+	sc.Middleware = []gophercloud.Middleware{}
+	_, err = sc.Get(context.TODO(), fakeServer.Endpoint()+"route", nil, nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(providerCalls))
+	th.AssertEquals(t, 2, len(serviceCalls))
+}
+
+func TestMiddlewareRequestInfo(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	fakeServer.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "new-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	p := &gophercloud.ProviderClient{}
+	p.SetToken(client.TokenID)
+	p.ReauthFunc = func(_ context.Context) error {
+		p.SetToken("new-token")
+		return nil
+	}
+
+	var info gophercloud.RequestInfo
+	p.Middleware = []gophercloud.Middleware{
+		func(next gophercloud.RequestHandler) gophercloud.RequestHandler {
+			return func(ctx context.Context, method, url string, opts *gophercloud.RequestOpts) (*http.Response, error) {
+				resp, err := next(ctx, method, url, opts)
+				info = *gophercloud.RequestInfoFromContext(ctx)
+				return resp, err
+			}
+		},
+	}
+
+	sc := &gophercloud.ServiceClient{
+		ProviderClient: p,
+		Type:           "compute",
+		Microversion:   "2.79",
+	}
+	_, err := sc.Get(context.TODO(), fmt.Sprintf("%sroute", fakeServer.Endpoint()), nil, nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "compute", info.ServiceType)
+	th.AssertEquals(t, "2.79", info.Microversion)
+	th.AssertEquals(t, true, info.Reauthenticated)
+}
@@ -0,0 +1,101 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+func TestPollerBackoff(t *testing.T) {
+	var progress []gophercloud.PollProgress
+	poller := &gophercloud.Poller{
+		InitialInterval: time.Millisecond,
+		Multiplier:      2,
+		MaxInterval:     4 * time.Millisecond,
+		Progress: func(p gophercloud.PollProgress) {
+			progress = append(progress, p)
+		},
+	}
+
+	statuses := []string{"BUILD", "BUILD", "BUILD", "BUILD", "ACTIVE"}
+	attempt := 0
+	err := poller.Poll(context.TODO(), func(context.Context) (bool, string, error) {
+		status := statuses[attempt]
+		attempt++
+		return status == "ACTIVE", status, nil
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 5, attempt)
+
+	th.AssertEquals(t, 4, len(progress))
+	expectedIntervals := []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond}
+	for i, p := range progress {
+		th.AssertEquals(t, i+1, p.Attempt)
+		th.AssertEquals(t, "BUILD", p.Status)
+		th.AssertEquals(t, expectedIntervals[i], p.NextInterval)
+	}
+}
+
+func TestPollerTimeout(t *testing.T) {
+	poller := &gophercloud.Poller{
+		InitialInterval: time.Millisecond,
+		Timeout:         20 * time.Millisecond,
+	}
+	err := poller.Poll(context.TODO(), func(context.Context) (bool, string, error) {
+		return false, "BUILD", nil
+	})
+	th.AssertErrIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWaitForWithPoller(t *testing.T) {
+	ctx := gophercloud.WithPoller(context.TODO(), &gophercloud.Poller{
+		InitialInterval: time.Millisecond,
+	})
+
+	start := time.Now()
+	attempt := 0
+	err := gophercloud.WaitFor(ctx, func(context.Context) (bool, error) {
+		attempt++
+		return attempt == 3, nil
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, attempt)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the configured poller to be used, waited %s", elapsed)
+	}
+}
+
+func TestWaitForStatus(t *testing.T) {
+	ctx := gophercloud.WithPoller(context.TODO(), &gophercloud.Poller{
+		InitialInterval: time.Millisecond,
+	})
+	failureStatuses := []string{"ERROR"}
+
+	statuses := []string{"BUILD", "ACTIVE"}
+	attempt := 0
+	err := gophercloud.WaitForStatus(ctx, "id", "ACTIVE", failureStatuses, func(context.Context) (gophercloud.ResourceStatus, error) {
+		status := statuses[attempt]
+		attempt++
+		return gophercloud.ResourceStatus{Status: status}, nil
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, attempt)
+
+	err = gophercloud.WaitForStatus(ctx, "id", "ACTIVE", failureStatuses, func(context.Context) (gophercloud.ResourceStatus, error) {
+		return gophercloud.ResourceStatus{Status: "ERROR", Fault: "No valid host was found"}, nil
+	})
+	var failed gophercloud.ErrResourceFailed
+	th.AssertEquals(t, true, errors.As(err, &failed))
+	th.AssertEquals(t, "ERROR", failed.Status)
+	th.AssertEquals(t, "Resource id reached failure status ERROR: No valid host was found", err.Error())
+
+	// the awaited status takes precedence over the failure statuses
+	err = gophercloud.WaitForStatus(ctx, "id", "ERROR", failureStatuses, func(context.Context) (gophercloud.ResourceStatus, error) {
+		return gophercloud.ResourceStatus{Status: "ERROR"}, nil
+	})
+	th.AssertNoErr(t, err)
+}
@@ -710,3 +710,18 @@ func TestRequestWrongOkCode(t *testing.T) {
 		t.Fatalf("expected error type gophercloud.ErrUnexpectedResponseCode but got %T", err)
 	}
 }
+
+func TestRequestOptsRewindBody(t *testing.T) {
+	var opts *gophercloud.RequestOpts
+	th.AssertEquals(t, true, opts.RewindBody())
+	th.AssertEquals(t, true, (&gophercloud.RequestOpts{}).RewindBody())
+
+	body := strings.NewReader("body")
+	_, err := io.ReadAll(body)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, (&gophercloud.RequestOpts{RawBody: body}).RewindBody())
+	th.AssertEquals(t, 4, body.Len())
+
+	pipe, _ := io.Pipe()
+	th.AssertEquals(t, false, (&gophercloud.RequestOpts{RawBody: pipe}).RewindBody())
+}
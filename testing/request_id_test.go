@@ -0,0 +1,71 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+func TestRequestIDFromHeader(t *testing.T) {
+	h := http.Header{}
+	th.AssertEquals(t, "", gophercloud.RequestIDFromHeader(h))
+
+	h.Set("X-Compute-Request-Id", "req-compute")
+	th.AssertEquals(t, "req-compute", gophercloud.RequestIDFromHeader(h))
+
+	h.Set("X-Openstack-Request-Id", "req-openstack")
+	th.AssertEquals(t, "req-openstack", gophercloud.RequestIDFromHeader(h))
+}
+
+func TestNewGlobalRequestID(t *testing.T) {
+	re := regexp.MustCompile(`^req-[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	id := gophercloud.NewGlobalRequestID()
+	if !re.MatchString(id) {
+		t.Errorf("invalid global request ID %q", id)
+	}
+	th.AssertEquals(t, false, id == gophercloud.NewGlobalRequestID())
+}
+
+func TestGlobalRequestIDPropagation(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	fakeServer.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "X-OpenStack-Request-ID", "req-4fd9ad4a-5e3e-4ed6-9e3c-e5d3b9c1d0f2")
+		w.Header().Set("X-Openstack-Request-Id", "req-local")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	p := &gophercloud.ProviderClient{}
+	ctx := gophercloud.WithGlobalRequestID(context.TODO(), "req-4fd9ad4a-5e3e-4ed6-9e3c-e5d3b9c1d0f2")
+	th.AssertEquals(t, "req-4fd9ad4a-5e3e-4ed6-9e3c-e5d3b9c1d0f2", gophercloud.GlobalRequestIDFromContext(ctx))
+
+	resp, err := p.Request(ctx, "GET", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{})
+	th.AssertNoErr(t, err)
+
+	r := gophercloud.Result{Header: resp.Header}
+	th.AssertEquals(t, "req-local", r.RequestID())
+}
+
+func TestRequestIDOnError(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	fakeServer.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Compute-Request-Id", "req-failed")
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	p := &gophercloud.ProviderClient{}
+	_, err := p.Request(context.TODO(), "GET", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{})
+
+	var codeError gophercloud.ErrUnexpectedResponseCode
+	th.AssertEquals(t, true, errors.As(err, &codeError))
+	th.AssertEquals(t, "req-failed", codeError.RequestID)
+
+	r := gophercloud.Result{Err: err}
+	th.AssertEquals(t, "req-failed", r.RequestID())
+}
@@ -2,7 +2,9 @@ package testing
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/gophercloud/gophercloud/v2"
 	th "github.com/gophercloud/gophercloud/v2/testhelper"
@@ -240,3 +242,45 @@ func TestUnmarshalSliceOfNamedStructs(t *testing.T) {
 	th.AssertEquals(t, "", actual[1].TestPerson.Name)
 	th.AssertEquals(t, "", actual[1].TestPersonExt.Location)
 }
+
+func TestExtractAs(t *testing.T) {
+	var dejson any
+	err := json.Unmarshal([]byte(singleResponse), &dejson)
+	th.AssertNoErr(t, err)
+
+	actual, err := gophercloud.ExtractAs[TestPersonWithExtensions](gophercloud.Result{Body: dejson}, "person")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "Bill unmarshalled", actual.Name)
+	th.AssertEquals(t, "bill@example.com", actual.Email)
+	th.AssertEquals(t, "Canada unmarshalled", actual.Location)
+
+	body, err := gophercloud.ExtractAs[map[string]any](gophercloud.Result{Body: dejson}, "")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(*body))
+
+	expectedErr := fmt.Errorf("request failed")
+	_, err = gophercloud.ExtractAs[TestPerson](gophercloud.Result{Err: expectedErr}, "person")
+	th.AssertEquals(t, expectedErr, err)
+}
+
+func TestExtractSliceAs(t *testing.T) {
+	var dejson any
+	err := json.Unmarshal([]byte(multiResponse), &dejson)
+	th.AssertNoErr(t, err)
+
+	actual, err := gophercloud.ExtractSliceAs[TestPersonWithExtensions](gophercloud.Result{Body: dejson}, "people")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(actual))
+	th.AssertEquals(t, "Bill unmarshalled", actual[0].Name)
+	th.AssertEquals(t, "Mexico unmarshalled", actual[1].Location)
+
+	var timeResponse any
+	err = json.Unmarshal([]byte(`{"events": [{"at": "2024-01-02T03:04:05"}]}`), &timeResponse)
+	th.AssertNoErr(t, err)
+	type event struct {
+		At gophercloud.JSONRFC3339NoZ `json:"at"`
+	}
+	events, err := gophercloud.ExtractSliceAs[event](gophercloud.Result{Body: timeResponse}, "events")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2024, time.Time(events[0].At).Year())
+}
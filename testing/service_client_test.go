@@ -2,6 +2,7 @@ package testing
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
@@ -33,3 +34,110 @@ func TestMoreHeaders(t *testing.T) {
 	th.AssertNoErr(t, err)
 	th.AssertEquals(t, resp.Request.Header.Get("custom"), "header")
 }
+
+func TestCheckMicroversion(t *testing.T) {
+	c := &gophercloud.ServiceClient{Type: "compute"}
+	th.AssertNoErr(t, c.CheckMicroversion(context.TODO(), "2.26"))
+
+	c.Microversion = "2.26"
+	th.AssertNoErr(t, c.CheckMicroversion(context.TODO(), "2.26"))
+	th.AssertNoErr(t, c.CheckMicroversion(context.TODO(), "2.6"))
+
+	err := c.CheckMicroversion(context.TODO(), "2.100")
+	var unsupported gophercloud.ErrMicroversionUnsupported
+	th.AssertEquals(t, true, errors.As(err, &unsupported))
+	th.AssertEquals(t, "2.100", unsupported.Required)
+	th.AssertEquals(t, "2.26", unsupported.Actual)
+
+	ctx := gophercloud.WithMicroversion(context.TODO(), "compute", "2.100")
+	th.AssertNoErr(t, c.CheckMicroversion(ctx, "2.100"))
+
+	c.Microversion = "latest"
+	th.AssertNoErr(t, c.CheckMicroversion(context.TODO(), "2.100"))
+}
+
+func TestMicroversionOverride(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	fakeServer.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Nova-Version", r.Header.Get("X-OpenStack-Nova-API-Version"))
+		w.Header().Set("Api-Version", r.Header.Get("OpenStack-API-Version"))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	c := &gophercloud.ServiceClient{
+		ProviderClient: new(gophercloud.ProviderClient),
+		Type:           "compute",
+		Microversion:   "2.1",
+	}
+	url := fakeServer.Endpoint() + "route"
+
+	resp, err := c.Get(context.TODO(), url, nil, nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "2.1", resp.Header.Get("Nova-Version"))
+	th.AssertEquals(t, "compute 2.1", resp.Header.Get("Api-Version"))
+
+	ctx := gophercloud.WithMicroversion(context.TODO(), "compute", "2.79")
+	resp, err = c.Get(ctx, url, nil, nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "2.79", resp.Header.Get("Nova-Version"))
+	th.AssertEquals(t, "compute 2.79", resp.Header.Get("Api-Version"))
+
+	resp, err = c.Get(ctx, url, nil, &gophercloud.RequestOpts{Microversion: "2.90"})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "2.90", resp.Header.Get("Nova-Version"))
+
+	// the service client is left untouched
+	th.AssertEquals(t, "2.1", c.Microversion)
+
+	c.Microversion = ""
+	resp, err = c.Get(ctx, url, nil, nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "2.79", resp.Header.Get("Nova-Version"))
+}
+
+func TestMicroversionContextServiceType(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	fakeServer.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", r.Header.Get("OpenStack-API-Version"))
+		w.WriteHeader(http.StatusOK)
+	})
+	url := fakeServer.Endpoint() + "route"
+
+	compute := &gophercloud.ServiceClient{
+		ProviderClient: new(gophercloud.ProviderClient),
+		Type:           "compute",
+	}
+	blockStorage := &gophercloud.ServiceClient{
+		ProviderClient: new(gophercloud.ProviderClient),
+		Type:           "block-storage",
+		Microversion:   "3.0",
+	}
+	identity := &gophercloud.ServiceClient{
+		ProviderClient: new(gophercloud.ProviderClient),
+		Type:           "identity",
+	}
+
+	ctx := gophercloud.WithMicroversion(context.TODO(), "compute", "2.79")
+
+	resp, err := compute.Get(ctx, url, nil, nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "compute 2.79", resp.Header.Get("Api-Version"))
+
+	// the microversion of compute is not sent to other services
+	resp, err = blockStorage.Get(ctx, url, nil, nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "volume 3.0", resp.Header.Get("Api-Version"))
+
+	resp, err = identity.Get(ctx, url, nil, nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "", resp.Header.Get("Api-Version"))
+
+	// aliases are resolved, and the microversions of other services are kept
+	ctx = gophercloud.WithMicroversion(ctx, "volume", "3.60")
+	resp, err = blockStorage.Get(ctx, url, nil, nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "volume 3.60", resp.Header.Get("Api-Version"))
+	th.AssertEquals(t, "2.79", gophercloud.MicroversionFromContext(ctx, "compute"))
+}
@@ -0,0 +1,122 @@
+package testing
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+)
+
+type expiringAuthResult struct {
+	tokenID   string
+	expiresAt time.Time
+}
+
+func (r expiringAuthResult) ExtractTokenID() (string, error) {
+	return r.tokenID, nil
+}
+
+func (r expiringAuthResult) ExtractExpiresAt() (time.Time, error) {
+	return r.expiresAt, nil
+}
+
+func TestTokenExpiresAt(t *testing.T) {
+	p := &gophercloud.ProviderClient{}
+	p.UseTokenLock()
+
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := p.SetTokenAndAuthResult(expiringAuthResult{"token", expiresAt})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, expiresAt, p.TokenExpiresAt())
+
+	other := &gophercloud.ProviderClient{}
+	other.CopyTokenFrom(p)
+	th.AssertEquals(t, expiresAt, other.TokenExpiresAt())
+
+	p.SetToken("manual")
+	th.AssertEquals(t, true, p.TokenExpiresAt().IsZero())
+}
+
+func TestProactiveTokenRenewal(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	fakeServer.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "renewed" {
+			t.Errorf("request sent with token %q", r.Header.Get("X-Auth-Token"))
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var reauths atomic.Int32
+	p := &gophercloud.ProviderClient{
+		TokenRenewalWindow: 5 * time.Minute,
+	}
+	p.UseTokenLock()
+	th.AssertNoErr(t, p.SetTokenAndAuthResult(expiringAuthResult{"expiring", time.Now().Add(time.Minute)}))
+	p.ReauthFunc = func(_ context.Context) error {
+		reauths.Add(1)
+		return p.SetTokenAndAuthResult(expiringAuthResult{"renewed", time.Now().Add(time.Hour)})
+	}
+
+	wg := new(sync.WaitGroup)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := p.Request(context.TODO(), "GET", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{})
+			th.CheckNoErr(t, err)
+		}()
+	}
+	wg.Wait()
+	th.AssertEquals(t, int32(1), reauths.Load())
+}
+
+func TestNoProactiveTokenRenewalOutsideWindow(t *testing.T) {
+	fakeServer := th.SetupHTTP()
+	defer fakeServer.Teardown()
+	fakeServer.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	p := &gophercloud.ProviderClient{
+		TokenRenewalWindow: 5 * time.Minute,
+	}
+	th.AssertNoErr(t, p.SetTokenAndAuthResult(expiringAuthResult{"valid", time.Now().Add(time.Hour)}))
+	p.ReauthFunc = func(_ context.Context) error {
+		t.Errorf("unexpected reauthentication")
+		return nil
+	}
+
+	_, err := p.Request(context.TODO(), "GET", fakeServer.Endpoint()+"route", &gophercloud.RequestOpts{})
+	th.AssertNoErr(t, err)
+}
+
+func TestStartTokenRenewal(t *testing.T) {
+	renewed := make(chan struct{}, 1)
+	p := &gophercloud.ProviderClient{
+		TokenRenewalWindow: time.Hour,
+	}
+	p.UseTokenLock()
+	th.AssertNoErr(t, p.SetTokenAndAuthResult(expiringAuthResult{"expiring", time.Now().Add(time.Hour + 50*time.Millisecond)}))
+	p.ReauthFunc = func(_ context.Context) error {
+		err := p.SetTokenAndAuthResult(expiringAuthResult{"renewed", time.Now().Add(24 * time.Hour)})
+		renewed <- struct{}{}
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.StartTokenRenewal(ctx)
+
+	select {
+	case <-renewed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("token was not renewed in the background")
+	}
+	th.AssertEquals(t, "renewed", p.Token())
+}
@@ -0,0 +1,90 @@
+package gophercloud
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// CachedToken is a token stored in a TokenCache, along with the body of the
+// response to the request that issued it. The body contains the service
+// catalog, so that a ProviderClient can locate endpoints from a cached token
+// without contacting the identity service.
+type CachedToken struct {
+	// TokenID is the ID of the token.
+	TokenID string `json:"token_id"`
+
+	// ExpiresAt is the time at which the token expires.
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Body is the body of the response to the token creation request.
+	Body json.RawMessage `json:"body"`
+}
+
+// Valid reports whether the token is still valid for at least the given
+// duration.
+func (t *CachedToken) Valid(margin time.Duration) bool {
+	return t != nil && t.TokenID != "" && time.Until(t.ExpiresAt) > margin
+}
+
+// TokenCache stores tokens so that they can be reused by other
+// ProviderClients, possibly in other processes, authenticating with the same
+// options. Set AuthOptions.TokenCache to use one.
+type TokenCache interface {
+	// Load returns the token stored under the given key, or nil if there
+	// is none.
+	Load(ctx context.Context, key string) (*CachedToken, error)
+
+	// Store stores a token under the given key, replacing any token
+	// previously stored under it.
+	Store(ctx context.Context, key string, token *CachedToken) error
+
+	// Delete removes the token stored under the given key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// TokenCacheKey returns the key under which tokens obtained with the options
+// are stored in a TokenCache. The key is a hash of the identity endpoint, the
+// credentials and the scope, so that tokens are only reused with the very
+// same credentials, and that it does not reveal them.
+func (opts AuthOptions) TokenCacheKey() string {
+	var scope AuthScope
+	if opts.Scope != nil {
+		scope = *opts.Scope
+	}
+
+	parts := []string{
+		opts.IdentityEndpoint,
+		opts.Username,
+		opts.UserID,
+		opts.Password,
+		opts.DomainID,
+		opts.DomainName,
+		opts.TenantID,
+		opts.TenantName,
+		opts.TokenID,
+		opts.ApplicationCredentialID,
+		opts.ApplicationCredentialName,
+		opts.ApplicationCredentialSecret,
+		scope.ProjectID,
+		scope.ProjectName,
+		scope.DomainID,
+		scope.DomainName,
+		scope.TrustID,
+	}
+	if scope.System {
+		parts = append(parts, "system")
+	}
+
+	h := sha256.New()
+	for _, p := range parts {
+		// length-prefix every part so that they cannot be confused with
+		// one another
+		h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(p))))
+		h.Write([]byte(p))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
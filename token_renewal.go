@@ -0,0 +1,108 @@
+package gophercloud
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// minTokenRenewalRetry is the minimum delay between two attempts of the
+// background token renewal, so that a failing ReauthFunc is not called in a
+// tight loop.
+const minTokenRenewalRetry = 10 * time.Second
+
+// TokenExpiresAt safely reads the expiry of the current token. It returns the
+// zero time if the expiry is unknown, e.g. because the token was set with
+// SetToken or because the AuthResult does not implement ExpiringAuthResult.
+func (client *ProviderClient) TokenExpiresAt() time.Time {
+	if client.mut != nil {
+		client.mut.RLock()
+		defer client.mut.RUnlock()
+	}
+	return client.tokenExpiresAt
+}
+
+// tokenExpiring reports whether the current token expires within the given
+// window, and returns the current token.
+func (client *ProviderClient) tokenExpiring(window time.Duration) (bool, string) {
+	if client.mut != nil {
+		client.mut.RLock()
+		defer client.mut.RUnlock()
+	}
+	if client.tokenExpiresAt.IsZero() {
+		return false, client.TokenID
+	}
+	return time.Until(client.tokenExpiresAt) < window, client.TokenID
+}
+
+// renewTokenIfExpiring reauthenticates if the token expires within
+// TokenRenewalWindow. A failed renewal is not fatal: the request is sent with
+// the current token and the usual reauthentication on 401 applies.
+func (client *ProviderClient) renewTokenIfExpiring(ctx context.Context) {
+	if client.TokenRenewalWindow <= 0 || client.ReauthFunc == nil || client.IsThrowaway() {
+		return
+	}
+	expiring, token := client.tokenExpiring(client.TokenRenewalWindow)
+	if !expiring {
+		return
+	}
+
+	client.logEvent(ctx, "renewing token before expiry", "", "")
+	if err := client.Reauthenticate(ctx, token); err != nil {
+		client.logEvent(ctx, "failed to renew token before expiry", "", "", slog.String("error", err.Error()))
+	}
+}
+
+// StartTokenRenewal starts renewing the token of the client in the
+// background, TokenRenewalWindow before it expires, until ctx is cancelled.
+// If TokenRenewalWindow is not set, the token is renewed one minute before it
+// expires. Nothing is done if the client has no ReauthFunc.
+//
+// Failed renewals are retried, with a delay of at least ten seconds between
+// two attempts.
+func (client *ProviderClient) StartTokenRenewal(ctx context.Context) {
+	if client.ReauthFunc == nil {
+		return
+	}
+	window := client.TokenRenewalWindow
+	if window <= 0 {
+		window = time.Minute
+	}
+
+	go func() {
+		for {
+			delay := minTokenRenewalRetry
+			if expiresAt := client.TokenExpiresAt(); !expiresAt.IsZero() {
+				delay = max(time.Until(expiresAt)-window, 0)
+			}
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			expiring, token := client.tokenExpiring(window)
+			if !expiring {
+				continue
+			}
+			if err := client.Reauthenticate(ctx, token); err != nil {
+				client.logEvent(ctx, "failed to renew token in the background", "", "", slog.String("error", err.Error()))
+			}
+
+			// Wait before trying again if the renewal failed, or if the new
+			// token is shorter-lived than the renewal window.
+			if expiring, _ := client.tokenExpiring(window); expiring {
+				timer := time.NewTimer(minTokenRenewalRetry)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,26 @@
+/*
+Package tokencache provides a file-backed implementation of
+gophercloud.TokenCache, which allows tokens to be reused across process
+invocations, e.g. by command line tools.
+
+Each token is stored in its own file, readable only by the current user,
+along with the service catalog it was issued with. Expired tokens are removed
+when they are loaded.
+
+Example of authenticating with a token cache:
+
+	cache, err := tokencache.NewFileCache("")
+	if err != nil {
+		panic(err)
+	}
+
+	opts, err := openstack.AuthOptionsFromEnv()
+	if err != nil {
+		panic(err)
+	}
+	opts.AllowReauth = true
+	opts.TokenCache = cache
+
+	provider, err := openstack.AuthenticatedClient(context.TODO(), opts)
+*/
+package tokencache
@@ -0,0 +1,141 @@
+package tokencache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// validKey matches the keys accepted by FileCache, so that a key cannot be
+// used to escape the cache directory.
+var validKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// FileCache is a gophercloud.TokenCache which stores each token in a file of
+// a directory.
+type FileCache struct {
+	// Dir is the directory in which the tokens are stored.
+	Dir string
+}
+
+// NewFileCache returns a FileCache storing tokens in the given directory,
+// which is created if needed. If dir is empty, the "gophercloud/tokens"
+// directory of the user's cache directory (see os.UserCacheDir) is used.
+func NewFileCache(dir string) (*FileCache, error) {
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(cacheDir, "gophercloud", "tokens")
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileCache{Dir: dir}, nil
+}
+
+func (c *FileCache) path(key string) (string, error) {
+	if !validKey.MatchString(key) {
+		return "", gophercloud.ErrInvalidInput{
+			ErrMissingInput: gophercloud.ErrMissingInput{Argument: "key"},
+			Value:           key,
+		}
+	}
+	return filepath.Join(c.Dir, key+".json"), nil
+}
+
+// Load implements gophercloud.TokenCache. It returns nil if no token is
+// stored under the key, or if the stored token has expired.
+func (c *FileCache) Load(_ context.Context, key string) (*gophercloud.CachedToken, error) {
+	path, err := c.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var t gophercloud.CachedToken
+	if err := json.Unmarshal(b, &t); err != nil || !t.Valid(0) {
+		// a corrupt or expired token is useless
+		_ = os.Remove(path)
+		return nil, nil
+	}
+	return &t, nil
+}
+
+// Store implements gophercloud.TokenCache. The token is written atomically,
+// to a file readable only by the current user.
+func (c *FileCache) Store(_ context.Context, key string, token *gophercloud.CachedToken) error {
+	path, err := c.path(key)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(c.Dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Delete implements gophercloud.TokenCache.
+func (c *FileCache) Delete(_ context.Context, key string) error {
+	path, err := c.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Prune removes the expired tokens from the cache.
+func (c *FileCache) Prune(ctx context.Context) error {
+	entries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || filepath.Ext(name) != ".json" {
+			continue
+		}
+		// Load removes expired tokens
+		if _, err := c.Load(ctx, name[:len(name)-len(".json")]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
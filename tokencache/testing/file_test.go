@@ -0,0 +1,96 @@
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	th "github.com/gophercloud/gophercloud/v2/testhelper"
+	"github.com/gophercloud/gophercloud/v2/tokencache"
+)
+
+func TestFileCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tokens")
+	cache, err := tokencache.NewFileCache(dir)
+	th.AssertNoErr(t, err)
+
+	key := gophercloud.AuthOptions{Username: "me", Password: "secret"}.TokenCacheKey()
+
+	token, err := cache.Load(context.TODO(), key)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, token == nil)
+
+	expected := &gophercloud.CachedToken{
+		TokenID:   "0123456789",
+		ExpiresAt: time.Now().Add(time.Hour).UTC().Truncate(time.Second),
+		Body:      json.RawMessage(`{"token":{"catalog":[]}}`),
+	}
+	th.AssertNoErr(t, cache.Store(context.TODO(), key, expected))
+
+	info, err := os.Stat(filepath.Join(dir, key+".json"))
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, os.FileMode(0o600), info.Mode().Perm())
+
+	token, err = cache.Load(context.TODO(), key)
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, expected, token)
+
+	th.AssertNoErr(t, cache.Delete(context.TODO(), key))
+	token, err = cache.Load(context.TODO(), key)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, token == nil)
+
+	// deleting a missing token is not an error
+	th.AssertNoErr(t, cache.Delete(context.TODO(), key))
+}
+
+func TestFileCacheExpired(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := tokencache.NewFileCache(dir)
+	th.AssertNoErr(t, err)
+
+	th.AssertNoErr(t, cache.Store(context.TODO(), "expired", &gophercloud.CachedToken{
+		TokenID:   "0123456789",
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}))
+
+	th.AssertNoErr(t, cache.Prune(context.TODO()))
+	_, err = os.Stat(filepath.Join(dir, "expired.json"))
+	th.AssertEquals(t, true, os.IsNotExist(err))
+}
+
+func TestFileCacheInvalidKey(t *testing.T) {
+	cache, err := tokencache.NewFileCache(t.TempDir())
+	th.AssertNoErr(t, err)
+
+	_, err = cache.Load(context.TODO(), "../escape")
+	th.AssertErr(t, err)
+}
+
+func TestTokenCacheKey(t *testing.T) {
+	opts := gophercloud.AuthOptions{
+		IdentityEndpoint: "http://keystone:5000/v3",
+		Username:         "me",
+		Password:         "secret",
+		Scope:            &gophercloud.AuthScope{ProjectName: "project"},
+	}
+	key := opts.TokenCacheKey()
+	th.AssertEquals(t, key, opts.TokenCacheKey())
+
+	other := opts
+	other.Scope = &gophercloud.AuthScope{ProjectName: "other"}
+	th.AssertEquals(t, false, key == other.TokenCacheKey())
+
+	other = opts
+	other.Password = "secret2"
+	th.AssertEquals(t, false, key == other.TokenCacheKey())
+
+	// the passcode changes every time and must not invalidate the cache
+	other = opts
+	other.Passcode = "123456"
+	th.AssertEquals(t, key, other.TokenCacheKey())
+}
@@ -6,7 +6,6 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
-	"time"
 )
 
 // NormalizePathURL is used to convert rawPath to a fqdn, using basePath as
@@ -84,23 +83,12 @@ func RemainingKeys(s any, m map[string]any) (extras map[string]any) {
 // This is useful to wait for a resource to transition to a certain state.
 // Resource packages will wrap this in a more convenient function that's
 // specific to a certain resource, but it can also be useful on its own.
+//
+// The polling interval and timeout can be configured by setting a Poller in
+// the context with WithPoller.
 func WaitFor(ctx context.Context, predicate func(context.Context) (bool, error)) error {
-	if done, err := predicate(ctx); done || err != nil {
-		return err
-	}
-
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			if done, err := predicate(ctx); done || err != nil {
-				return err
-			}
-
-		case <-ctx.Done():
-			return ctx.Err()
-		}
-	}
+	return PollerFromContext(ctx).Poll(ctx, func(ctx context.Context) (bool, string, error) {
+		done, err := predicate(ctx)
+		return done, "", err
+	})
 }